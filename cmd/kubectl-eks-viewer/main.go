@@ -16,6 +16,6 @@ func main() {
 
 	command := cmd.NewCmd(streams)
 	if err := command.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(cmd.ExitCode(err))
 	}
 }
@@ -2,13 +2,17 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/eks/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/printers"
@@ -19,6 +23,8 @@ type AccessEntry = types.AccessEntry
 type AccessEntryList struct {
 	metav1.TypeMeta
 	Items []AccessEntry
+	// clusters are the clusters of the items when viewing several contexts
+	clusters []clusterRef
 }
 
 // Implement runtime.Object interface
@@ -30,10 +36,11 @@ func (a *AccessEntryList) DeepCopyObject() runtime.Object {
 	return &AccessEntryList{
 		TypeMeta: a.TypeMeta,
 		Items:    append([]AccessEntry(nil), a.Items...),
+		clusters: append([]clusterRef(nil), a.clusters...),
 	}
 }
 
-func NewAccessEntryPrinter(client *EKSClient) printers.ResourcePrinter {
+func NewAccessEntryPrinter(client *EKSClient, options printers.PrintOptions) printers.ResourcePrinter {
 	return printers.ResourcePrinterFunc(func(obj runtime.Object, w io.Writer) error {
 		list, ok := obj.(*AccessEntryList)
 		if !ok {
@@ -49,60 +56,262 @@ func NewAccessEntryPrinter(client *EKSClient) printers.ResourcePrinter {
 				{Name: "ACCESS ENTRY PRINCIPAL ARN", Type: "string"},
 				{Name: "KUBERNETES GROUPS", Type: "string"},
 				{Name: "ACCESS POLICIES", Type: "string"},
+				{Name: "TYPE", Type: "string"},
+				{Name: "USERNAME", Type: "string"},
+				{Name: "CREATED AT", Type: "string", Priority: 1},
 			},
 		}
 
-		for _, item := range list.Items {
-			// Get associated policies for each access entry
-			policies, err := client.client.ListAssociatedAccessPolicies(context.Background(), &eks.ListAssociatedAccessPoliciesInput{
-				ClusterName:  client.clusterName,
-				PrincipalArn: item.PrincipalArn,
-			})
-			if err != nil {
-				return err
-			}
+		// An entry whose policies cannot be listed is still printed and
+		// reported afterwards
+		policiesByEntry, policiesErr := listAccessEntryListPolicies(context.Background(), client, list)
+		if policiesErr != nil && !isPartialError(policiesErr) {
+			return policiesErr
+		}
 
-			var policyARNs []string
-			for _, policy := range policies.AssociatedAccessPolicies {
-				policyARNs = append(policyARNs, *policy.PolicyArn)
+		for i, item := range list.Items {
+			accessPolicies := "<error>"
+			if policies, ok := policiesByEntry[i]; ok {
+				var formatted []string
+				for _, policy := range policies {
+					formatted = append(formatted, formatAccessPolicy(policy))
+				}
+				accessPolicies = strings.Join(formatted, ",")
 			}
 
 			table.Rows = append(table.Rows, metav1.TableRow{
 				Cells: []interface{}{
 					*item.PrincipalArn,
 					strings.Join(item.KubernetesGroups, ","),
-					strings.Join(policyARNs, ","),
+					accessPolicies,
+					stringOrNone(item.Type),
+					stringOrNone(item.Username),
+					tableTime(item.CreatedAt),
 				},
 			})
 		}
 
-		return printTable(w, table, "access-entries")
+		addClusterColumns(table, list.clusters)
+		addTagsColumn(table, options, func(i int) map[string]string { return list.Items[i].Tags })
+		if err := printTable(w, table, "access-entries", options); err != nil {
+			return err
+		}
+		return policiesErr
 	})
 }
 
+// listAccessEntryListPolicies lists the associated policies of the items of
+// list, by index, with client or, when viewing several contexts, with the
+// client of the cluster of each item. Items whose policies could not be
+// listed are missing.
+func listAccessEntryListPolicies(ctx context.Context, client *EKSClient, list *AccessEntryList) (map[int][]types.AssociatedAccessPolicy, error) {
+	// The items of each client, in the order of their clusters
+	var clients []*EKSClient
+	indexes := map[*EKSClient][]int{}
+	for i := range list.Items {
+		entryClient := client
+		if list.clusters != nil {
+			entryClient = list.clusters[i].client
+		}
+		if entryClient == nil {
+			return nil, fmt.Errorf("no EKS client to list the access policies of %s", aws.ToString(list.Items[i].PrincipalArn))
+		}
+		if _, ok := indexes[entryClient]; !ok {
+			clients = append(clients, entryClient)
+		}
+		indexes[entryClient] = append(indexes[entryClient], i)
+	}
+
+	type clientPolicies struct {
+		policies map[string][]types.AssociatedAccessPolicy
+		err      error
+	}
+	results := make([]clientPolicies, len(clients))
+	var wg sync.WaitGroup
+	for c, entryClient := range clients {
+		entries := make([]AccessEntry, 0, len(indexes[entryClient]))
+		for _, i := range indexes[entryClient] {
+			entries = append(entries, list.Items[i])
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			policies, err := entryClient.listAccessEntryPolicies(ctx, entries)
+			results[c] = clientPolicies{policies: policies, err: err}
+		}()
+	}
+	wg.Wait()
+
+	policiesByEntry := map[int][]types.AssociatedAccessPolicy{}
+	var err error
+	for c, entryClient := range clients {
+		result := results[c]
+		if result.err != nil {
+			var partial *PartialError
+			if !errors.As(result.err, &partial) {
+				return nil, result.err
+			}
+			for _, item := range partial.Errors {
+				err = withItemError(err, item)
+			}
+		}
+		for _, i := range indexes[entryClient] {
+			if policies, ok := result.policies[aws.ToString(list.Items[i].PrincipalArn)]; ok {
+				policiesByEntry[i] = policies
+			}
+		}
+	}
+	return policiesByEntry, err
+}
+
+// formatAccessPolicy formats a policy ARN followed by its access scope, e.g.
+// .../AmazonEKSViewPolicy(cluster) or .../AmazonEKSEditPolicy(dev,staging).
+func formatAccessPolicy(policy types.AssociatedAccessPolicy) string {
+	arn := aws.ToString(policy.PolicyArn)
+	if policy.AccessScope == nil {
+		return arn
+	}
+	if policy.AccessScope.Type == types.AccessScopeTypeNamespace {
+		return fmt.Sprintf("%s(%s)", arn, strings.Join(policy.AccessScope.Namespaces, ","))
+	}
+	return fmt.Sprintf("%s(%s)", arn, policy.AccessScope.Type)
+}
+
 func (c *EKSClient) ListAccessEntries(ctx context.Context) ([]AccessEntry, error) {
 	input := &eks.ListAccessEntriesInput{
 		ClusterName: c.clusterName,
 	}
 
-	result, err := c.client.ListAccessEntries(ctx, input)
+	var principalARNs []string
+	paginator := eks.NewListAccessEntriesPaginator(c.client, input)
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		principalARNs = append(principalARNs, result.AccessEntries...)
+	}
+
+	principalARNs = filterNames(c.nameFilter, principalARNs, stringNames)
+	return describeAll(ctx, c.pool, principalARNs, stringName, c.DescribeAccessEntry)
+}
+
+func (c *EKSClient) DescribeAccessEntry(ctx context.Context, principalARN string) (AccessEntry, error) {
+	// Get Kubernetes groups
+	entry, err := c.client.DescribeAccessEntry(ctx, &eks.DescribeAccessEntryInput{
+		ClusterName:  c.clusterName,
+		PrincipalArn: &principalARN,
+	})
 	if err != nil {
-		return nil, err
+		return AccessEntry{}, err
+	}
+
+	return *entry.AccessEntry, nil
+}
+
+func (c *EKSClient) ListAssociatedAccessPolicies(ctx context.Context, principalARN *string) ([]types.AssociatedAccessPolicy, error) {
+	input := &eks.ListAssociatedAccessPoliciesInput{
+		ClusterName:  c.clusterName,
+		PrincipalArn: principalARN,
+	}
+
+	var policies []types.AssociatedAccessPolicy
+	paginator := eks.NewListAssociatedAccessPoliciesPaginator(c.client, input)
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, result.AssociatedAccessPolicies...)
 	}
 
-	var accessEntries []AccessEntry
-	for _, principalARN := range result.AccessEntries {
-		// Get Kubernetes groups
-		entry, err := c.client.DescribeAccessEntry(ctx, &eks.DescribeAccessEntryInput{
-			ClusterName:  c.clusterName,
-			PrincipalArn: &principalARN,
-		})
+	return policies, nil
+}
+
+// ListAccessPolicies lists the access policies EKS provides.
+func (c *EKSClient) ListAccessPolicies(ctx context.Context) ([]types.AccessPolicy, error) {
+	var policies []types.AccessPolicy
+	paginator := eks.NewListAccessPoliciesPaginator(c.client, &eks.ListAccessPoliciesInput{})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
 		if err != nil {
 			return nil, err
 		}
+		policies = append(policies, result.AccessPolicies...)
+	}
+	return policies, nil
+}
+
+// listAccessEntryPolicies lists the policies associated with each entry, by
+// principal ARN. Entries whose policies cannot be listed are returned in a
+// *PartialError.
+func (c *EKSClient) listAccessEntryPolicies(ctx context.Context, entries []AccessEntry) (map[string][]types.AssociatedAccessPolicy, error) {
+	type entryPolicies struct {
+		policies []types.AssociatedAccessPolicy
+		err      error
+	}
+	policiesByEntry, err := mapConcurrent(ctx, c.pool, entries, func(ctx context.Context, entry AccessEntry) (entryPolicies, error) {
+		policies, err := c.ListAssociatedAccessPolicies(ctx, entry.PrincipalArn)
+		return entryPolicies{policies: policies, err: err}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	policies := map[string][]types.AssociatedAccessPolicy{}
+	partial := &PartialError{}
+	for i, entry := range entries {
+		if policiesErr := policiesByEntry[i].err; policiesErr != nil {
+			partial.Errors = append(partial.Errors, ItemError{Name: aws.ToString(entry.PrincipalArn), Err: policiesErr})
+			continue
+		}
+		policies[aws.ToString(entry.PrincipalArn)] = policiesByEntry[i].policies
+	}
+	if len(partial.Errors) > 0 {
+		return policies, partial
+	}
+	return policies, nil
+}
+
+func describeAccessEntry(w *prefixWriter, item AccessEntry, policies []types.AssociatedAccessPolicy) {
+	w.Write(levelZero, "Principal ARN:\t%s\n", stringOrNone(item.PrincipalArn))
+	w.Write(levelZero, "Cluster:\t%s\n", stringOrNone(item.ClusterName))
+	w.Write(levelZero, "ARN:\t%s\n", stringOrNone(item.AccessEntryArn))
+	w.Write(levelZero, "Type:\t%s\n", stringOrNone(item.Type))
+	w.Write(levelZero, "Username:\t%s\n", stringOrNone(item.Username))
+	w.WriteList(levelZero, "Kubernetes Groups", item.KubernetesGroups)
+	w.Write(levelZero, "Created At:\t%s\n", timeOrNone(item.CreatedAt))
+	w.Write(levelZero, "Modified At:\t%s\n", timeOrNone(item.ModifiedAt))
 
-		accessEntries = append(accessEntries, *entry.AccessEntry)
+	if len(policies) == 0 {
+		w.Write(levelZero, "Access Policies:\t<none>\n")
+	} else {
+		w.Write(levelZero, "Access Policies:\n")
+		for _, policy := range policies {
+			w.Write(levelOne, "Policy ARN:\t%s\n", stringOrNone(policy.PolicyArn))
+			if policy.AccessScope != nil {
+				w.Write(levelTwo, "Access Scope:\t%s\n", policy.AccessScope.Type)
+				if policy.AccessScope.Type == types.AccessScopeTypeNamespace {
+					w.WriteList(levelTwo, "Namespaces", policy.AccessScope.Namespaces)
+				}
+			}
+			w.Write(levelTwo, "Associated At:\t%s\n", timeOrNone(policy.AssociatedAt))
+		}
 	}
 
-	return accessEntries, nil
+	w.WriteMap(levelZero, "Tags", item.Tags)
+}
+
+// accessEntryFields are the fields matched by --field-selector.
+func accessEntryFields(item AccessEntry) []fields.Set {
+	return []fields.Set{{
+		"principalArn": aws.ToString(item.PrincipalArn),
+		"type":         aws.ToString(item.Type),
+		"username":     aws.ToString(item.Username),
+	}}
+}
+
+// accessEntryTags are the tags matched by --selector.
+func accessEntryTags(item AccessEntry) map[string]string {
+	return item.Tags
 }
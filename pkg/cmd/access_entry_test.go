@@ -8,6 +8,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"k8s.io/cli-runtime/pkg/printers"
 )
 
 func TestNewAccessEntryPrinter(t *testing.T) {
@@ -96,7 +97,7 @@ func TestNewAccessEntryPrinter(t *testing.T) {
 			}
 
 			// Create printer
-			printer := NewAccessEntryPrinter(client)
+			printer := NewAccessEntryPrinter(client, printers.PrintOptions{})
 
 			// Create buffer to capture output
 			buf := &bytes.Buffer{}
@@ -123,6 +124,157 @@ func TestNewAccessEntryPrinter(t *testing.T) {
 	}
 }
 
+func TestListAccessEntriesPagination(t *testing.T) {
+	pages := map[string]*eks.ListAccessEntriesOutput{
+		"": {
+			AccessEntries: []string{"arn:aws:iam::123456789012:role/role1"},
+			NextToken:     stringPtr("page-2"),
+		},
+		"page-2": {
+			AccessEntries: []string{"arn:aws:iam::123456789012:role/role2"},
+		},
+	}
+
+	mockClient := &mockEKSClient{
+		listAccessEntriesFunc: func(ctx context.Context, params *eks.ListAccessEntriesInput) (*eks.ListAccessEntriesOutput, error) {
+			token := ""
+			if params.NextToken != nil {
+				token = *params.NextToken
+			}
+			return pages[token], nil
+		},
+		describeAccessEntryFunc: func(ctx context.Context, params *eks.DescribeAccessEntryInput) (*eks.DescribeAccessEntryOutput, error) {
+			return &eks.DescribeAccessEntryOutput{
+				AccessEntry: &types.AccessEntry{PrincipalArn: params.PrincipalArn},
+			}, nil
+		},
+	}
+
+	client := &EKSClient{
+		client:      mockClient,
+		clusterName: stringPtr("test-cluster"),
+	}
+
+	entries, err := client.ListAccessEntries(context.Background())
+	if err != nil {
+		t.Fatalf("ListAccessEntries returned error: %v", err)
+	}
+
+	expected := []string{
+		"arn:aws:iam::123456789012:role/role1",
+		"arn:aws:iam::123456789012:role/role2",
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d access entries, got %d", len(expected), len(entries))
+	}
+	for i, arn := range expected {
+		if *entries[i].PrincipalArn != arn {
+			t.Errorf("access entry %d: expected %s, got %s", i, arn, *entries[i].PrincipalArn)
+		}
+	}
+}
+
+func TestNewAccessEntryPrinterPaginatedPolicies(t *testing.T) {
+	pages := map[string]*eks.ListAssociatedAccessPoliciesOutput{
+		"": {
+			AssociatedAccessPolicies: []types.AssociatedAccessPolicy{
+				{PolicyArn: stringPtr("arn:aws:eks::aws:cluster-access-policy/AmazonEKSViewPolicy")},
+			},
+			NextToken: stringPtr("page-2"),
+		},
+		"page-2": {
+			AssociatedAccessPolicies: []types.AssociatedAccessPolicy{
+				{PolicyArn: stringPtr("arn:aws:eks::aws:cluster-access-policy/AmazonEKSEditPolicy")},
+			},
+		},
+	}
+
+	mockClient := &mockEKSClient{
+		listAssociatedAccessPoliciesFunc: func(ctx context.Context, params *eks.ListAssociatedAccessPoliciesInput) (*eks.ListAssociatedAccessPoliciesOutput, error) {
+			token := ""
+			if params.NextToken != nil {
+				token = *params.NextToken
+			}
+			return pages[token], nil
+		},
+	}
+
+	client := &EKSClient{
+		client:      mockClient,
+		clusterName: stringPtr("test-cluster"),
+	}
+
+	buf := &bytes.Buffer{}
+	list := &AccessEntryList{
+		Items: []AccessEntry{
+			{PrincipalArn: stringPtr("arn:aws:iam::123456789012:role/test-role")},
+		},
+	}
+	if err := NewAccessEntryPrinter(client, printers.PrintOptions{}).PrintObj(list, buf); err != nil {
+		t.Fatalf("PrintObj returned error: %v", err)
+	}
+
+	expected := "arn:aws:eks::aws:cluster-access-policy/AmazonEKSViewPolicy,arn:aws:eks::aws:cluster-access-policy/AmazonEKSEditPolicy"
+	if !strings.Contains(buf.String(), expected) {
+		t.Errorf("Output does not contain expected string: %s\nGot: %s", expected, buf.String())
+	}
+}
+
+func TestNewAccessEntryPrinterWide(t *testing.T) {
+	client := &EKSClient{
+		client:      newMockEKSClient(),
+		clusterName: stringPtr("test-cluster"),
+	}
+	list := &AccessEntryList{
+		Items: []AccessEntry{
+			{
+				PrincipalArn: stringPtr("arn:aws:iam::123456789012:role/ci"),
+				Type:         stringPtr("STANDARD"),
+				Username:     stringPtr("ci-bot"),
+			},
+		},
+	}
+
+	for _, wide := range []bool{false, true} {
+		buf := &bytes.Buffer{}
+		if err := NewAccessEntryPrinter(client, printers.PrintOptions{Wide: wide}).PrintObj(list, buf); err != nil {
+			t.Fatalf("PrintObj returned error: %v", err)
+		}
+
+		output := buf.String()
+		for _, expected := range []string{"TYPE", "USERNAME", "STANDARD", "ci-bot"} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("wide=%t: output does not contain %q\nGot: %s", wide, expected, output)
+			}
+		}
+		if strings.Contains(output, "CREATED AT") != wide {
+			t.Errorf("wide=%t: unexpected presence of \"CREATED AT\"\nGot: %s", wide, output)
+		}
+	}
+}
+
+func TestFormatAccessPolicy(t *testing.T) {
+	arn := "arn:aws:eks::aws:cluster-access-policy/AmazonEKSEditPolicy"
+	tests := []struct {
+		name     string
+		scope    *types.AccessScope
+		expected string
+	}{
+		{name: "no scope", expected: arn},
+		{name: "cluster", scope: &types.AccessScope{Type: types.AccessScopeTypeCluster}, expected: arn + "(cluster)"},
+		{name: "namespaces", scope: &types.AccessScope{Type: types.AccessScopeTypeNamespace, Namespaces: []string{"dev", "staging"}}, expected: arn + "(dev,staging)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatAccessPolicy(types.AssociatedAccessPolicy{PolicyArn: stringPtr(arn), AccessScope: tt.scope})
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/eks/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/printers"
@@ -18,6 +21,11 @@ type Addon = types.Addon
 type AddonList struct {
 	metav1.TypeMeta
 	Items []types.Addon
+	// upgrades are the published versions of each item, in wide tables. An
+	// upgrade without AddonName is unknown.
+	upgrades []AddonUpgrade
+	// clusters are the clusters of the items when viewing several contexts
+	clusters []clusterRef
 }
 
 // Implement runtime.Object interface
@@ -29,10 +37,12 @@ func (a *AddonList) DeepCopyObject() runtime.Object {
 	return &AddonList{
 		TypeMeta: a.TypeMeta,
 		Items:    append([]types.Addon(nil), a.Items...),
+		upgrades: append([]AddonUpgrade(nil), a.upgrades...),
+		clusters: append([]clusterRef(nil), a.clusters...),
 	}
 }
 
-func NewAddonPrinter() printers.ResourcePrinter {
+func NewAddonPrinter(options printers.PrintOptions) printers.ResourcePrinter {
 	return printers.ResourcePrinterFunc(func(obj runtime.Object, w io.Writer) error {
 		list, ok := obj.(*AddonList)
 		if !ok {
@@ -49,46 +59,129 @@ func NewAddonPrinter() printers.ResourcePrinter {
 				{Name: "VERSION", Type: "string"},
 				{Name: "STATUS", Type: "string"},
 				{Name: "ISSUES", Type: "integer"},
+				{Name: "ADDON LATEST", Type: "string", Priority: 1},
+				{Name: "DEFAULT VERSION", Type: "string", Priority: 1},
+				{Name: "COMPATIBLE", Type: "string", Priority: 1},
+				{Name: "SERVICE ACCOUNT ROLE ARN", Type: "string", Priority: 1},
+				{Name: "CONFIGURATION VALUES", Type: "boolean", Priority: 1},
+				{Name: "CREATED AT", Type: "string", Priority: 1},
+				{Name: "MODIFIED AT", Type: "string", Priority: 1},
 			},
 		}
 
-		for _, item := range list.Items {
+		for i, item := range list.Items {
+			latest, defaultVersion, compatible := "<unknown>", "<unknown>", "<unknown>"
+			if i < len(list.upgrades) && list.upgrades[i].AddonName != "" {
+				upgrade := list.upgrades[i]
+				latest, defaultVersion = stringOrNone(&upgrade.LatestVersion), stringOrNone(&upgrade.DefaultVersion)
+				compatible = fmt.Sprint(upgrade.Compatible)
+			}
+
 			table.Rows = append(table.Rows, metav1.TableRow{
 				Cells: []interface{}{
 					*item.AddonName,
 					*item.AddonVersion,
 					string(item.Status),
 					len(item.Health.Issues),
+					latest,
+					defaultVersion,
+					compatible,
+					stringOrNone(item.ServiceAccountRoleArn),
+					item.ConfigurationValues != nil && *item.ConfigurationValues != "",
+					tableTime(item.CreatedAt),
+					tableTime(item.ModifiedAt),
 				},
 			})
 		}
 
-		return printTable(w, table, "addons")
+		addClusterColumns(table, list.clusters)
+		addTagsColumn(table, options, func(i int) map[string]string { return list.Items[i].Tags })
+		return printTable(w, table, "addons", options)
 	})
 }
 
 func (c *EKSClient) ListAddons(ctx context.Context) ([]types.Addon, error) {
+	addonNames, err := c.listAddonNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	addonNames = filterNames(c.nameFilter, addonNames, stringNames)
+	return describeAll(ctx, c.pool, addonNames, stringName, c.DescribeAddon)
+}
+
+// listAddonNames returns the names of all addons of the cluster.
+func (c *EKSClient) listAddonNames(ctx context.Context) ([]string, error) {
 	input := &eks.ListAddonsInput{
 		ClusterName: c.clusterName,
 	}
 
-	result, err := c.client.ListAddons(ctx, input)
+	var addonNames []string
+	paginator := eks.NewListAddonsPaginator(c.client, input)
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		addonNames = append(addonNames, result.Addons...)
+	}
+	return addonNames, nil
+}
+
+func (c *EKSClient) DescribeAddon(ctx context.Context, addonName string) (types.Addon, error) {
+	addonOutput, err := c.client.DescribeAddon(ctx, &eks.DescribeAddonInput{
+		ClusterName: c.clusterName,
+		AddonName:   &addonName,
+	})
 	if err != nil {
-		return nil, err
+		return types.Addon{}, err
 	}
 
-	var addons []types.Addon
-	for _, addonName := range result.Addons {
-		addonOutput, err := c.client.DescribeAddon(ctx, &eks.DescribeAddonInput{
-			ClusterName: c.clusterName,
-			AddonName:   &addonName,
-		})
-		if err != nil {
-			return nil, err
+	return *addonOutput.Addon, nil
+}
+
+func describeAddon(w *prefixWriter, item types.Addon) {
+	w.Write(levelZero, "Name:\t%s\n", stringOrNone(item.AddonName))
+	w.Write(levelZero, "Cluster:\t%s\n", stringOrNone(item.ClusterName))
+	w.Write(levelZero, "ARN:\t%s\n", stringOrNone(item.AddonArn))
+	w.Write(levelZero, "Status:\t%s\n", item.Status)
+	w.Write(levelZero, "Version:\t%s\n", stringOrNone(item.AddonVersion))
+	w.Write(levelZero, "Service Account Role ARN:\t%s\n", stringOrNone(item.ServiceAccountRoleArn))
+	w.Write(levelZero, "Owner:\t%s\n", stringOrNone(item.Owner))
+	w.Write(levelZero, "Publisher:\t%s\n", stringOrNone(item.Publisher))
+	w.Write(levelZero, "Created At:\t%s\n", timeOrNone(item.CreatedAt))
+	w.Write(levelZero, "Modified At:\t%s\n", timeOrNone(item.ModifiedAt))
+	w.WriteList(levelZero, "Pod Identity Associations", item.PodIdentityAssociations)
+
+	if item.ConfigurationValues == nil || *item.ConfigurationValues == "" {
+		w.Write(levelZero, "Configuration Values:\t<none>\n")
+	} else {
+		w.Write(levelZero, "Configuration Values:\n")
+		for _, line := range strings.Split(strings.TrimRight(*item.ConfigurationValues, "\n"), "\n") {
+			w.Write(levelOne, "%s\n", line)
 		}
+	}
 
-		addons = append(addons, *addonOutput.Addon)
+	var issues []healthIssue
+	if item.Health != nil {
+		for _, issue := range item.Health.Issues {
+			issues = append(issues, healthIssue{code: string(issue.Code), message: issue.Message, resourceIDs: issue.ResourceIds})
+		}
 	}
+	w.WriteHealthIssues(levelZero, issues)
+	w.WriteMap(levelZero, "Tags", item.Tags)
+}
+
+// addonFields are the fields matched by --field-selector.
+func addonFields(item types.Addon) []fields.Set {
+	return []fields.Set{{
+		"name":    aws.ToString(item.AddonName),
+		"status":  string(item.Status),
+		"version": aws.ToString(item.AddonVersion),
+	}}
+}
 
-	return addons, nil
+// addonTags are the tags matched by --selector.
+func addonTags(item types.Addon) map[string]string {
+	return item.Tags
 }
@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"k8s.io/cli-runtime/pkg/printers"
 )
 
 func TestNewAddonPrinter(t *testing.T) {
@@ -94,7 +97,7 @@ func TestNewAddonPrinter(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create printer
-			printer := NewAddonPrinter()
+			printer := NewAddonPrinter(printers.PrintOptions{})
 
 			// Create buffer to capture output
 			buf := &bytes.Buffer{}
@@ -120,3 +123,82 @@ func TestNewAddonPrinter(t *testing.T) {
 		})
 	}
 }
+
+func TestListAddonsPagination(t *testing.T) {
+	pages := map[string]*eks.ListAddonsOutput{
+		"":       {Addons: []string{"vpc-cni", "coredns"}, NextToken: stringPtr("page-2")},
+		"page-2": {Addons: []string{"kube-proxy"}},
+	}
+
+	mockClient := &mockEKSClient{
+		listAddonsFunc: func(ctx context.Context, params *eks.ListAddonsInput) (*eks.ListAddonsOutput, error) {
+			token := ""
+			if params.NextToken != nil {
+				token = *params.NextToken
+			}
+			return pages[token], nil
+		},
+		describeAddonFunc: func(ctx context.Context, params *eks.DescribeAddonInput) (*eks.DescribeAddonOutput, error) {
+			return &eks.DescribeAddonOutput{
+				Addon: &types.Addon{AddonName: params.AddonName},
+			}, nil
+		},
+	}
+
+	client := &EKSClient{
+		client:      mockClient,
+		clusterName: stringPtr("test-cluster"),
+	}
+
+	addons, err := client.ListAddons(context.Background())
+	if err != nil {
+		t.Fatalf("ListAddons returned error: %v", err)
+	}
+
+	expected := []string{"vpc-cni", "coredns", "kube-proxy"}
+	if len(addons) != len(expected) {
+		t.Fatalf("expected %d addons, got %d", len(expected), len(addons))
+	}
+	for i, name := range expected {
+		if *addons[i].AddonName != name {
+			t.Errorf("addon %d: expected %s, got %s", i, name, *addons[i].AddonName)
+		}
+	}
+}
+
+func TestNewAddonPrinterWide(t *testing.T) {
+	list := &AddonList{
+		Items: []types.Addon{
+			{
+				AddonName:             stringPtr("aws-ebs-csi-driver"),
+				AddonVersion:          stringPtr("v1.30.0-eksbuild.1"),
+				Status:                types.AddonStatusActive,
+				Health:                &types.AddonHealth{},
+				ServiceAccountRoleArn: stringPtr("arn:aws:iam::123456789012:role/ebs-csi"),
+				ConfigurationValues:   stringPtr(`{"controller":{"replicaCount":3}}`),
+			},
+		},
+	}
+	wideOutput := []string{
+		"SERVICE ACCOUNT ROLE ARN",
+		"CONFIGURATION VALUES",
+		"CREATED AT",
+		"MODIFIED AT",
+		"arn:aws:iam::123456789012:role/ebs-csi",
+		"true",
+	}
+
+	for _, wide := range []bool{false, true} {
+		buf := &bytes.Buffer{}
+		if err := NewAddonPrinter(printers.PrintOptions{Wide: wide}).PrintObj(list, buf); err != nil {
+			t.Fatalf("PrintObj returned error: %v", err)
+		}
+
+		output := buf.String()
+		for _, expected := range wideOutput {
+			if strings.Contains(output, expected) != wide {
+				t.Errorf("wide=%t: unexpected presence of %q\nGot: %s", wide, expected, output)
+			}
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// AddonUpgrade compares the version of an installed addon with the versions
+// EKS publishes for the cluster's Kubernetes version and the next one.
+type AddonUpgrade struct {
+	AddonName    string
+	AddonVersion string
+	// LatestVersion and DefaultVersion are empty when no version of the
+	// addon supports the cluster's Kubernetes version
+	LatestVersion  string `json:",omitempty"`
+	DefaultVersion string `json:",omitempty"`
+	// Behind is set when a newer version supports the cluster's Kubernetes version
+	Behind bool
+	// Compatible is set when the installed version supports the cluster's
+	// Kubernetes version
+	Compatible bool
+	// CompatibleWithNext is set when the installed version supports the next
+	// Kubernetes version. It is nil when EKS publishes no addon for it yet.
+	CompatibleWithNext *bool `json:",omitempty"`
+}
+
+type AddonUpgradeList struct {
+	metav1.TypeMeta
+	KubernetesVersion     string          `json:"kubernetesVersion"`
+	NextKubernetesVersion string          `json:"nextKubernetesVersion"`
+	Errors                []ResourceError `json:"errors,omitempty"`
+	Items                 []AddonUpgrade  `json:"items"`
+}
+
+// Implement runtime.Object interface
+func (a *AddonUpgradeList) GetObjectKind() schema.ObjectKind {
+	return &a.TypeMeta
+}
+
+func (a *AddonUpgradeList) DeepCopyObject() runtime.Object {
+	copied := *a
+	copied.Errors = append([]ResourceError(nil), a.Errors...)
+	copied.Items = append([]AddonUpgrade(nil), a.Items...)
+	return &copied
+}
+
+// addonVersionCache holds the addon versions published for each region and
+// Kubernetes version, which are the same for every cluster of that version.
+type addonVersionCache struct {
+	mu sync.Mutex
+	// byVersion is keyed by region and Kubernetes version
+	byVersion map[[2]string]map[string]types.AddonInfo
+}
+
+// RunAddonUpgrades prints, for each addon, whether it is behind the latest
+// version for the cluster and whether it supports the next Kubernetes minor
+// version.
+func (o *Options) RunAddonUpgrades(ctx context.Context) error {
+	list, err := fetchReport(ctx, o, "addons", o.fetchAddonUpgrades)
+	if err != nil {
+		return err
+	}
+	return o.printReport(list, list.Errors, NewAddonUpgradePrinter)
+}
+
+func (o *Options) fetchAddonUpgrades(ctx context.Context) (*AddonUpgradeList, error) {
+	addons, listErr := o.eksClient.ListAddons(ctx)
+	if listErr != nil && !isPartialError(listErr) {
+		return nil, fmt.Errorf("failed to list addons: %v", listErr)
+	}
+	addons = selectItems(o, "addons", addons, addonTags, addonFields)
+
+	items, clusterVersion, nextVersion, err := o.eksClient.clusterAddonUpgrades(ctx, addons)
+	if err != nil {
+		return nil, err
+	}
+	list := &AddonUpgradeList{
+		TypeMeta:              metav1.TypeMeta{APIVersion: "v1", Kind: "EksAddonUpgradeList"},
+		KubernetesVersion:     clusterVersion,
+		NextKubernetesVersion: nextVersion,
+		Items:                 items,
+	}
+	if listErr != nil {
+		list.Errors = toResourceErrors("addons", listErr)
+	}
+	return list, nil
+}
+
+// nextMinorVersion returns the Kubernetes minor version after v, e.g. 1.32
+// for 1.31.
+func nextMinorVersion(v string) (string, error) {
+	parsed, err := version.ParseMajorMinor(v)
+	if err != nil {
+		return "", fmt.Errorf("invalid Kubernetes version %q: %v", v, err)
+	}
+	return fmt.Sprintf("%d.%d", parsed.Major(), parsed.Minor()+1), nil
+}
+
+// clusterAddonUpgrades compares addons with the versions published for the
+// Kubernetes version of the cluster and the next minor version, which it
+// returns along with them.
+func (c *EKSClient) clusterAddonUpgrades(ctx context.Context, addons []Addon) (upgrades []AddonUpgrade, kubernetesVersion, nextVersion string, err error) {
+	clusters, err := c.DescribeCluster(ctx)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to describe cluster: %v", err)
+	}
+	kubernetesVersion = aws.ToString(clusters[0].Version)
+	if nextVersion, err = nextMinorVersion(kubernetesVersion); err != nil {
+		return nil, "", "", err
+	}
+	upgrades, err = c.addonUpgrades(ctx, addons, kubernetesVersion, nextVersion)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return upgrades, kubernetesVersion, nextVersion, nil
+}
+
+// addonUpgrades compares addons with the versions published for
+// kubernetesVersion and nextVersion.
+func (c *EKSClient) addonUpgrades(ctx context.Context, addons []Addon, kubernetesVersion, nextVersion string) ([]AddonUpgrade, error) {
+	current, err := c.addonVersionsFor(ctx, kubernetesVersion)
+	if err != nil {
+		return nil, err
+	}
+	next, err := c.addonVersionsFor(ctx, nextVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	upgrades := []AddonUpgrade{}
+	for _, addon := range addons {
+		name, installed := aws.ToString(addon.AddonName), aws.ToString(addon.AddonVersion)
+		upgrade := AddonUpgrade{AddonName: name, AddonVersion: installed}
+
+		if info, ok := current[name]; ok {
+			for _, v := range info.AddonVersions {
+				candidate := aws.ToString(v.AddonVersion)
+				if upgrade.LatestVersion == "" || compareAddonVersions(candidate, upgrade.LatestVersion) > 0 {
+					upgrade.LatestVersion = candidate
+				}
+				if isDefaultAddonVersion(v, kubernetesVersion) {
+					upgrade.DefaultVersion = candidate
+				}
+				if candidate == installed {
+					upgrade.Compatible = true
+				}
+			}
+			upgrade.Behind = compareAddonVersions(upgrade.LatestVersion, installed) > 0
+		}
+
+		// Nothing is published for a Kubernetes version EKS does not support yet
+		if len(next) > 0 {
+			compatible := false
+			for _, v := range next[name].AddonVersions {
+				if aws.ToString(v.AddonVersion) == installed {
+					compatible = true
+				}
+			}
+			upgrade.CompatibleWithNext = &compatible
+		}
+
+		upgrades = append(upgrades, upgrade)
+	}
+	return upgrades, nil
+}
+
+// addonVersionsFor returns the addon versions that support kubernetesVersion,
+// by addon name. They are fetched once per region and Kubernetes version.
+func (c *EKSClient) addonVersionsFor(ctx context.Context, kubernetesVersion string) (map[string]types.AddonInfo, error) {
+	if c.addonVersions == nil {
+		return c.describeAddonVersions(ctx, kubernetesVersion)
+	}
+
+	key := [2]string{c.region, kubernetesVersion}
+	c.addonVersions.mu.Lock()
+	defer c.addonVersions.mu.Unlock()
+	if catalog, ok := c.addonVersions.byVersion[key]; ok {
+		return catalog, nil
+	}
+	catalog, err := c.describeAddonVersions(ctx, kubernetesVersion)
+	if err != nil {
+		return nil, err
+	}
+	if c.addonVersions.byVersion == nil {
+		c.addonVersions.byVersion = map[[2]string]map[string]types.AddonInfo{}
+	}
+	c.addonVersions.byVersion[key] = catalog
+	return catalog, nil
+}
+
+// describeAddonVersions returns the addon versions that support
+// kubernetesVersion, by addon name.
+func (c *EKSClient) describeAddonVersions(ctx context.Context, kubernetesVersion string) (map[string]types.AddonInfo, error) {
+	input := &eks.DescribeAddonVersionsInput{
+		KubernetesVersion: &kubernetesVersion,
+	}
+
+	catalog := map[string]types.AddonInfo{}
+	paginator := eks.NewDescribeAddonVersionsPaginator(c.client, input)
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe addon versions for Kubernetes %s: %v", kubernetesVersion, err)
+		}
+		for _, info := range result.Addons {
+			name := aws.ToString(info.AddonName)
+			if existing, ok := catalog[name]; ok {
+				info.AddonVersions = append(existing.AddonVersions, info.AddonVersions...)
+			}
+			catalog[name] = info
+		}
+	}
+	return catalog, nil
+}
+
+// isDefaultAddonVersion reports whether v is installed by default on clusters
+// of kubernetesVersion.
+func isDefaultAddonVersion(v types.AddonVersionInfo, kubernetesVersion string) bool {
+	for _, compatibility := range v.Compatibilities {
+		if aws.ToString(compatibility.ClusterVersion) == kubernetesVersion && compatibility.DefaultVersion {
+			return true
+		}
+	}
+	return false
+}
+
+// compareAddonVersions compares addon versions such as v1.19.0-eksbuild.2,
+// where eksbuild numbers compare numerically. Versions that are not semantic
+// versions compare as strings.
+func compareAddonVersions(a, b string) int {
+	va, errA := version.ParseSemantic(a)
+	vb, errB := version.ParseSemantic(b)
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+	switch {
+	case va.LessThan(vb):
+		return -1
+	case vb.LessThan(va):
+		return 1
+	}
+	return 0
+}
+
+func NewAddonUpgradePrinter(options printers.PrintOptions) printers.ResourcePrinter {
+	return printers.ResourcePrinterFunc(func(obj runtime.Object, w io.Writer) error {
+		list, ok := obj.(*AddonUpgradeList)
+		if !ok {
+			return fmt.Errorf("expected *AddonUpgradeList, got %T", obj)
+		}
+
+		table := &metav1.Table{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "AddonUpgrade",
+			},
+			ColumnDefinitions: []metav1.TableColumnDefinition{
+				{Name: "NAME", Type: "string"},
+				{Name: "VERSION", Type: "string"},
+				{Name: "ADDON LATEST", Type: "string"},
+				{Name: "DEFAULT VERSION", Type: "string"},
+				{Name: "BEHIND", Type: "boolean"},
+				{Name: "COMPATIBLE", Type: "boolean"},
+				{Name: "COMPATIBLE " + list.NextKubernetesVersion, Type: "string"},
+			},
+		}
+
+		for _, item := range list.Items {
+			compatibleWithNext := "<unknown>"
+			if item.CompatibleWithNext != nil {
+				compatibleWithNext = fmt.Sprint(*item.CompatibleWithNext)
+			}
+
+			table.Rows = append(table.Rows, metav1.TableRow{
+				Cells: []interface{}{
+					item.AddonName,
+					item.AddonVersion,
+					stringOrNone(&item.LatestVersion),
+					stringOrNone(&item.DefaultVersion),
+					item.Behind,
+					item.Compatible,
+					compatibleWithNext,
+				},
+			})
+		}
+
+		return printTable(w, table, fmt.Sprintf("addon upgrades (Kubernetes %s)", list.KubernetesVersion), options)
+	})
+}
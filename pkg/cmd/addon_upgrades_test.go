@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+)
+
+func TestCompareAddonVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"v1.19.0-eksbuild.1", "v1.18.3-eksbuild.2", 1},
+		{"v1.18.3-eksbuild.2", "v1.18.3-eksbuild.10", -1},
+		{"v1.18.3-eksbuild.2", "v1.18.3-eksbuild.2", 0},
+		{"custom-b", "custom-a", 1},
+	}
+
+	for _, tt := range tests {
+		if got := compareAddonVersions(tt.a, tt.b); got != tt.expected {
+			t.Errorf("compareAddonVersions(%q, %q): expected %d, got %d", tt.a, tt.b, tt.expected, got)
+		}
+	}
+}
+
+func addonVersion(v string, clusterVersion string, isDefault bool) types.AddonVersionInfo {
+	return types.AddonVersionInfo{
+		AddonVersion:    stringPtr(v),
+		Compatibilities: []types.Compatibility{{ClusterVersion: stringPtr(clusterVersion), DefaultVersion: isDefault}},
+	}
+}
+
+// newAddonUpgradesMock has a 1.31 cluster with an outdated vpc-cni, an up to
+// date coredns and a kube-proxy that does not support 1.32. It counts the
+// DescribeAddonVersions calls per Kubernetes version.
+func newAddonUpgradesMock(calls map[string]int, mu *sync.Mutex) *mockEKSClient {
+	catalogs := map[string][]*eks.DescribeAddonVersionsOutput{
+		"1.31": {
+			{
+				Addons: []types.AddonInfo{{
+					AddonName: stringPtr("vpc-cni"),
+					AddonVersions: []types.AddonVersionInfo{
+						addonVersion("v1.18.3-eksbuild.10", "1.31", false),
+						addonVersion("v1.18.3-eksbuild.2", "1.31", true),
+					},
+				}},
+				NextToken: stringPtr("page-2"),
+			},
+			{
+				Addons: []types.AddonInfo{
+					{AddonName: stringPtr("vpc-cni"), AddonVersions: []types.AddonVersionInfo{addonVersion("v1.18.1-eksbuild.1", "1.31", false)}},
+					{AddonName: stringPtr("coredns"), AddonVersions: []types.AddonVersionInfo{addonVersion("v1.11.3-eksbuild.2", "1.31", true)}},
+					{AddonName: stringPtr("kube-proxy"), AddonVersions: []types.AddonVersionInfo{addonVersion("v1.31.2-eksbuild.3", "1.31", true)}},
+				},
+			},
+		},
+		"1.32": {
+			{
+				Addons: []types.AddonInfo{
+					{AddonName: stringPtr("vpc-cni"), AddonVersions: []types.AddonVersionInfo{addonVersion("v1.18.3-eksbuild.2", "1.32", true)}},
+					{AddonName: stringPtr("coredns"), AddonVersions: []types.AddonVersionInfo{addonVersion("v1.11.3-eksbuild.2", "1.32", true)}},
+					{AddonName: stringPtr("kube-proxy"), AddonVersions: []types.AddonVersionInfo{addonVersion("v1.32.0-eksbuild.2", "1.32", true)}},
+				},
+			},
+		},
+	}
+	installed := map[string]string{
+		"vpc-cni":    "v1.18.3-eksbuild.2",
+		"coredns":    "v1.11.3-eksbuild.2",
+		"kube-proxy": "v1.31.2-eksbuild.3",
+	}
+
+	mockClient := newMockEKSClient()
+	mockClient.listAddonsFunc = func(ctx context.Context, params *eks.ListAddonsInput) (*eks.ListAddonsOutput, error) {
+		return &eks.ListAddonsOutput{Addons: []string{"vpc-cni", "coredns", "kube-proxy"}}, nil
+	}
+	mockClient.describeAddonFunc = func(ctx context.Context, params *eks.DescribeAddonInput) (*eks.DescribeAddonOutput, error) {
+		return &eks.DescribeAddonOutput{
+			Addon: &types.Addon{AddonName: params.AddonName, AddonVersion: stringPtr(installed[*params.AddonName])},
+		}, nil
+	}
+	mockClient.describeAddonVersionsFunc = func(ctx context.Context, params *eks.DescribeAddonVersionsInput) (*eks.DescribeAddonVersionsOutput, error) {
+		kubernetesVersion := aws.ToString(params.KubernetesVersion)
+		page := 0
+		if params.NextToken != nil {
+			page = 1
+		} else {
+			mu.Lock()
+			calls[kubernetesVersion]++
+			mu.Unlock()
+		}
+		pages := catalogs[kubernetesVersion]
+		if len(pages) == 0 {
+			return &eks.DescribeAddonVersionsOutput{}, nil
+		}
+		return pages[page], nil
+	}
+	return mockClient
+}
+
+func TestRunAddonUpgrades(t *testing.T) {
+	calls, mu := map[string]int{}, &sync.Mutex{}
+	o, out, _ := newTestOptions(newAddonUpgradesMock(calls, mu), "")
+	o.resourceType, o.upgrades = "addons", true
+
+	if err := o.RunAddonUpgrades(context.Background()); err != nil {
+		t.Fatalf("RunAddonUpgrades returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if lines[0] != "=== addon upgrades (Kubernetes 1.31) ===" || !strings.Contains(lines[1], "COMPATIBLE 1.32") {
+		t.Fatalf("unexpected header:\n%s", out.String())
+	}
+	expected := [][]string{
+		{"coredns", "v1.11.3-eksbuild.2", "v1.11.3-eksbuild.2", "v1.11.3-eksbuild.2", "false", "true", "true"},
+		{"kube-proxy", "v1.31.2-eksbuild.3", "v1.31.2-eksbuild.3", "v1.31.2-eksbuild.3", "false", "true", "false"},
+		{"vpc-cni", "v1.18.3-eksbuild.2", "v1.18.3-eksbuild.10", "v1.18.3-eksbuild.2", "true", "true", "true"},
+	}
+	for i, fields := range expected {
+		if got := strings.Fields(lines[i+2]); strings.Join(got, " ") != strings.Join(fields, " ") {
+			t.Errorf("row %d: expected %v, got %v", i, fields, got)
+		}
+	}
+
+	// A second run reuses the addon versions of both Kubernetes versions
+	if _, err := o.fetchAddonUpgrades(context.Background()); err != nil {
+		t.Fatalf("fetchAddonUpgrades returned error: %v", err)
+	}
+	if calls["1.31"] != 1 || calls["1.32"] != 1 {
+		t.Errorf("expected addon versions to be described once per Kubernetes version, got %v", calls)
+	}
+}
+
+func TestRunAddonUpgradesUnreleasedNextVersion(t *testing.T) {
+	mockClient := newAddonUpgradesMock(map[string]int{}, &sync.Mutex{})
+	mockClient.describeClusterFunc = func(ctx context.Context, params *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error) {
+		return &eks.DescribeClusterOutput{Cluster: &types.Cluster{Name: params.Name, Version: stringPtr("1.32")}}, nil
+	}
+	o, out, _ := newTestOptions(mockClient, "json")
+	o.resourceType, o.upgrades = "addons", true
+
+	if err := o.RunAddonUpgrades(context.Background()); err != nil {
+		t.Fatalf("RunAddonUpgrades returned error: %v", err)
+	}
+
+	list := &AddonUpgradeList{}
+	if err := json.Unmarshal(out.Bytes(), list); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if list.Kind != "EksAddonUpgradeList" || list.NextKubernetesVersion != "1.33" || len(list.Items) != 3 {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+	for _, item := range list.Items {
+		if item.CompatibleWithNext != nil {
+			t.Errorf("%s: expected an unknown 1.33 compatibility, got %v", item.AddonName, *item.CompatibleWithNext)
+		}
+		// kube-proxy must be upgraded along with the cluster
+		if expected := item.AddonName != "kube-proxy"; item.Compatible != expected || item.Behind == expected {
+			t.Errorf("%s: expected compatible %t, got %+v", item.AddonName, expected, item)
+		}
+	}
+}
+
+func TestAddonsWideVersions(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		expected []string
+	}{
+		{
+			name:     "wide",
+			format:   "wide",
+			expected: []string{"coredns", "v1.11.3-eksbuild.2", "ACTIVE", "0", "v1.11.3-eksbuild.2", "v1.11.3-eksbuild.2", "true"},
+		},
+		{
+			name:     "default columns",
+			expected: []string{"coredns", "v1.11.3-eksbuild.2", "ACTIVE", "0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := map[string]int{}
+			mockClient := newAddonUpgradesMock(calls, &sync.Mutex{})
+			describe := mockClient.describeAddonFunc
+			mockClient.describeAddonFunc = func(ctx context.Context, params *eks.DescribeAddonInput) (*eks.DescribeAddonOutput, error) {
+				output, err := describe(ctx, params)
+				output.Addon.Status = types.AddonStatusActive
+				output.Addon.Health = &types.AddonHealth{}
+				return output, err
+			}
+			o, out, _ := newTestOptions(mockClient, tt.format)
+			o.resourceType, o.quiet = "addons", true
+
+			if err := o.Run(); err != nil {
+				t.Fatalf("Run returned error: %v", err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			var row []string
+			for _, line := range lines {
+				if fields := strings.Fields(line); len(fields) > 0 && fields[0] == "coredns" {
+					row = fields
+				}
+			}
+			if len(row) < len(tt.expected) || strings.Join(row[:len(tt.expected)], " ") != strings.Join(tt.expected, " ") {
+				t.Errorf("expected row starting with %v, got:\n%s", tt.expected, out.String())
+			}
+			// Addon versions are only described for wide tables
+			if wide := tt.format == "wide"; (calls["1.31"] == 1) != wide {
+				t.Errorf("expected addon versions described %t, got %v", wide, calls)
+			}
+		})
+	}
+}
+
+func TestAddonVersionsSharedByContexts(t *testing.T) {
+	calls, mu := map[string]int{}, &sync.Mutex{}
+	o, out, _ := newTestOptions(newMockEKSClient(), "wide")
+	o.rawConfig = newMultiContextConfig()
+	o.resourceType = "addons"
+	connect := func(contextName string) (*EKSClient, error) {
+		mockClient := newAddonUpgradesMock(calls, mu)
+		describe := mockClient.describeAddonFunc
+		mockClient.describeAddonFunc = func(ctx context.Context, params *eks.DescribeAddonInput) (*eks.DescribeAddonOutput, error) {
+			output, err := describe(ctx, params)
+			output.Addon.Health = &types.AddonHealth{}
+			return output, err
+		}
+		return &EKSClient{
+			client:        mockClient,
+			clusterName:   stringPtr(contextName + "-cluster"),
+			pool:          newWorkerPool(2),
+			region:        "eu-west-1",
+			addonVersions: o.addonVersions,
+		}, nil
+	}
+
+	if err := o.runContexts(context.Background(), out, []string{"production", "staging"}, connect); err != nil {
+		t.Fatalf("runContexts returned error: %v", err)
+	}
+
+	// Both clusters run Kubernetes 1.31 in the same region
+	if calls["1.31"] != 1 || calls["1.32"] != 1 {
+		t.Errorf("expected addon versions to be described once for both contexts, got %v", calls)
+	}
+	if strings.Count(out.String(), "v1.18.3-eksbuild.10") != 2 {
+		t.Errorf("expected the latest vpc-cni version of both clusters, got:\n%s", out.String())
+	}
+}
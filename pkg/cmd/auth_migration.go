@@ -0,0 +1,405 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"sigs.k8s.io/yaml"
+)
+
+// Statuses of a principal mapped by aws-auth or an access entry, in the order
+// they are printed
+const (
+	authConfigMapOnly   = "CONFIG-MAP-ONLY"
+	authConflict        = "CONFLICT"
+	authAccessEntryOnly = "ACCESS-ENTRY-ONLY"
+	authMatch           = "MATCH"
+)
+
+var authStatusOrder = map[string]int{authConfigMapOnly: 0, authConflict: 1, authAccessEntryOnly: 2, authMatch: 3}
+
+// clusterAdminPolicyArn is the access policy that replaces the system:masters
+// group, which access entries cannot use.
+const clusterAdminPolicyArn = "arn:aws:eks::aws:cluster-access-policy/AmazonEKSClusterAdminPolicy"
+
+// nodeAccessEntryGroups are the groups EKS gives to the access entries of
+// nodes, which have no Kubernetes groups of their own.
+var nodeAccessEntryGroups = map[string][]string{
+	"EC2_LINUX":     {"system:bootstrappers", "system:nodes"},
+	"EC2_WINDOWS":   {"eks:kube-proxy-windows", "system:bootstrappers", "system:nodes"},
+	"FARGATE_LINUX": {"system:bootstrappers", "system:node-proxier", "system:nodes"},
+	"HYBRID_LINUX":  {"system:bootstrappers", "system:nodes"},
+}
+
+// rolePathPattern matches the path of an IAM role ARN, which aws-auth does
+// not support.
+var rolePathPattern = regexp.MustCompile(`^(arn:[^:]+:iam::\d+:role/)(?:[^/]+/)+([^/]+)$`)
+
+// AuthMapping compares how a principal is mapped by the aws-auth ConfigMap
+// and by its access entry.
+type AuthMapping struct {
+	PrincipalArn string
+	Status       string
+	// ConfigMapUsername and ConfigMapGroups are the mapping of aws-auth
+	ConfigMapUsername string   `json:",omitempty"`
+	ConfigMapGroups   []string `json:",omitempty"`
+	AccessEntryType   string   `json:",omitempty"`
+	// AccessEntryUsername and AccessEntryGroups are the mapping of the
+	// access entry
+	AccessEntryUsername string   `json:",omitempty"`
+	AccessEntryGroups   []string `json:",omitempty"`
+	AccessPolicies      []string `json:",omitempty"`
+	// MissingGroups are the groups of aws-auth the access entry does not
+	// grant
+	MissingGroups []string `json:",omitempty"`
+}
+
+// AuthMigration is the output of the auth-migration command.
+type AuthMigration struct {
+	metav1.TypeMeta
+	Cluster            string `json:"cluster"`
+	AuthenticationMode string `json:"authenticationMode"`
+	// SafeToSwitch is set when every principal of aws-auth has an access
+	// entry, so that switching to the API authentication mode locks no one
+	// out. It is not set when access entries could not be read.
+	SafeToSwitch bool            `json:"safeToSwitch"`
+	Errors       []ResourceError `json:"errors,omitempty"`
+	Items        []AuthMapping   `json:"items"`
+}
+
+// Implement runtime.Object interface
+func (a *AuthMigration) GetObjectKind() schema.ObjectKind {
+	return &a.TypeMeta
+}
+
+func (a *AuthMigration) DeepCopyObject() runtime.Object {
+	copied := *a
+	copied.Errors = append([]ResourceError(nil), a.Errors...)
+	copied.Items = append([]AuthMapping(nil), a.Items...)
+	return &copied
+}
+
+// awsAuthMapping is an entry of the mapRoles or mapUsers keys of aws-auth.
+type awsAuthMapping struct {
+	RoleArn  string   `json:"rolearn"`
+	UserArn  string   `json:"userarn"`
+	Username string   `json:"username"`
+	Groups   []string `json:"groups"`
+}
+
+func NewAuthMigrationCmd(o *Options) *cobra.Command {
+	printFlags := genericclioptions.NewPrintFlags("")
+
+	cmd := &cobra.Command{
+		Use:   "auth-migration",
+		Short: "Compare the aws-auth ConfigMap with the access entries of the cluster",
+		Long: `Compare the IAM principals mapped by the kube-system/aws-auth ConfigMap with
+the access entries of the cluster, to migrate it to the API authentication mode.
+
+Each principal is:
+  CONFIG-MAP-ONLY    mapped by aws-auth only, it loses access in the API mode
+  CONFLICT           mapped by both, with different Kubernetes groups
+  ACCESS-ENTRY-ONLY  mapped by an access entry only
+  MATCH              mapped by both, with the same Kubernetes groups
+
+The AmazonEKSClusterAdminPolicy access policy stands for the system:masters
+group, and the access entries of nodes for the groups EKS gives to nodes.`,
+		Example: `  # Check whether switching the current cluster to the API mode locks anyone out
+  kubectl eks-viewer auth-migration
+
+  # List the principals that are only mapped by aws-auth
+  kubectl eks-viewer auth-migration -o json | jq '.items[] | select(.Status == "CONFIG-MAP-ONLY")'`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if err := o.validateLiveCluster("auth-migration"); err != nil {
+				return err
+			}
+			o.printFlags = printFlags
+
+			if err := o.Complete(); err != nil {
+				return err
+			}
+			if err := o.ensureKubernetesClient(); err != nil {
+				return err
+			}
+			return o.RunAuthMigration(context.Background())
+		},
+	}
+
+	printFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+// RunAuthMigration compares aws-auth with the access entries of the cluster
+// and prints whether switching to the API authentication mode locks anyone
+// out.
+func (o *Options) RunAuthMigration(ctx context.Context) error {
+	migration, err := o.buildAuthMigration(ctx)
+	if err != nil {
+		return err
+	}
+	return o.printReport(migration, migration.Errors, NewAuthMigrationPrinter)
+}
+
+func (o *Options) buildAuthMigration(ctx context.Context) (*AuthMigration, error) {
+	clusters, err := o.eksClient.DescribeCluster(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe cluster: %v", err)
+	}
+	cluster := clusters[0]
+	mode := types.AuthenticationModeConfigMap
+	if cluster.AccessConfig != nil {
+		mode = cluster.AccessConfig.AuthenticationMode
+	}
+
+	configMap, err := o.readAWSAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	migration := &AuthMigration{
+		TypeMeta:           metav1.TypeMeta{APIVersion: "v1", Kind: "EksAuthMigration"},
+		Cluster:            aws.ToString(cluster.Name),
+		AuthenticationMode: string(mode),
+	}
+
+	// Access entries cannot be listed before the cluster allows them
+	var entries []AccessEntry
+	policies := map[string][]types.AssociatedAccessPolicy{}
+	if mode != types.AuthenticationModeConfigMap {
+		var listErr error
+		entries, listErr = o.eksClient.ListAccessEntries(ctx)
+		if listErr != nil && !isPartialError(listErr) {
+			return nil, fmt.Errorf("failed to list access entries: %v", listErr)
+		}
+		if listErr != nil {
+			migration.Errors = append(migration.Errors, toResourceErrors("access-entries", listErr)...)
+		}
+
+		var policiesErr error
+		policies, policiesErr = o.eksClient.listAccessEntryPolicies(ctx, entries)
+		if policiesErr != nil && !isPartialError(policiesErr) {
+			return nil, policiesErr
+		}
+		if policiesErr != nil {
+			migration.Errors = append(migration.Errors, toResourceErrors("access-entries", policiesErr)...)
+		}
+	}
+
+	migration.Items = compareAuthMappings(configMap, entries, policies)
+	// An access entry that could not be read may be missing
+	migration.SafeToSwitch = len(migration.Errors) == 0
+	for _, item := range migration.Items {
+		if item.Status == authConfigMapOnly {
+			migration.SafeToSwitch = false
+		}
+	}
+	return migration, nil
+}
+
+// readAWSAuth returns the mapRoles and mapUsers of the aws-auth ConfigMap,
+// none when the cluster has no aws-auth ConfigMap.
+func (o *Options) readAWSAuth(ctx context.Context) ([]awsAuthMapping, error) {
+	configMap, err := o.kubeClient.CoreV1().ConfigMaps("kube-system").Get(ctx, "aws-auth", metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kube-system/aws-auth: %v", err)
+	}
+
+	var mappings []awsAuthMapping
+	for _, key := range []string{"mapRoles", "mapUsers"} {
+		var keyMappings []awsAuthMapping
+		if err := yaml.Unmarshal([]byte(configMap.Data[key]), &keyMappings); err != nil {
+			return nil, fmt.Errorf("failed to parse %s of kube-system/aws-auth: %v", key, err)
+		}
+		mappings = append(mappings, keyMappings...)
+	}
+	return mappings, nil
+}
+
+// compareAuthMappings matches the principals of aws-auth with the access
+// entries. policies are the access policies of each entry by principal ARN.
+func compareAuthMappings(configMap []awsAuthMapping, entries []AccessEntry, policies map[string][]types.AssociatedAccessPolicy) []AuthMapping {
+	byPrincipal := map[string]*AuthMapping{}
+	var principals []string
+	mappingOf := func(arn string) *AuthMapping {
+		key := principalKey(arn)
+		if _, ok := byPrincipal[key]; !ok {
+			byPrincipal[key] = &AuthMapping{PrincipalArn: arn}
+			principals = append(principals, key)
+		}
+		return byPrincipal[key]
+	}
+
+	inConfigMap := map[string]bool{}
+	for _, m := range configMap {
+		arn := m.RoleArn
+		if arn == "" {
+			arn = m.UserArn
+		}
+		mapping := mappingOf(arn)
+		inConfigMap[principalKey(arn)] = true
+		if mapping.ConfigMapUsername == "" {
+			mapping.ConfigMapUsername = m.Username
+		}
+		mapping.ConfigMapGroups = sortedUnion(mapping.ConfigMapGroups, m.Groups)
+	}
+
+	inAccessEntries := map[string]bool{}
+	accessGroups := map[string][]string{}
+	for _, entry := range entries {
+		arn := aws.ToString(entry.PrincipalArn)
+		// Keep the ARN of the access entry, which may have a path
+		mapping := mappingOf(arn)
+		mapping.PrincipalArn = arn
+		inAccessEntries[principalKey(arn)] = true
+		mapping.AccessEntryType = aws.ToString(entry.Type)
+		mapping.AccessEntryUsername = aws.ToString(entry.Username)
+		mapping.AccessEntryGroups = sortedUnion(nil, entry.KubernetesGroups)
+
+		groups := sortedUnion(entry.KubernetesGroups, nodeAccessEntryGroups[mapping.AccessEntryType])
+		for _, policy := range policies[arn] {
+			policyArn := aws.ToString(policy.PolicyArn)
+			mapping.AccessPolicies = append(mapping.AccessPolicies, policyArn)
+			if policyArn == clusterAdminPolicyArn && policy.AccessScope != nil && policy.AccessScope.Type == types.AccessScopeTypeCluster {
+				groups = sortedUnion(groups, []string{"system:masters"})
+			}
+		}
+		accessGroups[principalKey(arn)] = groups
+	}
+
+	var items []AuthMapping
+	for _, key := range principals {
+		mapping := byPrincipal[key]
+		switch {
+		case !inAccessEntries[key]:
+			mapping.Status = authConfigMapOnly
+		case !inConfigMap[key]:
+			mapping.Status = authAccessEntryOnly
+		default:
+			mapping.Status = authMatch
+			for _, group := range mapping.ConfigMapGroups {
+				if !slices.Contains(accessGroups[key], group) {
+					mapping.MissingGroups = append(mapping.MissingGroups, group)
+				}
+			}
+			if len(mapping.MissingGroups) > 0 || !slices.Equal(accessGroups[key], mapping.ConfigMapGroups) {
+				mapping.Status = authConflict
+			}
+		}
+		items = append(items, *mapping)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if authStatusOrder[items[i].Status] != authStatusOrder[items[j].Status] {
+			return authStatusOrder[items[i].Status] < authStatusOrder[items[j].Status]
+		}
+		return items[i].PrincipalArn < items[j].PrincipalArn
+	})
+	return items
+}
+
+// principalKey identifies an IAM principal by its ARN without the path of
+// roles, which aws-auth omits.
+func principalKey(arn string) string {
+	return rolePathPattern.ReplaceAllString(arn, "$1$2")
+}
+
+// sortedUnion returns the sorted, distinct values of a and b.
+func sortedUnion(a, b []string) []string {
+	union := slices.Concat(a, b)
+	slices.Sort(union)
+	return slices.Compact(union)
+}
+
+func NewAuthMigrationPrinter(options printers.PrintOptions) printers.ResourcePrinter {
+	return printers.ResourcePrinterFunc(func(obj runtime.Object, w io.Writer) error {
+		migration, ok := obj.(*AuthMigration)
+		if !ok {
+			return fmt.Errorf("expected *AuthMigration, got %T", obj)
+		}
+
+		table := &metav1.Table{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "AuthMapping",
+			},
+			ColumnDefinitions: []metav1.TableColumnDefinition{
+				{Name: "PRINCIPAL ARN", Type: "string"},
+				{Name: "STATUS", Type: "string"},
+				{Name: "AWS-AUTH GROUPS", Type: "string"},
+				{Name: "ACCESS ENTRY GROUPS", Type: "string"},
+				{Name: "ACCESS POLICIES", Type: "string"},
+				{Name: "MISSING GROUPS", Type: "string"},
+				{Name: "AWS-AUTH USERNAME", Type: "string", Priority: 1},
+				{Name: "ACCESS ENTRY USERNAME", Type: "string", Priority: 1},
+				{Name: "TYPE", Type: "string", Priority: 1},
+			},
+		}
+
+		counts := map[string]int{}
+		for _, item := range migration.Items {
+			counts[item.Status]++
+			table.Rows = append(table.Rows, metav1.TableRow{
+				Cells: []interface{}{
+					item.PrincipalArn,
+					item.Status,
+					joinOrNone(item.ConfigMapGroups),
+					joinOrNone(item.AccessEntryGroups),
+					joinOrNone(item.AccessPolicies),
+					joinOrNone(item.MissingGroups),
+					stringOrNone(&item.ConfigMapUsername),
+					stringOrNone(&item.AccessEntryUsername),
+					stringOrNone(&item.AccessEntryType),
+				},
+			})
+		}
+
+		header := fmt.Sprintf("auth migration: %s (%s)", migration.Cluster, migration.AuthenticationMode)
+		if err := printTable(w, table, header, options); err != nil {
+			return err
+		}
+
+		var notes []string
+		switch {
+		case migration.SafeToSwitch:
+			notes = append(notes, "Switching to the API authentication mode would not lock anyone out")
+		case counts[authConfigMapOnly] == 0:
+			notes = append(notes, "Some access entries could not be read, switching to the API authentication mode may lock principals out")
+		default:
+			notes = append(notes, fmt.Sprintf("Switching to the API authentication mode would lock out %d principal(s) only mapped by aws-auth", counts[authConfigMapOnly]))
+		}
+		if counts[authConflict] > 0 {
+			notes = append(notes, fmt.Sprintf("%d principal(s) have other Kubernetes groups in their access entry", counts[authConflict]))
+		}
+		switch types.AuthenticationMode(migration.AuthenticationMode) {
+		case types.AuthenticationModeConfigMap:
+			notes = append(notes, "Access entries can only be created after switching to API_AND_CONFIG_MAP")
+		case types.AuthenticationModeApi:
+			notes = append(notes, "The cluster already ignores aws-auth")
+		}
+		fmt.Fprintf(w, "\n%s\n", strings.Join(notes, "\n"))
+		return nil
+	})
+}
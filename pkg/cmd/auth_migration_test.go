@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPrincipalKey(t *testing.T) {
+	tests := []struct {
+		arn      string
+		expected string
+	}{
+		{"arn:aws:iam::123456789012:role/admins", "arn:aws:iam::123456789012:role/admins"},
+		{"arn:aws:iam::123456789012:role/teams/platform/admins", "arn:aws:iam::123456789012:role/admins"},
+		{"arn:aws:iam::123456789012:user/alice", "arn:aws:iam::123456789012:user/alice"},
+	}
+
+	for _, tt := range tests {
+		if got := principalKey(tt.arn); got != tt.expected {
+			t.Errorf("principalKey(%q): expected %q, got %q", tt.arn, tt.expected, got)
+		}
+	}
+}
+
+const awsAuthMapRoles = `- rolearn: arn:aws:iam::123456789012:role/node-role
+  username: system:node:{{EC2PrivateDNSName}}
+  groups:
+  - system:bootstrappers
+  - system:nodes
+- rolearn: arn:aws:iam::123456789012:role/admins
+  username: admin
+  groups:
+  - system:masters
+- rolearn: arn:aws:iam::123456789012:role/developers
+  username: developer
+  groups:
+  - developers
+  - viewers
+- rolearn: arn:aws:iam::123456789012:role/legacy-ci
+  username: ci
+  groups:
+  - deployers
+`
+
+const awsAuthMapUsers = `- userarn: arn:aws:iam::123456789012:user/alice
+  username: alice
+  groups:
+  - viewers
+`
+
+// newAuthMigrationMock has access entries for the nodes, the admins (with a
+// path), the developers (without the viewers group), alice and a new team.
+func newAuthMigrationMock(mode types.AuthenticationMode) *mockEKSClient {
+	entries := map[string]types.AccessEntry{
+		"arn:aws:iam::123456789012:role/node-role":       {Type: stringPtr("EC2_LINUX")},
+		"arn:aws:iam::123456789012:role/platform/admins": {Type: stringPtr("STANDARD")},
+		"arn:aws:iam::123456789012:role/developers":      {Type: stringPtr("STANDARD"), KubernetesGroups: []string{"developers"}},
+		"arn:aws:iam::123456789012:user/alice":           {Type: stringPtr("STANDARD"), KubernetesGroups: []string{"viewers"}},
+		"arn:aws:iam::123456789012:role/new-team":        {Type: stringPtr("STANDARD"), KubernetesGroups: []string{"new-team"}},
+	}
+
+	mockClient := newMockEKSClient()
+	mockClient.describeClusterFunc = func(ctx context.Context, params *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error) {
+		return &eks.DescribeClusterOutput{
+			Cluster: &types.Cluster{Name: params.Name, AccessConfig: &types.AccessConfigResponse{AuthenticationMode: mode}},
+		}, nil
+	}
+	mockClient.listAccessEntriesFunc = func(ctx context.Context, params *eks.ListAccessEntriesInput) (*eks.ListAccessEntriesOutput, error) {
+		var arns []string
+		for arn := range entries {
+			arns = append(arns, arn)
+		}
+		return &eks.ListAccessEntriesOutput{AccessEntries: arns}, nil
+	}
+	mockClient.describeAccessEntryFunc = func(ctx context.Context, params *eks.DescribeAccessEntryInput) (*eks.DescribeAccessEntryOutput, error) {
+		entry := entries[*params.PrincipalArn]
+		entry.PrincipalArn = params.PrincipalArn
+		return &eks.DescribeAccessEntryOutput{AccessEntry: &entry}, nil
+	}
+	mockClient.listAssociatedAccessPoliciesFunc = func(ctx context.Context, params *eks.ListAssociatedAccessPoliciesInput) (*eks.ListAssociatedAccessPoliciesOutput, error) {
+		if *params.PrincipalArn != "arn:aws:iam::123456789012:role/platform/admins" {
+			return &eks.ListAssociatedAccessPoliciesOutput{}, nil
+		}
+		return &eks.ListAssociatedAccessPoliciesOutput{AssociatedAccessPolicies: []types.AssociatedAccessPolicy{{
+			PolicyArn:   stringPtr(clusterAdminPolicyArn),
+			AccessScope: &types.AccessScope{Type: types.AccessScopeTypeCluster},
+		}}}, nil
+	}
+	return mockClient
+}
+
+func newAWSAuthClientset() *fake.Clientset {
+	return fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "aws-auth"},
+		Data:       map[string]string{"mapRoles": awsAuthMapRoles, "mapUsers": awsAuthMapUsers},
+	})
+}
+
+func TestRunAuthMigration(t *testing.T) {
+	o, out, _ := newTestOptions(newAuthMigrationMock(types.AuthenticationModeApiAndConfigMap), "json")
+	o.kubeClient = newAWSAuthClientset()
+
+	if err := o.RunAuthMigration(context.Background()); err != nil {
+		t.Fatalf("RunAuthMigration returned error: %v", err)
+	}
+
+	migration := &AuthMigration{}
+	if err := json.Unmarshal(out.Bytes(), migration); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if migration.Kind != "EksAuthMigration" || migration.AuthenticationMode != "API_AND_CONFIG_MAP" || migration.SafeToSwitch {
+		t.Errorf("unexpected migration: %+v", migration)
+	}
+
+	var got []string
+	for _, item := range migration.Items {
+		got = append(got, item.Status+" "+item.PrincipalArn+" "+strings.Join(item.MissingGroups, ","))
+	}
+	expected := []string{
+		"CONFIG-MAP-ONLY arn:aws:iam::123456789012:role/legacy-ci ",
+		"CONFLICT arn:aws:iam::123456789012:role/developers viewers",
+		"ACCESS-ENTRY-ONLY arn:aws:iam::123456789012:role/new-team ",
+		"MATCH arn:aws:iam::123456789012:role/node-role ",
+		"MATCH arn:aws:iam::123456789012:role/platform/admins ",
+		"MATCH arn:aws:iam::123456789012:user/alice ",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected mappings:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestRunAuthMigrationWithoutAWSAuth(t *testing.T) {
+	o, out, _ := newTestOptions(newAuthMigrationMock(types.AuthenticationModeApi), "json")
+	o.kubeClient = fake.NewSimpleClientset()
+
+	if err := o.RunAuthMigration(context.Background()); err != nil {
+		t.Fatalf("RunAuthMigration returned error: %v", err)
+	}
+
+	migration := &AuthMigration{}
+	if err := json.Unmarshal(out.Bytes(), migration); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if !migration.SafeToSwitch || len(migration.Items) != 5 {
+		t.Errorf("expected only access entries, got %+v", migration)
+	}
+	for _, item := range migration.Items {
+		if item.Status != authAccessEntryOnly {
+			t.Errorf("expected %s, got %+v", authAccessEntryOnly, item)
+		}
+	}
+}
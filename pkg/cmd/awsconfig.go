@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// awsSettings are the AWS region, shared config profile and IAM role used to
+// build the EKS client. Empty fields fall back to the AWS SDK defaults.
+type awsSettings struct {
+	Region  string
+	Profile string
+	RoleARN string
+}
+
+// eksEndpointPattern matches EKS API server endpoints such as
+// https://ABCDEF0123456789.gr7.us-west-2.eks.amazonaws.com
+var eksEndpointPattern = regexp.MustCompile(`\.([a-z]{2}(?:-[a-z]+)+-\d+)\.eks\.amazonaws\.com(?:\.cn)?(?::\d+)?/?$`)
+
+// awsSettingsFromKubeconfig derives AWS settings for a kubeconfig context from
+// the `aws eks get-token` (or aws-iam-authenticator) exec plugin of its user,
+// the cluster ARN used as the kubeconfig cluster name, and the API server
+// endpoint.
+func awsSettingsFromKubeconfig(rawConfig api.Config, kubeContext *api.Context) awsSettings {
+	var settings awsSettings
+
+	if authInfo, ok := rawConfig.AuthInfos[kubeContext.AuthInfo]; ok && authInfo.Exec != nil {
+		exec := authInfo.Exec
+		settings.Region = firstNonEmpty(
+			execArg(exec.Args, "--region"),
+			execEnv(exec, "AWS_REGION"),
+			execEnv(exec, "AWS_DEFAULT_REGION"),
+		)
+		settings.Profile = firstNonEmpty(
+			execArg(exec.Args, "--profile"),
+			execEnv(exec, "AWS_PROFILE"),
+		)
+		settings.RoleARN = execArg(exec.Args, "--role-arn", "--role", "-r")
+	}
+
+	if settings.Region == "" {
+		if clusterARN, err := arn.Parse(kubeContext.Cluster); err == nil {
+			settings.Region = clusterARN.Region
+		}
+	}
+
+	if settings.Region == "" {
+		if cluster, ok := rawConfig.Clusters[kubeContext.Cluster]; ok {
+			if m := eksEndpointPattern.FindStringSubmatch(cluster.Server); m != nil {
+				settings.Region = m[1]
+			}
+		}
+	}
+
+	return settings
+}
+
+// override replaces the fields of s with the non-empty fields of flags.
+func (s awsSettings) override(flags awsSettings) awsSettings {
+	s.Region = firstNonEmpty(flags.Region, s.Region)
+	s.Profile = firstNonEmpty(flags.Profile, s.Profile)
+	s.RoleARN = firstNonEmpty(flags.RoleARN, s.RoleARN)
+	return s
+}
+
+// loadAWSConfig builds the AWS config for settings, assuming RoleARN when set.
+func loadAWSConfig(ctx context.Context, settings awsSettings) (aws.Config, error) {
+	opts := []func(*config.LoadOptions) error{
+		// Adaptive mode slows the client-side request rate when the EKS API
+		// responds with throttling errors instead of retrying at full speed.
+		config.WithRetryer(func() aws.Retryer {
+			return retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+				o.StandardOptions = append(o.StandardOptions, func(so *retry.StandardOptions) {
+					so.MaxAttempts = maxRetryAttempts
+				})
+			})
+		}),
+	}
+	if settings.Region != "" {
+		opts = append(opts, config.WithRegion(settings.Region))
+	}
+	if settings.Profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(settings.Profile))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, err
+	}
+
+	if settings.RoleARN != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), settings.RoleARN)
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+
+	return cfg, nil
+}
+
+// execArg returns the value of the first of flags present in args, accepting
+// both "--flag value" and "--flag=value".
+func execArg(args []string, flags ...string) string {
+	for _, flag := range flags {
+		for i, arg := range args {
+			if arg == flag && i+1 < len(args) {
+				return args[i+1]
+			}
+			if value, ok := strings.CutPrefix(arg, flag+"="); ok {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// execEnv returns the value of the environment variable name set by exec.
+func execEnv(exec *api.ExecConfig, name string) string {
+	for _, env := range exec.Env {
+		if env.Name == name {
+			return env.Value
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestAWSSettingsFromKubeconfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		cluster  string
+		server   string
+		exec     *api.ExecConfig
+		flags    awsSettings
+		expected awsSettings
+	}{
+		{
+			name:    "aws eks get-token with region, profile and role",
+			cluster: "arn:aws:eks:us-west-2:123456789012:cluster/prod",
+			exec: &api.ExecConfig{
+				Command: "aws",
+				Args: []string{
+					"--region", "eu-west-1",
+					"eks", "get-token",
+					"--cluster-name", "prod",
+					"--role-arn", "arn:aws:iam::123456789012:role/eks-admin",
+				},
+				Env: []api.ExecEnvVar{{Name: "AWS_PROFILE", Value: "prod"}},
+			},
+			expected: awsSettings{
+				Region:  "eu-west-1",
+				Profile: "prod",
+				RoleARN: "arn:aws:iam::123456789012:role/eks-admin",
+			},
+		},
+		{
+			name:    "equals form arguments",
+			cluster: "prod",
+			exec: &api.ExecConfig{
+				Command: "aws",
+				Args:    []string{"eks", "get-token", "--cluster-name=prod", "--region=ap-northeast-1", "--profile=ops"},
+			},
+			expected: awsSettings{
+				Region:  "ap-northeast-1",
+				Profile: "ops",
+			},
+		},
+		{
+			name:    "aws-iam-authenticator role",
+			cluster: "arn:aws:eks:us-east-2:123456789012:cluster/dev",
+			exec: &api.ExecConfig{
+				Command: "aws-iam-authenticator",
+				Args:    []string{"token", "-i", "dev", "-r", "arn:aws:iam::123456789012:role/dev"},
+			},
+			expected: awsSettings{
+				Region:  "us-east-2",
+				RoleARN: "arn:aws:iam::123456789012:role/dev",
+			},
+		},
+		{
+			name:    "region from exec env",
+			cluster: "dev",
+			exec: &api.ExecConfig{
+				Command: "aws",
+				Args:    []string{"eks", "get-token", "--cluster-name", "dev"},
+				Env:     []api.ExecEnvVar{{Name: "AWS_DEFAULT_REGION", Value: "us-east-1"}},
+			},
+			expected: awsSettings{Region: "us-east-1"},
+		},
+		{
+			name:     "region from cluster ARN without exec plugin",
+			cluster:  "arn:aws:eks:ca-central-1:123456789012:cluster/staging",
+			expected: awsSettings{Region: "ca-central-1"},
+		},
+		{
+			name:     "region from API server endpoint",
+			cluster:  "staging.ap-southeast-2.eksctl.io",
+			server:   "https://0123456789ABCDEF.gr7.ap-southeast-2.eks.amazonaws.com",
+			expected: awsSettings{Region: "ap-southeast-2"},
+		},
+		{
+			name:    "flags override kubeconfig",
+			cluster: "arn:aws:eks:us-west-2:123456789012:cluster/prod",
+			exec: &api.ExecConfig{
+				Command: "aws",
+				Args:    []string{"eks", "get-token", "--cluster-name", "prod", "--profile", "prod"},
+			},
+			flags: awsSettings{
+				Region:  "us-east-1",
+				RoleARN: "arn:aws:iam::123456789012:role/readonly",
+			},
+			expected: awsSettings{
+				Region:  "us-east-1",
+				Profile: "prod",
+				RoleARN: "arn:aws:iam::123456789012:role/readonly",
+			},
+		},
+		{
+			name:     "non-EKS context",
+			cluster:  "kind-kind",
+			server:   "https://127.0.0.1:6443",
+			expected: awsSettings{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rawConfig := api.Config{
+				Clusters: map[string]*api.Cluster{
+					tt.cluster: {Server: tt.server},
+				},
+				AuthInfos: map[string]*api.AuthInfo{
+					"user": {Exec: tt.exec},
+				},
+			}
+			kubeContext := &api.Context{Cluster: tt.cluster, AuthInfo: "user"}
+
+			got := awsSettingsFromKubeconfig(rawConfig, kubeContext).override(tt.flags)
+			if got != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/keidarcy/kubectl-eks-viewer/pkg/filter"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/printers"
@@ -18,6 +22,8 @@ type Cluster = types.Cluster
 type ClusterList struct {
 	metav1.TypeMeta
 	Items []types.Cluster
+	// clusters are the clusters of the items when viewing several contexts
+	clusters []clusterRef
 }
 
 // Implement runtime.Object interface
@@ -29,10 +35,11 @@ func (c *ClusterList) DeepCopyObject() runtime.Object {
 	return &ClusterList{
 		TypeMeta: c.TypeMeta,
 		Items:    append([]types.Cluster(nil), c.Items...),
+		clusters: append([]clusterRef(nil), c.clusters...),
 	}
 }
 
-func NewClusterPrinter() printers.ResourcePrinter {
+func NewClusterPrinter(options printers.PrintOptions) printers.ResourcePrinter {
 	return printers.ResourcePrinterFunc(func(obj runtime.Object, w io.Writer) error {
 		list, ok := obj.(*ClusterList)
 		if !ok {
@@ -50,6 +57,11 @@ func NewClusterPrinter() printers.ResourcePrinter {
 				{Name: "STATUS", Type: "string"},
 				{Name: "PLATFORM VERSION", Type: "string"},
 				{Name: "AUTH MODE", Type: "string"},
+				{Name: "ENDPOINT ACCESS", Type: "string", Priority: 1},
+				{Name: "OIDC ISSUER", Type: "string", Priority: 1},
+				{Name: "SERVICE CIDR", Type: "string", Priority: 1},
+				{Name: "IP FAMILY", Type: "string", Priority: 1},
+				{Name: "LOGGING", Type: "string", Priority: 1},
 			},
 		}
 
@@ -59,6 +71,20 @@ func NewClusterPrinter() printers.ResourcePrinter {
 				authMode = string(item.AccessConfig.AuthenticationMode)
 			}
 
+			oidcIssuer := "<none>"
+			if item.Identity != nil && item.Identity.Oidc != nil {
+				oidcIssuer = stringOrNone(item.Identity.Oidc.Issuer)
+			}
+
+			serviceCIDR, ipFamily := "<none>", "<none>"
+			if network := item.KubernetesNetworkConfig; network != nil {
+				serviceCIDR = stringOrNone(network.ServiceIpv4Cidr)
+				if network.ServiceIpv6Cidr != nil {
+					serviceCIDR = *network.ServiceIpv6Cidr
+				}
+				ipFamily = string(network.IpFamily)
+			}
+
 			table.Rows = append(table.Rows, metav1.TableRow{
 				Cells: []interface{}{
 					*item.Name,
@@ -66,11 +92,18 @@ func NewClusterPrinter() printers.ResourcePrinter {
 					string(item.Status),
 					*item.PlatformVersion,
 					authMode,
+					formatEndpointAccess(item.ResourcesVpcConfig),
+					oidcIssuer,
+					serviceCIDR,
+					ipFamily,
+					joinOrNone(enabledLogTypes(item.Logging)),
 				},
 			})
 		}
 
-		return printTable(w, table, "cluster")
+		addClusterColumns(table, list.clusters)
+		addTagsColumn(table, options, func(i int) map[string]string { return list.Items[i].Tags })
+		return printTable(w, table, "cluster", options)
 	})
 }
 
@@ -87,14 +120,185 @@ func (c *EKSClient) DescribeCluster(ctx context.Context) ([]types.Cluster, error
 	return []types.Cluster{*result.Cluster}, nil
 }
 
-func printTable(w io.Writer, table *metav1.Table, resourceType string) error {
+func printSectionHeader(w io.Writer, resourceType string) {
 	fmt.Fprintf(w, "=== %s ===\n", resourceType)
+}
+
+// printTable prints a resource section. Columns with a non-zero priority are
+// only shown when options.Wide is set.
+func printTable(w io.Writer, table *metav1.Table, resourceType string, options printers.PrintOptions) error {
+	printSectionHeader(w, resourceType)
 
 	if len(table.Rows) == 0 {
 		fmt.Fprintf(w, "<none>\n")
 		return nil
 	}
 
-	printer := printers.NewTablePrinter(printers.PrintOptions{})
+	// The TAGS column replaces the LABELS column of the table printer
+	options.ShowLabels = false
+	printer := printers.NewTablePrinter(options)
 	return printer.PrintObj(table, w)
 }
+
+// addTagsColumn appends the TAGS column shown by --show-tags, which sets
+// options.ShowLabels as AWS tags take the place of labels. tagsOf returns the
+// tags of the item printed in row i.
+func addTagsColumn(table *metav1.Table, options printers.PrintOptions, tagsOf func(i int) map[string]string) {
+	if !options.ShowLabels {
+		return
+	}
+
+	table.ColumnDefinitions = append(table.ColumnDefinitions, metav1.TableColumnDefinition{Name: "TAGS", Type: "string"})
+	for i := range table.Rows {
+		table.Rows[i].Cells = append(table.Rows[i].Cells, filter.FormatTags(tagsOf(i)))
+	}
+}
+
+// addClusterColumns prepends the cluster of every row when viewing several
+// contexts, with its region and account in wide output. clusters holds the
+// cluster of the item printed in each row and is nil for a single context.
+func addClusterColumns(table *metav1.Table, clusters []clusterRef) {
+	if clusters == nil {
+		return
+	}
+
+	table.ColumnDefinitions = append([]metav1.TableColumnDefinition{
+		{Name: "CLUSTER", Type: "string"},
+		{Name: "REGION", Type: "string", Priority: 1},
+		{Name: "ACCOUNT", Type: "string", Priority: 1},
+	}, table.ColumnDefinitions...)
+	for i := range table.Rows {
+		cluster := clusters[i]
+		region, account := "<none>", "<none>"
+		if cluster.Region != "" {
+			region = cluster.Region
+		}
+		if cluster.Account != "" {
+			account = cluster.Account
+		}
+		table.Rows[i].Cells = append([]interface{}{cluster.Cluster, region, account}, table.Rows[i].Cells...)
+	}
+}
+
+// tableTime formats a timestamp for a table cell.
+func tableTime(t *time.Time) string {
+	if t == nil {
+		return "<none>"
+	}
+	return t.Format(time.RFC3339)
+}
+
+// formatEndpointAccess summarizes whether the API server endpoint is public,
+// private or both.
+func formatEndpointAccess(vpc *types.VpcConfigResponse) string {
+	if vpc == nil {
+		return "<none>"
+	}
+	var access []string
+	if vpc.EndpointPublicAccess {
+		access = append(access, "public")
+	}
+	if vpc.EndpointPrivateAccess {
+		access = append(access, "private")
+	}
+	return joinOrNone(access)
+}
+
+func describeCluster(w *prefixWriter, item types.Cluster) {
+	w.Write(levelZero, "Name:\t%s\n", stringOrNone(item.Name))
+	w.Write(levelZero, "ARN:\t%s\n", stringOrNone(item.Arn))
+	w.Write(levelZero, "Status:\t%s\n", item.Status)
+	w.Write(levelZero, "Version:\t%s\n", stringOrNone(item.Version))
+	w.Write(levelZero, "Platform Version:\t%s\n", stringOrNone(item.PlatformVersion))
+	w.Write(levelZero, "Endpoint:\t%s\n", stringOrNone(item.Endpoint))
+	w.Write(levelZero, "Role ARN:\t%s\n", stringOrNone(item.RoleArn))
+	w.Write(levelZero, "Created At:\t%s\n", timeOrNone(item.CreatedAt))
+
+	authMode := "<none>"
+	if item.AccessConfig != nil {
+		authMode = string(item.AccessConfig.AuthenticationMode)
+	}
+	w.Write(levelZero, "Authentication Mode:\t%s\n", authMode)
+
+	oidcIssuer := "<none>"
+	if item.Identity != nil && item.Identity.Oidc != nil {
+		oidcIssuer = stringOrNone(item.Identity.Oidc.Issuer)
+	}
+	w.Write(levelZero, "OIDC Issuer:\t%s\n", oidcIssuer)
+
+	supportType := "<none>"
+	if item.UpgradePolicy != nil {
+		supportType = string(item.UpgradePolicy.SupportType)
+	}
+	w.Write(levelZero, "Upgrade Policy:\t%s\n", supportType)
+
+	w.Write(levelZero, "Networking:\n")
+	if vpc := item.ResourcesVpcConfig; vpc != nil {
+		w.Write(levelOne, "VPC:\t%s\n", stringOrNone(vpc.VpcId))
+		w.WriteList(levelOne, "Subnets", vpc.SubnetIds)
+		w.WriteList(levelOne, "Security Groups", vpc.SecurityGroupIds)
+		w.Write(levelOne, "Cluster Security Group:\t%s\n", stringOrNone(vpc.ClusterSecurityGroupId))
+		w.Write(levelOne, "Endpoint Public Access:\t%t\n", vpc.EndpointPublicAccess)
+		w.Write(levelOne, "Endpoint Private Access:\t%t\n", vpc.EndpointPrivateAccess)
+		w.WriteList(levelOne, "Public Access CIDRs", vpc.PublicAccessCidrs)
+	}
+	if network := item.KubernetesNetworkConfig; network != nil {
+		w.Write(levelOne, "IP Family:\t%s\n", network.IpFamily)
+		w.Write(levelOne, "Service IPv4 CIDR:\t%s\n", stringOrNone(network.ServiceIpv4Cidr))
+		if network.ServiceIpv6Cidr != nil {
+			w.Write(levelOne, "Service IPv6 CIDR:\t%s\n", *network.ServiceIpv6Cidr)
+		}
+	}
+
+	w.WriteList(levelZero, "Logging", enabledLogTypes(item.Logging))
+
+	var issues []healthIssue
+	if item.Health != nil {
+		for _, issue := range item.Health.Issues {
+			issues = append(issues, healthIssue{code: string(issue.Code), message: issue.Message, resourceIDs: issue.ResourceIds})
+		}
+	}
+	w.WriteHealthIssues(levelZero, issues)
+	w.WriteMap(levelZero, "Tags", item.Tags)
+}
+
+// enabledLogTypes returns the control plane log types that are enabled.
+func enabledLogTypes(logging *types.Logging) []string {
+	var enabled []string
+	if logging == nil {
+		return nil
+	}
+	for _, setup := range logging.ClusterLogging {
+		if setup.Enabled == nil || !*setup.Enabled {
+			continue
+		}
+		for _, logType := range setup.Types {
+			enabled = append(enabled, string(logType))
+		}
+	}
+	return enabled
+}
+
+// clusterFields are the fields matched by --field-selector.
+func clusterFields(item types.Cluster) []fields.Set {
+	set := fields.Set{
+		"name":            aws.ToString(item.Name),
+		"status":          string(item.Status),
+		"version":         aws.ToString(item.Version),
+		"platformVersion": aws.ToString(item.PlatformVersion),
+		"authMode":        "",
+	}
+	if item.AccessConfig != nil {
+		set["authMode"] = string(item.AccessConfig.AuthenticationMode)
+	}
+	return []fields.Set{set}
+}
+
+func clusterNames(item types.Cluster) []string {
+	return []string{aws.ToString(item.Name)}
+}
+
+// clusterTags are the tags matched by --selector.
+func clusterTags(item types.Cluster) map[string]string {
+	return item.Tags
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Strategies reported by resolveClusterName, in the order they are tried.
+const (
+	strategyFlag              = "--cluster-name flag"
+	strategyExecArgs          = "exec plugin arguments"
+	strategyClusterARN        = "cluster ARN"
+	strategyEksctl            = "eksctl cluster name"
+	strategyEndpoint          = "API server endpoint"
+	strategyKubeconfigCluster = "kubeconfig cluster name"
+)
+
+// eksctlClusterPattern matches kubeconfig cluster names written by eksctl:
+// <cluster-name>.<region>.eksctl.io
+var eksctlClusterPattern = regexp.MustCompile(`^(.+)\.[a-z]{2}(?:-[a-z]+)+-\d+\.eksctl\.io$`)
+
+// resolveClusterName identifies the EKS cluster behind a kubeconfig context and
+// returns its name together with the strategy that matched. The client is only
+// used to match the API server endpoint against the clusters in its region;
+// why it did not match is written to verbose, which may be nil.
+func resolveClusterName(ctx context.Context, rawConfig api.Config, kubeContext *api.Context, flagClusterName string, client *EKSClient, verbose io.Writer) (string, string, error) {
+	if flagClusterName != "" {
+		return flagClusterName, strategyFlag, nil
+	}
+
+	if name, ok := clusterNameFromKubeconfig(rawConfig, kubeContext); ok {
+		return name.name, name.strategy, nil
+	}
+
+	var endpointErr error
+	if cluster, ok := rawConfig.Clusters[kubeContext.Cluster]; ok && eksEndpointPattern.MatchString(cluster.Server) {
+		name, err := client.FindClusterByEndpoint(ctx, cluster.Server)
+		if err == nil {
+			return name, strategyEndpoint, nil
+		}
+		endpointErr = err
+		if verbose != nil {
+			fmt.Fprintf(verbose, "Could not match the API server endpoint %s: %v\n", cluster.Server, err)
+		}
+	}
+
+	if kubeContext.Cluster == "" {
+		if endpointErr != nil {
+			return "", "", fmt.Errorf("could not identify the EKS cluster for this context, use --cluster-name: matching the API server endpoint: %v", endpointErr)
+		}
+		return "", "", fmt.Errorf("could not identify the EKS cluster for this context, use --cluster-name")
+	}
+	return kubeContext.Cluster, strategyKubeconfigCluster, nil
+}
+
+// isEKSContext reports whether a kubeconfig context points to an EKS cluster,
+// judging by the kubeconfig alone.
+func isEKSContext(rawConfig api.Config, kubeContext *api.Context) bool {
+	if _, ok := clusterNameFromKubeconfig(rawConfig, kubeContext); ok {
+		return true
+	}
+	cluster, ok := rawConfig.Clusters[kubeContext.Cluster]
+	return ok && eksEndpointPattern.MatchString(cluster.Server)
+}
+
+type resolvedClusterName struct {
+	name     string
+	strategy string
+}
+
+// clusterNameFromKubeconfig tries the strategies that need nothing but the
+// kubeconfig: the exec plugin arguments, the cluster ARN and eksctl naming.
+func clusterNameFromKubeconfig(rawConfig api.Config, kubeContext *api.Context) (resolvedClusterName, bool) {
+	if authInfo, ok := rawConfig.AuthInfos[kubeContext.AuthInfo]; ok && authInfo.Exec != nil {
+		// aws eks get-token uses --cluster-name, aws-iam-authenticator -i/--cluster-id
+		if name := execArg(authInfo.Exec.Args, "--cluster-name", "--cluster-id", "-i"); name != "" {
+			return resolvedClusterName{name: name, strategy: strategyExecArgs}, true
+		}
+	}
+
+	// arn:aws:eks:<region>:<account>:cluster/<cluster-name>
+	if clusterARN, err := arn.Parse(kubeContext.Cluster); err == nil && clusterARN.Service == "eks" {
+		if name, ok := strings.CutPrefix(clusterARN.Resource, "cluster/"); ok && name != "" {
+			return resolvedClusterName{name: name, strategy: strategyClusterARN}, true
+		}
+	}
+
+	if m := eksctlClusterPattern.FindStringSubmatch(kubeContext.Cluster); m != nil {
+		return resolvedClusterName{name: m[1], strategy: strategyEksctl}, true
+	}
+
+	return resolvedClusterName{}, false
+}
+
+// FindClusterByEndpoint returns the name of the cluster in the client's region
+// whose API server endpoint is endpoint.
+func (c *EKSClient) FindClusterByEndpoint(ctx context.Context, endpoint string) (string, error) {
+	// Clusters that cannot be described are skipped, one of the others may still match
+	clusters, err := c.ListClusters(ctx)
+	if err != nil && !isPartialError(err) {
+		return "", err
+	}
+
+	for _, cluster := range clusters {
+		if cluster.Endpoint != nil && sameEndpoint(*cluster.Endpoint, endpoint) {
+			return *cluster.Name, nil
+		}
+	}
+	return "", fmt.Errorf("no EKS cluster found with endpoint %s", endpoint)
+}
+
+func sameEndpoint(a, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "/"), strings.TrimSuffix(b, "/"))
+}
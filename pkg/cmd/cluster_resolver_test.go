@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestResolveClusterName(t *testing.T) {
+	tests := []struct {
+		name             string
+		cluster          string
+		server           string
+		exec             *api.ExecConfig
+		flagClusterName  string
+		expectedName     string
+		expectedStrategy string
+	}{
+		{
+			name:             "flag wins over everything",
+			cluster:          "arn:aws:eks:us-west-2:123456789012:cluster/prod",
+			flagClusterName:  "staging",
+			expectedName:     "staging",
+			expectedStrategy: strategyFlag,
+		},
+		{
+			name:    "aws eks get-token cluster name on a renamed context",
+			cluster: "prod-renamed",
+			exec: &api.ExecConfig{
+				Command: "aws",
+				Args:    []string{"--region", "us-west-2", "eks", "get-token", "--cluster-name", "prod"},
+			},
+			expectedName:     "prod",
+			expectedStrategy: strategyExecArgs,
+		},
+		{
+			name:    "aws-iam-authenticator cluster id",
+			cluster: "lens-generated",
+			exec: &api.ExecConfig{
+				Command: "aws-iam-authenticator",
+				Args:    []string{"token", "-i", "payments"},
+			},
+			expectedName:     "payments",
+			expectedStrategy: strategyExecArgs,
+		},
+		{
+			name:             "cluster ARN",
+			cluster:          "arn:aws:eks:us-west-2:123456789012:cluster/prod",
+			expectedName:     "prod",
+			expectedStrategy: strategyClusterARN,
+		},
+		{
+			name:             "eksctl name containing dots",
+			cluster:          "team.payments.us-east-1.eksctl.io",
+			expectedName:     "team.payments",
+			expectedStrategy: strategyEksctl,
+		},
+		{
+			name:             "rancher kubeconfig matched by endpoint",
+			cluster:          "rancher-imported",
+			server:           "https://BBBBBBBBBBBBBBBB.gr7.us-west-2.eks.amazonaws.com",
+			expectedName:     "cluster-b",
+			expectedStrategy: strategyEndpoint,
+		},
+		{
+			name:             "unmatched endpoint falls back to kubeconfig cluster name",
+			cluster:          "my-cluster",
+			server:           "https://CCCCCCCCCCCCCCCC.gr7.us-west-2.eks.amazonaws.com",
+			expectedName:     "my-cluster",
+			expectedStrategy: strategyKubeconfigCluster,
+		},
+		{
+			name:             "teleport proxy falls back to kubeconfig cluster name",
+			cluster:          "teleport.example.com-prod",
+			server:           "https://teleport.example.com:3026",
+			expectedName:     "teleport.example.com-prod",
+			expectedStrategy: strategyKubeconfigCluster,
+		},
+	}
+
+	mockClient := newMockEKSClient()
+	mockClient.listClustersFunc = func(ctx context.Context, params *eks.ListClustersInput) (*eks.ListClustersOutput, error) {
+		if params.NextToken == nil {
+			return &eks.ListClustersOutput{Clusters: []string{"cluster-a", "cluster-denied"}, NextToken: stringPtr("page-2")}, nil
+		}
+		return &eks.ListClustersOutput{Clusters: []string{"cluster-b"}}, nil
+	}
+	mockClient.describeClusterFunc = func(ctx context.Context, params *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error) {
+		endpoints := map[string]string{
+			"cluster-a": "https://AAAAAAAAAAAAAAAA.gr7.us-west-2.eks.amazonaws.com",
+			"cluster-b": "https://bbbbbbbbbbbbbbbb.gr7.us-west-2.eks.amazonaws.com",
+		}
+		endpoint, ok := endpoints[*params.Name]
+		if !ok {
+			return nil, errors.New("AccessDeniedException")
+		}
+		return &eks.DescribeClusterOutput{
+			Cluster: &types.Cluster{Name: params.Name, Endpoint: stringPtr(endpoint)},
+		}, nil
+	}
+	client := &EKSClient{client: mockClient}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rawConfig := api.Config{
+				Clusters: map[string]*api.Cluster{
+					tt.cluster: {Server: tt.server},
+				},
+				AuthInfos: map[string]*api.AuthInfo{
+					"user": {Exec: tt.exec},
+				},
+			}
+			kubeContext := &api.Context{Cluster: tt.cluster, AuthInfo: "user"}
+
+			name, strategy, err := resolveClusterName(context.Background(), rawConfig, kubeContext, tt.flagClusterName, client, nil)
+			if err != nil {
+				t.Fatalf("resolveClusterName returned error: %v", err)
+			}
+			if name != tt.expectedName {
+				t.Errorf("expected cluster name %q, got %q", tt.expectedName, name)
+			}
+			if strategy != tt.expectedStrategy {
+				t.Errorf("expected strategy %q, got %q", tt.expectedStrategy, strategy)
+			}
+		})
+	}
+}
+
+func TestResolveClusterNameEndpointError(t *testing.T) {
+	mockClient := newMockEKSClient()
+	mockClient.listClustersFunc = func(ctx context.Context, params *eks.ListClustersInput) (*eks.ListClustersOutput, error) {
+		return nil, errors.New("AccessDeniedException: not authorized to perform eks:ListClusters")
+	}
+	client := &EKSClient{client: mockClient}
+	server := "https://AAAAAAAAAAAAAAAA.gr7.us-west-2.eks.amazonaws.com"
+
+	tests := []struct {
+		name        string
+		cluster     string
+		expectedErr bool
+	}{
+		{name: "falls back to kubeconfig cluster name", cluster: "my-cluster"},
+		{name: "no kubeconfig cluster name", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rawConfig := api.Config{Clusters: map[string]*api.Cluster{tt.cluster: {Server: server}}}
+			kubeContext := &api.Context{Cluster: tt.cluster}
+			verbose := &bytes.Buffer{}
+
+			_, _, err := resolveClusterName(context.Background(), rawConfig, kubeContext, "", client, verbose)
+			if !strings.Contains(verbose.String(), "AccessDeniedException") {
+				t.Errorf("expected the endpoint error to be reported, got %q", verbose.String())
+			}
+			if tt.expectedErr != (err != nil) || (err != nil && !strings.Contains(err.Error(), "AccessDeniedException")) {
+				t.Errorf("expected error %t containing the endpoint error, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"k8s.io/cli-runtime/pkg/printers"
 )
 
 func TestNewClusterPrinter(t *testing.T) {
@@ -65,7 +66,7 @@ func TestNewClusterPrinter(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create printer
-			printer := NewClusterPrinter()
+			printer := NewClusterPrinter(printers.PrintOptions{})
 
 			// Create buffer to capture output
 			buf := &bytes.Buffer{}
@@ -91,3 +92,66 @@ func TestNewClusterPrinter(t *testing.T) {
 		})
 	}
 }
+
+func TestNewClusterPrinterWide(t *testing.T) {
+	list := &ClusterList{
+		Items: []types.Cluster{
+			{
+				Name:            stringPtr("prod"),
+				Version:         stringPtr("1.31"),
+				Status:          types.ClusterStatusActive,
+				PlatformVersion: stringPtr("eks.5"),
+				ResourcesVpcConfig: &types.VpcConfigResponse{
+					EndpointPublicAccess:  true,
+					EndpointPrivateAccess: true,
+				},
+				Identity: &types.Identity{
+					Oidc: &types.OIDC{Issuer: stringPtr("https://oidc.eks.us-west-2.amazonaws.com/id/ABC")},
+				},
+				KubernetesNetworkConfig: &types.KubernetesNetworkConfigResponse{
+					ServiceIpv4Cidr: stringPtr("172.20.0.0/16"),
+					IpFamily:        types.IpFamilyIpv4,
+				},
+				Logging: &types.Logging{
+					ClusterLogging: []types.LogSetup{
+						{Enabled: boolPtr(true), Types: []types.LogType{types.LogTypeApi, types.LogTypeAudit}},
+						{Enabled: boolPtr(false), Types: []types.LogType{types.LogTypeScheduler}},
+					},
+				},
+			},
+		},
+	}
+	wideOutput := []string{
+		"ENDPOINT ACCESS",
+		"OIDC ISSUER",
+		"SERVICE CIDR",
+		"IP FAMILY",
+		"LOGGING",
+		"public,private",
+		"https://oidc.eks.us-west-2.amazonaws.com/id/ABC",
+		"172.20.0.0/16",
+		"ipv4",
+		"api,audit",
+	}
+
+	for _, wide := range []bool{false, true} {
+		buf := &bytes.Buffer{}
+		if err := NewClusterPrinter(printers.PrintOptions{Wide: wide}).PrintObj(list, buf); err != nil {
+			t.Fatalf("PrintObj returned error: %v", err)
+		}
+
+		output := buf.String()
+		for _, expected := range wideOutput {
+			if strings.Contains(output, expected) != wide {
+				t.Errorf("wide=%t: unexpected presence of %q\nGot: %s", wide, expected, output)
+			}
+		}
+		if strings.Contains(output, "scheduler") {
+			t.Errorf("disabled log types should not be listed\nGot: %s", output)
+		}
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
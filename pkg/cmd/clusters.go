@@ -0,0 +1,304 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// defaultRegionsEndpointRegion is the region asked for the enabled regions
+// when no region is configured.
+const defaultRegionsEndpointRegion = "us-east-1"
+
+// DiscoveredClusterList is the output of the clusters command.
+type DiscoveredClusterList struct {
+	metav1.TypeMeta
+	Errors []ResourceError `json:"errors,omitempty"`
+	Items  []Cluster       `json:"items"`
+}
+
+// Implement runtime.Object interface
+func (d *DiscoveredClusterList) GetObjectKind() schema.ObjectKind {
+	return &d.TypeMeta
+}
+
+func (d *DiscoveredClusterList) DeepCopyObject() runtime.Object {
+	return &DiscoveredClusterList{
+		TypeMeta: d.TypeMeta,
+		Errors:   append([]ResourceError(nil), d.Errors...),
+		Items:    append([]Cluster(nil), d.Items...),
+	}
+}
+
+func NewClustersCmd(o *Options) *cobra.Command {
+	var (
+		regions    []string
+		allRegions bool
+	)
+	printFlags := genericclioptions.NewPrintFlags("")
+
+	cmd := &cobra.Command{
+		Use:   "clusters [--region REGION...|--all-regions]",
+		Short: "List the EKS clusters of the AWS account",
+		Long: `List the EKS clusters of the AWS account, whether or not they are in the
+kubeconfig. Clusters are listed in the region of the AWS settings by default,
+in the regions given with --region, or in every region enabled for the
+account with --all-regions.
+
+The AWS credentials come from --profile, --role-arn and the AWS SDK defaults.
+View the resources of a cluster found this way with --cluster-name,
+--region and --no-kubeconfig.`,
+		Example: `  # List the clusters of the default region
+  kubectl eks-viewer clusters
+
+  # List the clusters of several regions, or of every enabled region
+  kubectl eks-viewer clusters --region us-east-1,eu-west-1
+  kubectl eks-viewer clusters --all-regions -o wide
+
+  # View a cluster without a kubeconfig entry
+  kubectl eks-viewer --cluster-name my-cluster --region eu-west-1 --no-kubeconfig`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if allRegions && len(regions) > 0 {
+				return fmt.Errorf("--region and --all-regions cannot be used together")
+			}
+			o.printFlags = printFlags
+
+			ctx := context.Background()
+			selected, err := o.clusterRegions(ctx, regions, allRegions)
+			if err != nil {
+				return err
+			}
+			return o.RunClusters(ctx, selected, o.newRegionClient(newWorkerPool(o.concurrency)))
+		},
+	}
+
+	// Shadows the persistent --region flag, which takes a single region
+	cmd.Flags().StringSliceVar(&regions, "region", nil, "AWS regions to list the clusters of. May be repeated or comma separated. Defaults to the region of the AWS settings")
+	cmd.Flags().BoolVar(&allRegions, "all-regions", false, "List the clusters of every region enabled for the account")
+	printFlags.AddFlags(cmd)
+	cmd.Flags().Lookup("output").Usage = fmt.Sprintf("Output format. One of: (%s).",
+		strings.Join(append(printFlags.AllowedFormats(), "wide"), ", "))
+
+	return cmd
+}
+
+// clusterRegions returns the regions to list clusters in: the given regions,
+// every region enabled for the account, or the region of the AWS settings.
+func (o *Options) clusterRegions(ctx context.Context, regions []string, allRegions bool) ([]string, error) {
+	if len(regions) > 0 {
+		return regions, nil
+	}
+
+	cfg, err := loadAWSConfig(ctx, awsSettings{}.override(o.awsFlags))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %v", err)
+	}
+	if !allRegions {
+		if cfg.Region == "" {
+			return nil, fmt.Errorf("no AWS region configured, use --region or --all-regions")
+		}
+		return []string{cfg.Region}, nil
+	}
+
+	if cfg.Region == "" {
+		cfg.Region = defaultRegionsEndpointRegion
+	}
+	// Only the regions enabled for the account are returned by default
+	result, err := ec2.NewFromConfig(cfg).DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the enabled regions: %v", err)
+	}
+	for _, region := range result.Regions {
+		regions = append(regions, aws.ToString(region.RegionName))
+	}
+	sort.Strings(regions)
+	return regions, nil
+}
+
+// newRegionClient returns a function creating the EKS client of a region.
+// The clients of all regions share pool.
+func (o *Options) newRegionClient(pool *workerPool) func(region string) (*EKSClient, error) {
+	return func(region string) (*EKSClient, error) {
+		settings := awsSettings{}.override(o.awsFlags)
+		settings.Region = region
+		eksClient, err := NewEKSClient(nil, o.concurrency, settings)
+		if err != nil {
+			return nil, err
+		}
+		eksClient.pool = pool
+		return eksClient, nil
+	}
+}
+
+// ListClusters describes every cluster in the client's region. Clusters that
+// cannot be described are reported in a *PartialError.
+func (c *EKSClient) ListClusters(ctx context.Context) ([]Cluster, error) {
+	var clusterNames []string
+	paginator := eks.NewListClustersPaginator(c.client, &eks.ListClustersInput{})
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		clusterNames = append(clusterNames, result.Clusters...)
+	}
+
+	return describeAll(ctx, c.pool, clusterNames, stringName, func(ctx context.Context, name string) (Cluster, error) {
+		result, err := c.client.DescribeCluster(ctx, &eks.DescribeClusterInput{Name: &name})
+		if err != nil {
+			return Cluster{}, err
+		}
+		return *result.Cluster, nil
+	})
+}
+
+// RunClusters lists the clusters of every region concurrently. A region that
+// cannot be listed is reported without failing the others.
+func (o *Options) RunClusters(ctx context.Context, regions []string, connect func(region string) (*EKSClient, error)) error {
+	type regionClusters struct {
+		clusters []Cluster
+		err      error
+	}
+
+	progress := newProgressReporter(o.ErrOut, o.quiet)
+	progress.Start(regions)
+	results, err := mapConcurrent(ctx, nil, regions, func(ctx context.Context, region string) (regionClusters, error) {
+		defer progress.Done(region)
+		eksClient, err := connect(region)
+		if err != nil {
+			return regionClusters{err: err}, nil
+		}
+		clusters, err := eksClient.ListClusters(ctx)
+		return regionClusters{clusters: clusters, err: err}, nil
+	})
+	progress.Clear()
+	if err != nil {
+		return err
+	}
+
+	list := &DiscoveredClusterList{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EksClusterList"},
+		Items:    []Cluster{},
+	}
+	failedRegions := 0
+	for i, result := range results {
+		list.Items = append(list.Items, result.clusters...)
+		switch {
+		case result.err == nil:
+		case isPartialError(result.err):
+			list.Errors = append(list.Errors, toResourceErrors("clusters", result.err)...)
+		default:
+			failedRegions++
+			list.Errors = append(list.Errors, ResourceError{
+				ResourceType: "clusters",
+				Message:      fmt.Sprintf("%s: %v", regions[i], result.err),
+			})
+		}
+	}
+	// By region, then by name
+	sortItems(list.Items, []string{"{.Arn}", "{.Name}"})
+
+	if err := o.printClusters(o.Out, list); err != nil {
+		return err
+	}
+	if failedRegions == len(regions) {
+		return fmt.Errorf("failed to list clusters in %d region(s)", failedRegions)
+	}
+	return nil
+}
+
+func (o *Options) printClusters(out io.Writer, list *DiscoveredClusterList) error {
+	if !o.isTableFormat() {
+		printer, err := o.printFlags.ToPrinter()
+		if err != nil {
+			return err
+		}
+		if err := printer.PrintObj(list, out); err != nil {
+			return err
+		}
+		printResourceErrors(o.ErrOut, list.Errors)
+		return nil
+	}
+
+	options := printers.PrintOptions{Wide: *o.printFlags.OutputFormat == "wide"}
+	if err := NewDiscoveredClusterPrinter(options).PrintObj(list, out); err != nil {
+		return err
+	}
+	printResourceErrors(out, list.Errors)
+	return nil
+}
+
+func NewDiscoveredClusterPrinter(options printers.PrintOptions) printers.ResourcePrinter {
+	return printers.ResourcePrinterFunc(func(obj runtime.Object, w io.Writer) error {
+		list, ok := obj.(*DiscoveredClusterList)
+		if !ok {
+			return fmt.Errorf("expected *DiscoveredClusterList, got %T", obj)
+		}
+
+		table := &metav1.Table{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "Cluster",
+			},
+			ColumnDefinitions: []metav1.TableColumnDefinition{
+				{Name: "NAME", Type: "string"},
+				{Name: "REGION", Type: "string"},
+				{Name: "VERSION", Type: "string"},
+				{Name: "STATUS", Type: "string"},
+				{Name: "PLATFORM VERSION", Type: "string"},
+				{Name: "ENDPOINT ACCESS", Type: "string"},
+				{Name: "ACCOUNT", Type: "string", Priority: 1},
+				{Name: "AUTH MODE", Type: "string", Priority: 1},
+				{Name: "ENDPOINT", Type: "string", Priority: 1},
+			},
+		}
+
+		for _, item := range list.Items {
+			region, account := "<none>", "<none>"
+			if item.Arn != nil {
+				if clusterARN, err := arn.Parse(*item.Arn); err == nil {
+					region, account = clusterARN.Region, clusterARN.AccountID
+				}
+			}
+
+			authMode := "<none>"
+			if item.AccessConfig != nil {
+				authMode = string(item.AccessConfig.AuthenticationMode)
+			}
+
+			table.Rows = append(table.Rows, metav1.TableRow{
+				Cells: []interface{}{
+					stringOrNone(item.Name),
+					region,
+					stringOrNone(item.Version),
+					string(item.Status),
+					stringOrNone(item.PlatformVersion),
+					formatEndpointAccess(item.ResourcesVpcConfig),
+					account,
+					authMode,
+					stringOrNone(item.Endpoint),
+				},
+			})
+		}
+
+		return printTable(w, table, "clusters", options)
+	})
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+)
+
+// connectRegion returns a client for regions with two pages of clusters, and
+// fails for ap-south-2 as if the region were not enabled.
+func connectRegion(region string) (*EKSClient, error) {
+	mockClient := newMockEKSClient()
+	mockClient.listClustersFunc = func(ctx context.Context, params *eks.ListClustersInput) (*eks.ListClustersOutput, error) {
+		if region == "ap-south-2" {
+			return nil, errors.New("UnrecognizedClientException: The security token included in the request is invalid")
+		}
+		if params.NextToken == nil {
+			return &eks.ListClustersOutput{Clusters: []string{region + "-b"}, NextToken: stringPtr("page-2")}, nil
+		}
+		return &eks.ListClustersOutput{Clusters: []string{region + "-a", region + "-denied"}}, nil
+	}
+	mockClient.describeClusterFunc = func(ctx context.Context, params *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error) {
+		if strings.HasSuffix(*params.Name, "-denied") {
+			return nil, errors.New("AccessDeniedException: not authorized to perform eks:DescribeCluster")
+		}
+		return &eks.DescribeClusterOutput{
+			Cluster: &types.Cluster{
+				Name:               params.Name,
+				Arn:                stringPtr("arn:aws:eks:" + region + ":123456789012:cluster/" + *params.Name),
+				Version:            stringPtr("1.31"),
+				Status:             types.ClusterStatusActive,
+				PlatformVersion:    stringPtr("eks.1"),
+				ResourcesVpcConfig: &types.VpcConfigResponse{EndpointPublicAccess: true},
+			},
+		}, nil
+	}
+	return &EKSClient{client: mockClient, pool: newWorkerPool(2)}, nil
+}
+
+func TestRunClusters(t *testing.T) {
+	o, out, _ := newTestOptions(newMockEKSClient(), "")
+
+	err := o.RunClusters(context.Background(), []string{"us-east-1", "eu-west-1", "ap-south-2"}, connectRegion)
+	if err != nil {
+		t.Fatalf("RunClusters returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	var names []string
+	for _, line := range lines[2:6] {
+		fields := strings.Fields(line)
+		names = append(names, fields[0]+" "+fields[1])
+		if fields[5] != "public" {
+			t.Errorf("expected public endpoint access, got %q", line)
+		}
+	}
+	expected := []string{"eu-west-1-a eu-west-1", "eu-west-1-b eu-west-1", "us-east-1-a us-east-1", "us-east-1-b us-east-1"}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected clusters %v, got %v\n%s", expected, names, out.String())
+	}
+
+	for _, expected := range []string{
+		`error: failed to describe "us-east-1-denied": AccessDeniedException`,
+		"error: failed to list clusters: ap-south-2: UnrecognizedClientException",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Output does not contain expected string: %s\nGot: %s", expected, out.String())
+		}
+	}
+}
+
+func TestRunClustersJSON(t *testing.T) {
+	o, out, errOut := newTestOptions(newMockEKSClient(), "json")
+
+	if err := o.RunClusters(context.Background(), []string{"us-east-1"}, connectRegion); err != nil {
+		t.Fatalf("RunClusters returned error: %v", err)
+	}
+
+	list := &DiscoveredClusterList{}
+	if err := json.Unmarshal(out.Bytes(), list); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if list.Kind != "EksClusterList" || len(list.Items) != 2 || len(list.Errors) != 1 {
+		t.Errorf("unexpected list: %+v", list)
+	}
+	if !strings.Contains(errOut.String(), `failed to describe "us-east-1-denied"`) {
+		t.Errorf("expected the error on stderr, got %q", errOut.String())
+	}
+}
+
+func TestRunClustersAllRegionsFail(t *testing.T) {
+	o, _, _ := newTestOptions(newMockEKSClient(), "")
+
+	err := o.RunClusters(context.Background(), []string{"ap-south-2"}, connectRegion)
+	if err == nil || !strings.Contains(err.Error(), "failed to list clusters in 1 region(s)") {
+		t.Errorf("expected an error when no region can be listed, got %v", err)
+	}
+}
+
+func TestCompleteWithoutKubeconfig(t *testing.T) {
+	o, _, _ := newTestOptions(newMockEKSClient(), "")
+	*o.configFlags.KubeConfig = "/nonexistent/kubeconfig"
+	o.clusterName = "my-cluster"
+	o.awsFlags.Region = "eu-west-1"
+
+	if err := o.Complete(); err != nil {
+		t.Fatalf("Complete returned error: %v", err)
+	}
+	if *o.eksClient.clusterName != "my-cluster" || o.kubeContextName != "" {
+		t.Errorf("expected cluster my-cluster without a context, got %q and context %q", *o.eksClient.clusterName, o.kubeContextName)
+	}
+}
+
+func TestCompleteClusterNameWithKubeconfig(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+current-context: prod
+contexts:
+- name: prod
+  context: {cluster: prod, user: prod}
+clusters:
+- name: prod
+  cluster: {server: "https://example.eks.amazonaws.com"}
+users:
+- name: prod
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      command: aws
+      args: [eks, get-token, --cluster-name, prod, --region, us-east-1]
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		noKubeconfig    bool
+		context         string
+		expectedContext string
+		expectedErr     string
+	}{
+		{name: "region overrides the current context", expectedContext: "prod"},
+		{name: "no kubeconfig", noKubeconfig: true},
+		{name: "no kubeconfig with a context", noKubeconfig: true, context: "prod", expectedErr: "--no-kubeconfig cannot be used with --context"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, _, _ := newTestOptions(newMockEKSClient(), "")
+			*o.configFlags.KubeConfig = kubeconfig
+			if tt.context != "" {
+				o.contexts = []string{tt.context}
+			}
+			o.clusterName, o.awsFlags.Region, o.noKubeconfig = "my-cluster", "eu-west-1", tt.noKubeconfig
+
+			err := o.Complete()
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Errorf("expected error containing %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Complete returned error: %v", err)
+			}
+			if *o.eksClient.clusterName != "my-cluster" || o.kubeContextName != tt.expectedContext {
+				t.Errorf("expected cluster my-cluster with context %q, got %q and context %q", tt.expectedContext, *o.eksClient.clusterName, o.kubeContextName)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func NewDescribeCmd(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe [resource-type] [name]",
+		Short: "Show details of a single EKS resource",
+		Long: `Show details of a single EKS resource.
+Only the Describe API of the given resource is called.
+
+Names by resource type:
+  - access-entries: principal ARN
+  - addons: addon name
+  - cluster: optional, defaults to the current cluster
+  - fargate-profiles: Fargate profile name
+  - insights: insight ID
+  - nodegroups: nodegroup name
+  - pod-identity-associations: association ID
+  - updates: update ID`,
+		Example: `  # Describe a nodegroup
+  kubectl eks-viewer describe nodegroups my-nodegroup
+
+  # Describe an access entry and its associated policies
+  kubectl eks-viewer describe access-entries arn:aws:iam::123456789012:role/my-role
+
+  # Describe the cluster of a specific context
+  kubectl eks-viewer describe cluster --context=my-context`,
+		Args:         cobra.RangeArgs(1, 2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			o.resourceType = args[0]
+			name := ""
+			if len(args) > 1 {
+				name = args[1]
+			}
+
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if name == "" && o.resourceType != "cluster" {
+				return fmt.Errorf("a name is required to describe %s", o.resourceType)
+			}
+
+			if err := o.Complete(); err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return o.RunDescribe(ctx, name)
+		},
+	}
+
+	return cmd
+}
+
+// resourceDescriber fetches a single named resource and writes its details.
+type resourceDescriber func(ctx context.Context, name string, w *prefixWriter) error
+
+func (o *Options) describers() map[string]resourceDescriber {
+	return map[string]resourceDescriber{
+		"access-entries": func(ctx context.Context, name string, w *prefixWriter) error {
+			entry, err := o.eksClient.DescribeAccessEntry(ctx, name)
+			if err != nil {
+				return err
+			}
+			policies, err := o.eksClient.ListAssociatedAccessPolicies(ctx, entry.PrincipalArn)
+			if err != nil {
+				return err
+			}
+			describeAccessEntry(w, entry, policies)
+			return nil
+		},
+		"addons": func(ctx context.Context, name string, w *prefixWriter) error {
+			addon, err := o.eksClient.DescribeAddon(ctx, name)
+			if err != nil {
+				return err
+			}
+			describeAddon(w, addon)
+			return nil
+		},
+		"cluster": func(ctx context.Context, name string, w *prefixWriter) error {
+			if name != "" {
+				o.eksClient.clusterName = &name
+			}
+			clusters, err := o.eksClient.DescribeCluster(ctx)
+			if err != nil {
+				return err
+			}
+			describeCluster(w, clusters[0])
+			return nil
+		},
+		"fargate-profiles": func(ctx context.Context, name string, w *prefixWriter) error {
+			profile, err := o.eksClient.DescribeFargateProfile(ctx, name)
+			if err != nil {
+				return err
+			}
+			describeFargateProfile(w, profile)
+			return nil
+		},
+		"insights": func(ctx context.Context, name string, w *prefixWriter) error {
+			insight, err := o.eksClient.DescribeInsight(ctx, name)
+			if err != nil {
+				return err
+			}
+			describeInsight(w, insight)
+			return nil
+		},
+		"nodegroups": func(ctx context.Context, name string, w *prefixWriter) error {
+			nodegroup, err := o.eksClient.DescribeNodeGroup(ctx, name)
+			if err != nil {
+				return err
+			}
+			describeNodegroup(w, nodegroup)
+			return nil
+		},
+		"pod-identity-associations": func(ctx context.Context, name string, w *prefixWriter) error {
+			association, err := o.eksClient.DescribePodIdentityAssociation(ctx, name)
+			if err != nil {
+				return err
+			}
+			describePodIdentityAssociation(w, association)
+			return nil
+		},
+		"updates": func(ctx context.Context, name string, w *prefixWriter) error {
+			update, err := o.eksClient.DescribeUpdateByID(ctx, name)
+			if err != nil {
+				return err
+			}
+			describeUpdate(w, update)
+			return nil
+		},
+	}
+}
+
+func (o *Options) RunDescribe(ctx context.Context, name string) error {
+	describers := o.describers()
+	describe, ok := describers[o.resourceType]
+	if !ok {
+		var supported []string
+		for resourceType := range describers {
+			supported = append(supported, resourceType)
+		}
+		sort.Strings(supported)
+		return fmt.Errorf("resource type %q not supported. Valid types are: %s",
+			o.resourceType, strings.Join(supported, ", "))
+	}
+
+	tw := tabwriter.NewWriter(o.Out, 0, 8, 2, ' ', 0)
+	if err := describe(ctx, name, &prefixWriter{out: tw}); err != nil {
+		return fmt.Errorf("failed to describe %s %q: %v", o.resourceType, name, err)
+	}
+	return tw.Flush()
+}
+
+// Indentation levels of prefixWriter
+const (
+	levelZero = iota
+	levelOne
+	levelTwo
+)
+
+// prefixWriter writes indented "Key:\tvalue" lines in the style of kubectl
+// describe. The tabs are aligned by the tabwriter it wraps.
+type prefixWriter struct {
+	out io.Writer
+}
+
+func (pw *prefixWriter) Write(level int, format string, a ...interface{}) {
+	fmt.Fprintf(pw.out, strings.Repeat("  ", level)+format, a...)
+}
+
+// WriteList writes title followed by one item per line, or <none>.
+func (pw *prefixWriter) WriteList(level int, title string, items []string) {
+	if len(items) == 0 {
+		pw.Write(level, "%s:\t<none>\n", title)
+		return
+	}
+	pw.Write(level, "%s:\t%s\n", title, items[0])
+	for _, item := range items[1:] {
+		pw.Write(level, "\t%s\n", item)
+	}
+}
+
+// WriteMap writes title followed by one key=value pair per line, sorted by key.
+func (pw *prefixWriter) WriteMap(level int, title string, m map[string]string) {
+	pw.WriteList(level, title, sortedKeyValues(m))
+}
+
+// healthIssue is the common shape of the nodegroup, addon, cluster and Fargate
+// profile health issues.
+type healthIssue struct {
+	code        string
+	message     *string
+	resourceIDs []string
+}
+
+func (pw *prefixWriter) WriteHealthIssues(level int, issues []healthIssue) {
+	if len(issues) == 0 {
+		pw.Write(level, "Health Issues:\t<none>\n")
+		return
+	}
+	pw.Write(level, "Health Issues:\n")
+	pw.Write(level+1, "Code\tMessage\tResources\n")
+	pw.Write(level+1, "----\t-------\t---------\n")
+	for _, issue := range issues {
+		pw.Write(level+1, "%s\t%s\t%s\n", issue.code, stringOrNone(issue.message), joinOrNone(issue.resourceIDs))
+	}
+}
+
+func sortedKeyValues(m map[string]string) []string {
+	keyValues := make([]string, 0, len(m))
+	for k, v := range m {
+		keyValues = append(keyValues, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(keyValues)
+	return keyValues
+}
+
+func stringOrNone(s *string) string {
+	if s == nil || *s == "" {
+		return "<none>"
+	}
+	return *s
+}
+
+func int32OrNone(i *int32) string {
+	if i == nil {
+		return "<none>"
+	}
+	return fmt.Sprintf("%d", *i)
+}
+
+func timeOrNone(t *time.Time) string {
+	if t == nil {
+		return "<none>"
+	}
+	return t.Format(time.RFC1123Z)
+}
+
+func joinOrNone(items []string) string {
+	if len(items) == 0 {
+		return "<none>"
+	}
+	return strings.Join(items, ",")
+}
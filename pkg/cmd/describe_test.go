@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+)
+
+func TestRunDescribe(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		resourceType   string
+		resourceName   string
+		mockClient     func() *mockEKSClient
+		expectedOutput []string
+	}{
+		{
+			name:         "nodegroup",
+			resourceType: "nodegroups",
+			resourceName: "gpu-ng",
+			mockClient: func() *mockEKSClient {
+				m := newMockEKSClient()
+				m.describeNodegroupFunc = func(ctx context.Context, params *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error) {
+					return &eks.DescribeNodegroupOutput{
+						Nodegroup: &types.Nodegroup{
+							NodegroupName: params.NodegroupName,
+							ClusterName:   params.ClusterName,
+							Status:        types.NodegroupStatusDegraded,
+							Version:       stringPtr("1.31"),
+							CapacityType:  types.CapacityTypesSpot,
+							InstanceTypes: []string{"g5.xlarge", "g5.2xlarge"},
+							CreatedAt:     &createdAt,
+							ScalingConfig: &types.NodegroupScalingConfig{
+								DesiredSize: int32Ptr(2),
+								MinSize:     int32Ptr(0),
+								MaxSize:     int32Ptr(5),
+							},
+							UpdateConfig: &types.NodegroupUpdateConfig{MaxUnavailable: int32Ptr(1)},
+							LaunchTemplate: &types.LaunchTemplateSpecification{
+								Name:    stringPtr("gpu-lt"),
+								Version: stringPtr("3"),
+							},
+							Subnets: []string{"subnet-a", "subnet-b"},
+							Labels:  map[string]string{"zeta": "z", "accelerator": "nvidia"},
+							Taints: []types.Taint{
+								{Key: stringPtr("nvidia.com/gpu"), Value: stringPtr("true"), Effect: types.TaintEffectNoSchedule},
+							},
+							Health: &types.NodegroupHealth{
+								Issues: []types.Issue{
+									{Code: types.NodegroupIssueCodeAsgInstanceLaunchFailures, Message: stringPtr("InsufficientInstanceCapacity"), ResourceIds: []string{"asg-1"}},
+								},
+							},
+							Tags: map[string]string{"team": "ml"},
+						},
+					}, nil
+				}
+				return m
+			},
+			expectedOutput: []string{
+				"Name:",
+				"gpu-ng",
+				"Cluster:",
+				"test-cluster",
+				"DEGRADED",
+				"g5.xlarge,g5.2xlarge",
+				"Wed, 01 May 2024 10:00:00 +0000",
+				"Scaling:\n  Desired Size:",
+				"Max Unavailable:",
+				"Launch Template:\n  Name:",
+				"gpu-lt",
+				"subnet-a\n",
+				"accelerator=nvidia\n",
+				"zeta=z\n",
+				"nvidia.com/gpu=true:NO_SCHEDULE",
+				"AsgInstanceLaunchFailures",
+				"InsufficientInstanceCapacity",
+				"team=ml",
+			},
+		},
+		{
+			name:         "access entry with scoped policies",
+			resourceType: "access-entries",
+			resourceName: "arn:aws:iam::123456789012:role/dev",
+			mockClient: func() *mockEKSClient {
+				m := newMockEKSClient()
+				m.describeAccessEntryFunc = func(ctx context.Context, params *eks.DescribeAccessEntryInput) (*eks.DescribeAccessEntryOutput, error) {
+					return &eks.DescribeAccessEntryOutput{
+						AccessEntry: &types.AccessEntry{
+							PrincipalArn:     params.PrincipalArn,
+							Type:             stringPtr("STANDARD"),
+							Username:         stringPtr("arn:aws:sts::123456789012:assumed-role/dev/{{SessionName}}"),
+							KubernetesGroups: []string{"developers"},
+						},
+					}, nil
+				}
+				m.listAssociatedAccessPoliciesFunc = func(ctx context.Context, params *eks.ListAssociatedAccessPoliciesInput) (*eks.ListAssociatedAccessPoliciesOutput, error) {
+					return &eks.ListAssociatedAccessPoliciesOutput{
+						AssociatedAccessPolicies: []types.AssociatedAccessPolicy{
+							{
+								PolicyArn: stringPtr("arn:aws:eks::aws:cluster-access-policy/AmazonEKSEditPolicy"),
+								AccessScope: &types.AccessScope{
+									Type:       types.AccessScopeTypeNamespace,
+									Namespaces: []string{"team-a", "team-b"},
+								},
+							},
+						},
+					}, nil
+				}
+				return m
+			},
+			expectedOutput: []string{
+				"Principal ARN:",
+				"arn:aws:iam::123456789012:role/dev",
+				"STANDARD",
+				"assumed-role/dev/{{SessionName}}",
+				"developers",
+				"Access Policies:\n  Policy ARN:",
+				"AmazonEKSEditPolicy",
+				"Access Scope:",
+				"namespace",
+				"team-a\n",
+				"team-b\n",
+			},
+		},
+		{
+			name:         "update of a nodegroup",
+			resourceType: "updates",
+			resourceName: "ng-2",
+			mockClient:   newUpdatesMock,
+			expectedOutput: []string{
+				"ID:",
+				"ng-2",
+				"nodegroup/ng-1",
+				"VersionUpdate",
+				"Successful",
+			},
+		},
+		{
+			name:         "cluster without a name uses the current cluster",
+			resourceType: "cluster",
+			mockClient:   newMockEKSClient,
+			expectedOutput: []string{
+				"Name:",
+				"test-cluster",
+				"Platform Version:",
+				"eks.1",
+				"Health Issues:",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, out, _ := newTestOptions(tt.mockClient(), "")
+			o.resourceType = tt.resourceType
+
+			if err := o.RunDescribe(context.Background(), tt.resourceName); err != nil {
+				t.Fatalf("RunDescribe returned error: %v", err)
+			}
+
+			output := out.String()
+			for _, expected := range tt.expectedOutput {
+				if !strings.Contains(output, expected) {
+					t.Errorf("Output does not contain expected string: %q\nGot: %s", expected, output)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,496 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// diffResourceTypes are the resource types compared by the diff command, in
+// the order they are printed. The cluster itself and its insights differ
+// between any two clusters and are left out.
+var diffResourceTypes = []string{
+	"addons",
+	"nodegroups",
+	"fargate-profiles",
+	"access-entries",
+	"pod-identity-associations",
+}
+
+// volatileFields are ignored by default because they identify a resource
+// rather than configure it, so they differ between any two clusters.
+var volatileFields = map[string]bool{
+	"AccessEntryArn":    true,
+	"AddonArn":          true,
+	"AssociationArn":    true,
+	"AssociationId":     true,
+	"ClusterName":       true,
+	"FargateProfileArn": true,
+	"NodegroupArn":      true,
+	// Auto Scaling groups and the remote access security group
+	"Resources":         true,
+	"LaunchTemplate.Id": true,
+	// Subnets belong to the VPC of each cluster
+	"Subnets": true,
+}
+
+// timestampFields are ignored by default wherever they are nested, e.g. in
+// the access policies of an access entry.
+var timestampFields = map[string]bool{
+	"CreatedAt":    true,
+	"ModifiedAt":   true,
+	"AssociatedAt": true,
+}
+
+// accountARN matches ARNs that contain an account ID, which differ between
+// clusters in different accounts.
+var accountARN = regexp.MustCompile(`^arn:[^:]+:[^:]*:[^:]*:\d{12}:`)
+
+// Changes reported by the diff command
+const (
+	diffAdded   = "added"
+	diffRemoved = "removed"
+	diffChanged = "changed"
+)
+
+// DiffResult is the -o json output of the diff command.
+type DiffResult struct {
+	From    string         `json:"from"`
+	To      string         `json:"to"`
+	Changes []ResourceDiff `json:"changes"`
+}
+
+// ResourceDiff is a resource that exists on one side only, or whose fields
+// differ.
+type ResourceDiff struct {
+	ResourceType string      `json:"resourceType"`
+	Name         string      `json:"name"`
+	Change       string      `json:"change"`
+	Fields       []FieldDiff `json:"fields,omitempty"`
+}
+
+// FieldDiff is a field that differs, by its path in -o json output. From or
+// To is omitted when the field is only set on the other side.
+type FieldDiff struct {
+	Path string      `json:"path"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// diffSource is a kubeconfig context or a snapshot file to compare.
+type diffSource struct {
+	context string
+	file    string
+}
+
+func (s diffSource) String() string {
+	if s.file != "" {
+		return s.file
+	}
+	return "context " + s.context
+}
+
+func NewDiffCmd(o *Options) *cobra.Command {
+	var (
+		contexts        []string
+		files           []string
+		output          string
+		includeVolatile bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff --context FROM --context TO",
+		Short: "Compare the EKS configuration of two clusters",
+		Long: `Compare the EKS configuration of two clusters.
+Each side is a kubeconfig context or a snapshot written by the snapshot
+command. Snapshot files come before contexts, and a single side is compared
+to the current context. --cluster-name and --region only apply when a single
+side is a context.
+
+Addons, nodegroups, Fargate profiles, access entries and pod identity
+associations are matched by name, principal ARN and namespace/service account,
+and their fields are compared. Addon configuration values are compared value
+by value.
+
+Fields that differ between any two clusters are ignored unless
+--include-volatile is set: timestamps, the ARNs and IDs of the resources
+themselves, and the account IDs in other ARNs, e.g. IAM role ARNs. Access
+entries are matched with the account ID of their principal ARN ignored.
+
+Resources that cannot be fetched on either side are reported on stderr and
+not compared.`,
+		Example: `  # Compare staging and production
+  kubectl eks-viewer diff --context staging --context production
+
+  # Compare a snapshot with the current cluster
+  kubectl eks-viewer diff --from-file state.yaml
+
+  # Print the differences as JSON
+  kubectl eks-viewer diff --context staging --context production -o json`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if output != "" && output != "json" {
+				return fmt.Errorf("invalid output format %q, must be json", output)
+			}
+
+			var sources []diffSource
+			for _, file := range files {
+				sources = append(sources, diffSource{file: file})
+			}
+			for _, contextName := range contexts {
+				sources = append(sources, diffSource{context: contextName})
+			}
+			if len(sources) == 0 || len(sources) > 2 {
+				return fmt.Errorf("diff compares two clusters, got %d --context and --from-file flags", len(sources))
+			}
+			// A single side is compared to the current context. The cluster
+			// and region flags would apply to both contexts.
+			contextSides := len(contexts)
+			if len(sources) < 2 {
+				contextSides++
+			}
+			if contextSides == 2 && (o.clusterName != "" || o.awsFlags.Region != "") {
+				return fmt.Errorf("--cluster-name and --region cannot be used when comparing two contexts")
+			}
+			if len(sources) < 2 || len(contexts) > 0 {
+				if err := o.loadKubeconfig(); err != nil {
+					return err
+				}
+			}
+			if len(sources) < 2 {
+				if o.rawConfig.CurrentContext == "" {
+					return fmt.Errorf("no current-context found in kubeconfig to compare %s with", sources[0])
+				}
+				sources = append(sources, diffSource{context: o.rawConfig.CurrentContext})
+			}
+
+			return o.RunDiff(context.Background(), sources[0], sources[1], output == "json", includeVolatile)
+		},
+	}
+
+	// Both flags shadow the persistent flags of the same name, which take a
+	// single value
+	cmd.Flags().StringArrayVar(&contexts, "context", nil, "The kubeconfig context of a cluster to compare. May be repeated")
+	cmd.Flags().StringArrayVar(&files, "from-file", nil, "A snapshot to compare. May be repeated")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output format. One of: (json). Defaults to a unified form")
+	cmd.Flags().BoolVar(&includeVolatile, "include-volatile", false, "Also compare timestamps, resource ARNs and IDs, and the account IDs in ARNs")
+
+	return cmd
+}
+
+// RunDiff compares the resources of from and to and prints the differences.
+func (o *Options) RunDiff(ctx context.Context, from, to diffSource, asJSON, includeVolatile bool) error {
+	fromSnapshot, err := o.loadDiffSource(ctx, from)
+	if err != nil {
+		return err
+	}
+	toSnapshot, err := o.loadDiffSource(ctx, to)
+	if err != nil {
+		return err
+	}
+
+	result := &DiffResult{
+		From:    from.String(),
+		To:      to.String(),
+		Changes: diffSnapshots(fromSnapshot, toSnapshot, includeVolatile),
+	}
+	if asJSON {
+		data, err := json.MarshalIndent(result, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(o.Out, "%s\n", data)
+		return err
+	}
+	printDiff(o.Out, result)
+	return nil
+}
+
+// loadDiffSource reads a snapshot file, or captures the cluster of a context
+// like the snapshot command does. Resources that cannot be fetched are
+// reported and left out of the comparison.
+func (o *Options) loadDiffSource(ctx context.Context, source diffSource) (*Snapshot, error) {
+	var snapshot *Snapshot
+	if source.file != "" {
+		var err error
+		if snapshot, err = readSnapshot(source.file); err != nil {
+			return nil, err
+		}
+	} else {
+		eksClient, err := o.newEKSClientForContext(source.context)
+		if err != nil {
+			return nil, err
+		}
+		o.eksClient = eksClient
+		o.kubeContextName = source.context
+		if snapshot, err = o.captureSnapshot(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, e := range snapshot.Errors {
+		fmt.Fprintf(o.ErrOut, "error: %s: %s\n", source, e)
+	}
+	return snapshot, nil
+}
+
+// diffItems returns the fields of the items of resourceType by their natural
+// identity.
+func diffItems(snapshot *Snapshot, resourceType string, includeVolatile bool) map[string]map[string]interface{} {
+	items := map[string]map[string]interface{}{}
+	add := func(name string, item interface{}) {
+		if !includeVolatile {
+			name = maskAccountID(name)
+		}
+		items[name] = flattenItem(item, includeVolatile)
+	}
+
+	switch resourceType {
+	case "addons":
+		for _, item := range snapshot.Items.Addons {
+			add(aws.ToString(item.AddonName), item)
+		}
+	case "nodegroups":
+		for _, item := range snapshot.Items.Nodegroups {
+			add(aws.ToString(item.NodegroupName), item)
+		}
+	case "fargate-profiles":
+		for _, item := range snapshot.Items.FargateProfiles {
+			add(aws.ToString(item.FargateProfileName), item)
+		}
+	case "access-entries":
+		for _, item := range snapshot.Items.AccessEntries {
+			principalArn := aws.ToString(item.PrincipalArn)
+			policies := snapshot.AccessPolicies[principalArn]
+			sort.Slice(policies, func(i, j int) bool {
+				return aws.ToString(policies[i].PolicyArn) < aws.ToString(policies[j].PolicyArn)
+			})
+			add(principalArn, struct {
+				AccessEntry
+				AccessPolicies interface{} `json:"AccessPolicies,omitempty"`
+			}{item, policies})
+		}
+	case "pod-identity-associations":
+		for _, item := range snapshot.Items.PodIdentityAssociations {
+			add(aws.ToString(item.Namespace)+"/"+aws.ToString(item.ServiceAccount), item)
+		}
+	}
+	return items
+}
+
+// failedItems returns whether resourceType could not be listed from snapshot,
+// and the items of it that could not be described.
+func failedItems(snapshot *Snapshot, resourceType string, includeVolatile bool) (bool, map[string]bool) {
+	failed := map[string]bool{}
+	for _, e := range snapshot.Errors {
+		switch {
+		case e.ResourceType == resourceType && e.Name == "":
+			return true, nil
+		case e.ResourceType == resourceType,
+			resourceType == "access-entries" && e.ResourceType == accessPoliciesResourceType:
+			name := e.Name
+			if !includeVolatile {
+				name = maskAccountID(name)
+			}
+			failed[name] = true
+		}
+	}
+	return false, failed
+}
+
+// diffSnapshots compares the resources of two snapshots. Resources that could
+// not be fetched on either side are not compared.
+func diffSnapshots(from, to *Snapshot, includeVolatile bool) []ResourceDiff {
+	var changes []ResourceDiff
+	for _, resourceType := range diffResourceTypes {
+		fromFailed, fromFailedItems := failedItems(from, resourceType, includeVolatile)
+		toFailed, toFailedItems := failedItems(to, resourceType, includeVolatile)
+		if fromFailed || toFailed {
+			continue
+		}
+
+		fromItems := diffItems(from, resourceType, includeVolatile)
+		toItems := diffItems(to, resourceType, includeVolatile)
+		names := map[string]bool{}
+		for name := range fromItems {
+			names[name] = true
+		}
+		for name := range toItems {
+			names[name] = true
+		}
+		sortedNames := make([]string, 0, len(names))
+		for name := range names {
+			if !fromFailedItems[name] && !toFailedItems[name] {
+				sortedNames = append(sortedNames, name)
+			}
+		}
+		sort.Strings(sortedNames)
+
+		for _, name := range sortedNames {
+			fromItem, inFrom := fromItems[name]
+			toItem, inTo := toItems[name]
+			switch {
+			case !inFrom:
+				changes = append(changes, ResourceDiff{ResourceType: resourceType, Name: name, Change: diffAdded})
+			case !inTo:
+				changes = append(changes, ResourceDiff{ResourceType: resourceType, Name: name, Change: diffRemoved})
+			default:
+				if fields := diffFields(fromItem, toItem); len(fields) > 0 {
+					changes = append(changes, ResourceDiff{ResourceType: resourceType, Name: name, Change: diffChanged, Fields: fields})
+				}
+			}
+		}
+	}
+	return changes
+}
+
+// diffFields returns the fields that differ, sorted by path.
+func diffFields(from, to map[string]interface{}) []FieldDiff {
+	paths := map[string]bool{}
+	for path := range from {
+		paths[path] = true
+	}
+	for path := range to {
+		paths[path] = true
+	}
+
+	var fields []FieldDiff
+	for path := range paths {
+		if fmt.Sprint(from[path]) != fmt.Sprint(to[path]) {
+			fields = append(fields, FieldDiff{Path: path, From: from[path], To: to[path]})
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Path < fields[j].Path })
+	return fields
+}
+
+// flattenItem flattens item to the leaves of its JSON representation, by path
+// such as ScalingConfig.DesiredSize or Selectors[0].Namespace. Addon
+// configuration values are parsed so that they are compared value by value.
+func flattenItem(item interface{}, includeVolatile bool) map[string]interface{} {
+	fields := map[string]interface{}{}
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fields
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return fields
+	}
+	if values, ok := obj["ConfigurationValues"].(string); ok {
+		var parsed interface{}
+		if err := yaml.Unmarshal([]byte(values), &parsed); err == nil && parsed != nil {
+			obj["ConfigurationValues"] = parsed
+		}
+	}
+
+	flatten(fields, "", obj, includeVolatile)
+	return fields
+}
+
+func flatten(fields map[string]interface{}, path string, value interface{}, includeVolatile bool) {
+	if !includeVolatile && path != "" && isVolatileField(path) {
+		return
+	}
+
+	switch v := value.(type) {
+	case nil:
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			flatten(fields, childPath, child, includeVolatile)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flatten(fields, fmt.Sprintf("%s[%d]", path, i), child, includeVolatile)
+		}
+	case string:
+		if !includeVolatile {
+			v = maskAccountID(v)
+		}
+		fields[path] = v
+	default:
+		fields[path] = v
+	}
+}
+
+// isVolatileField reports whether the field at path is ignored by default:
+// the ARN or ID of the resource itself, or a timestamp.
+func isVolatileField(path string) bool {
+	// Configuration values are user data, whatever their names
+	if strings.HasPrefix(path, "ConfigurationValues.") {
+		return false
+	}
+	if volatileFields[path] {
+		return true
+	}
+	return timestampFields[path[strings.LastIndex(path, ".")+1:]]
+}
+
+// maskAccountID replaces the account ID of an ARN, so that the same principal
+// in two accounts is matched.
+func maskAccountID(s string) string {
+	if loc := accountARN.FindStringIndex(s); loc != nil {
+		return s[:loc[1]-13] + "<account>" + s[loc[1]-1:]
+	}
+	return s
+}
+
+// printDiff prints the differences in a unified form: added resources are
+// prefixed with +, removed ones with - and changed ones with ~, followed by
+// the old and new value of each field that changed.
+func printDiff(w io.Writer, result *DiffResult) {
+	if len(result.Changes) == 0 {
+		fmt.Fprintf(w, "No differences between %s and %s\n", result.From, result.To)
+		return
+	}
+
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", result.From, result.To)
+	for _, change := range result.Changes {
+		name := change.ResourceType + "/" + change.Name
+		switch change.Change {
+		case diffAdded:
+			fmt.Fprintf(w, "+ %s\n", name)
+		case diffRemoved:
+			fmt.Fprintf(w, "- %s\n", name)
+		default:
+			fmt.Fprintf(w, "~ %s\n", name)
+			for _, field := range change.Fields {
+				if field.From != nil {
+					fmt.Fprintf(w, "-     %s: %s\n", field.Path, formatDiffValue(field.From))
+				}
+				if field.To != nil {
+					fmt.Fprintf(w, "+     %s: %s\n", field.Path, formatDiffValue(field.To))
+				}
+			}
+		}
+	}
+}
+
+// formatDiffValue prints strings as they are unless they span several lines.
+func formatDiffValue(value interface{}) string {
+	if s, ok := value.(string); ok && !strings.Contains(s, "\n") {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
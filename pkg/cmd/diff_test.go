@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// newDiffSnapshot returns a snapshot of a cluster in account.
+func newDiffSnapshot(account string) *Snapshot {
+	now := time.Now()
+	return &Snapshot{
+		Items: ResourceItems{
+			Addons: []Addon{{
+				AddonName:             stringPtr("vpc-cni"),
+				AddonArn:              stringPtr("arn:aws:eks:eu-west-1:" + account + ":addon/cluster/vpc-cni/1"),
+				AddonVersion:          stringPtr("v1.18.0-eksbuild.1"),
+				ConfigurationValues:   stringPtr(`{"env": {"WARM_IP_TARGET": "5"}}`),
+				ServiceAccountRoleArn: stringPtr("arn:aws:iam::" + account + ":role/vpc-cni"),
+				CreatedAt:             &now,
+			}},
+			Nodegroups: []Nodegroup{{
+				NodegroupName: stringPtr("general"),
+				NodegroupArn:  stringPtr("arn:aws:eks:eu-west-1:" + account + ":nodegroup/cluster/general/1"),
+				ScalingConfig: &types.NodegroupScalingConfig{DesiredSize: int32Ptr(2)},
+				InstanceTypes: []string{"m6i.large"},
+				Subnets:       []string{"subnet-" + account},
+				CreatedAt:     &now,
+			}},
+			AccessEntries: []AccessEntry{{
+				PrincipalArn:     stringPtr("arn:aws:iam::" + account + ":role/admin"),
+				KubernetesGroups: []string{"admins"},
+			}},
+			PodIdentityAssociations: []PodIdentityAssociation{{
+				Namespace:      stringPtr("default"),
+				ServiceAccount: stringPtr("app"),
+				AssociationId:  stringPtr("a-" + account),
+				RoleArn:        stringPtr("arn:aws:iam::" + account + ":role/app"),
+			}},
+		},
+		AccessPolicies: map[string][]types.AssociatedAccessPolicy{
+			"arn:aws:iam::" + account + ":role/admin": {
+				{PolicyArn: stringPtr("arn:aws:eks::aws:cluster-access-policy/AmazonEKSClusterAdminPolicy")},
+			},
+		},
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	t.Run("identical configuration in another account", func(t *testing.T) {
+		changes := diffSnapshots(newDiffSnapshot("111111111111"), newDiffSnapshot("222222222222"), false)
+		if len(changes) != 0 {
+			t.Errorf("expected no changes, got %+v", changes)
+		}
+	})
+
+	t.Run("volatile fields included", func(t *testing.T) {
+		changes := diffSnapshots(newDiffSnapshot("111111111111"), newDiffSnapshot("222222222222"), true)
+		var names []string
+		for _, change := range changes {
+			names = append(names, change.ResourceType+"/"+change.Name+" "+change.Change)
+		}
+		expected := []string{
+			"addons/vpc-cni changed",
+			"nodegroups/general changed",
+			"access-entries/arn:aws:iam::111111111111:role/admin removed",
+			"access-entries/arn:aws:iam::222222222222:role/admin added",
+			"pod-identity-associations/default/app changed",
+		}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("expected %v, got %v", expected, names)
+		}
+	})
+
+	t.Run("configuration changes", func(t *testing.T) {
+		from := newDiffSnapshot("111111111111")
+		to := newDiffSnapshot("222222222222")
+		to.Items.Addons[0].AddonVersion = stringPtr("v1.19.0-eksbuild.1")
+		to.Items.Addons[0].ConfigurationValues = stringPtr("env:\n  WARM_IP_TARGET: \"5\"\n  ENABLE_PREFIX_DELEGATION: \"true\"\n")
+		to.Items.Nodegroups[0].ScalingConfig.DesiredSize = int32Ptr(6)
+		to.Items.FargateProfiles = []FargateProfile{{FargateProfileName: stringPtr("batch")}}
+		to.Items.PodIdentityAssociations = nil
+		to.AccessPolicies["arn:aws:iam::222222222222:role/admin"] = nil
+
+		changes := diffSnapshots(from, to, false)
+		expected := []ResourceDiff{
+			{ResourceType: "addons", Name: "vpc-cni", Change: diffChanged, Fields: []FieldDiff{
+				{Path: "AddonVersion", From: "v1.18.0-eksbuild.1", To: "v1.19.0-eksbuild.1"},
+				{Path: "ConfigurationValues.env.ENABLE_PREFIX_DELEGATION", To: "true"},
+			}},
+			{ResourceType: "nodegroups", Name: "general", Change: diffChanged, Fields: []FieldDiff{
+				{Path: "ScalingConfig.DesiredSize", From: float64(2), To: float64(6)},
+			}},
+			{ResourceType: "fargate-profiles", Name: "batch", Change: diffAdded},
+			{ResourceType: "access-entries", Name: "arn:aws:iam::<account>:role/admin", Change: diffChanged, Fields: []FieldDiff{
+				{Path: "AccessPolicies[0].PolicyArn", From: "arn:aws:eks::aws:cluster-access-policy/AmazonEKSClusterAdminPolicy"},
+			}},
+			{ResourceType: "pod-identity-associations", Name: "default/app", Change: diffRemoved},
+		}
+		if !reflect.DeepEqual(changes, expected) {
+			t.Errorf("expected %+v, got %+v", expected, changes)
+		}
+	})
+
+	t.Run("role in another account", func(t *testing.T) {
+		from := newDiffSnapshot("111111111111")
+		to := newDiffSnapshot("222222222222")
+		to.Items.PodIdentityAssociations[0].RoleArn = stringPtr("arn:aws:iam::222222222222:role/other-app")
+
+		changes := diffSnapshots(from, to, false)
+		expected := []ResourceDiff{
+			{ResourceType: "pod-identity-associations", Name: "default/app", Change: diffChanged, Fields: []FieldDiff{
+				{Path: "RoleArn", From: "arn:aws:iam::<account>:role/app", To: "arn:aws:iam::<account>:role/other-app"},
+			}},
+		}
+		if !reflect.DeepEqual(changes, expected) {
+			t.Errorf("expected %+v, got %+v", expected, changes)
+		}
+	})
+
+	t.Run("resources that could not be fetched are not compared", func(t *testing.T) {
+		from := newDiffSnapshot("111111111111")
+		to := newDiffSnapshot("111111111111")
+		to.Items.Addons = nil
+		to.Items.Nodegroups = nil
+		to.Errors = []ResourceError{
+			{ResourceType: "addons", Message: "AccessDeniedException"},
+			{ResourceType: "nodegroups", Name: "general", Message: "AccessDeniedException"},
+		}
+		if changes := diffSnapshots(from, to, false); len(changes) != 0 {
+			t.Errorf("expected no changes, got %+v", changes)
+		}
+	})
+}
+
+func TestDiffCmd(t *testing.T) {
+	dir := t.TempDir()
+	from := newDiffSnapshot("111111111111")
+	to := newDiffSnapshot("222222222222")
+	to.Items.Nodegroups[0].ScalingConfig.DesiredSize = int32Ptr(6)
+	to.Items.Addons = append(to.Items.Addons, Addon{AddonName: stringPtr("coredns")})
+	for name, snapshot := range map[string]*Snapshot{"from.yaml": from, "to.yaml": to} {
+		snapshot.TypeMeta.APIVersion, snapshot.TypeMeta.Kind = "v1", snapshotKind
+		buf := &bytes.Buffer{}
+		if err := writeSnapshot(buf, snapshot, false); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fromPath, toPath := filepath.Join(dir, "from.yaml"), filepath.Join(dir, "to.yaml")
+
+	run := func(args ...string) (string, error) {
+		out := &bytes.Buffer{}
+		cmd := NewCmd(genericclioptions.IOStreams{Out: out, ErrOut: &bytes.Buffer{}})
+		cmd.SetArgs(append([]string{"diff", "--from-file", fromPath, "--from-file", toPath}, args...))
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		err := cmd.Execute()
+		return out.String(), err
+	}
+
+	t.Run("unified", func(t *testing.T) {
+		out, err := run()
+		if err != nil {
+			t.Fatalf("diff returned error: %v", err)
+		}
+		expected := "--- " + fromPath + "\n" +
+			"+++ " + toPath + "\n" +
+			"+ addons/coredns\n" +
+			"~ nodegroups/general\n" +
+			"-     ScalingConfig.DesiredSize: 2\n" +
+			"+     ScalingConfig.DesiredSize: 6\n"
+		if out != expected {
+			t.Errorf("expected:\n%s\ngot:\n%s", expected, out)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out, err := run("-o", "json")
+		if err != nil {
+			t.Fatalf("diff returned error: %v", err)
+		}
+		result := &DiffResult{}
+		if err := json.Unmarshal([]byte(out), result); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, out)
+		}
+		if result.From != fromPath || result.To != toPath || len(result.Changes) != 2 || result.Changes[0].Change != diffAdded {
+			t.Errorf("unexpected result: %+v", result)
+		}
+	})
+
+	t.Run("cluster name with two contexts", func(t *testing.T) {
+		cmd := NewCmd(genericclioptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}})
+		cmd.SetArgs([]string{"diff", "--context", "staging", "--context", "production", "--region", "us-east-1"})
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "cannot be used when comparing two contexts") {
+			t.Errorf("expected the region to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("invalid output", func(t *testing.T) {
+		if _, err := run("-o", "yaml"); err == nil || !strings.Contains(err.Error(), "invalid output format") {
+			t.Errorf("expected an invalid output format error, got %v", err)
+		}
+	})
+}
+
+func TestIsVolatileField(t *testing.T) {
+	tests := map[string]bool{
+		"CreatedAt":                       true,
+		"AccessPolicies[0].AssociatedAt":  true,
+		"AddonArn":                        true,
+		"UpdateConfig.MaxUnavailableAt":   false,
+		"ConfigurationValues.env.StartAt": false,
+		"Version":                         false,
+	}
+
+	for path, expected := range tests {
+		if got := isVolatileField(path); got != expected {
+			t.Errorf("isVolatileField(%q): expected %t, got %t", path, expected, got)
+		}
+	}
+}
+
+func TestMaskAccountID(t *testing.T) {
+	tests := map[string]string{
+		"arn:aws:iam::123456789012:role/admin":                        "arn:aws:iam::<account>:role/admin",
+		"arn:aws:eks::aws:cluster-access-policy/AmazonEKSAdminPolicy": "arn:aws:eks::aws:cluster-access-policy/AmazonEKSAdminPolicy",
+		"arn:aws-cn:sts::123456789012:assumed-role/admin/session":     "arn:aws-cn:sts::<account>:assumed-role/admin/session",
+		"not-an-arn": "not-an-arn",
+	}
+	for input, expected := range tests {
+		if got := maskAccountID(input); got != expected {
+			t.Errorf("maskAccountID(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
@@ -2,8 +2,8 @@ package cmd
 
 import (
 	"context"
+	"maps"
 
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -16,13 +16,16 @@ type EKSClientAPI interface {
 	ListAssociatedAccessPolicies(ctx context.Context, params *eks.ListAssociatedAccessPoliciesInput, optFns ...func(*eks.Options)) (*eks.ListAssociatedAccessPoliciesOutput, error)
 	ListAccessEntries(ctx context.Context, params *eks.ListAccessEntriesInput, optFns ...func(*eks.Options)) (*eks.ListAccessEntriesOutput, error)
 	DescribeAccessEntry(ctx context.Context, params *eks.DescribeAccessEntryInput, optFns ...func(*eks.Options)) (*eks.DescribeAccessEntryOutput, error)
+	ListAccessPolicies(ctx context.Context, params *eks.ListAccessPoliciesInput, optFns ...func(*eks.Options)) (*eks.ListAccessPoliciesOutput, error)
 
 	// Addon methods
 	ListAddons(ctx context.Context, params *eks.ListAddonsInput, optFns ...func(*eks.Options)) (*eks.ListAddonsOutput, error)
 	DescribeAddon(ctx context.Context, params *eks.DescribeAddonInput, optFns ...func(*eks.Options)) (*eks.DescribeAddonOutput, error)
+	DescribeAddonVersions(ctx context.Context, params *eks.DescribeAddonVersionsInput, optFns ...func(*eks.Options)) (*eks.DescribeAddonVersionsOutput, error)
 
 	// Cluster methods
 	DescribeCluster(ctx context.Context, params *eks.DescribeClusterInput, optFns ...func(*eks.Options)) (*eks.DescribeClusterOutput, error)
+	ListClusters(ctx context.Context, params *eks.ListClustersInput, optFns ...func(*eks.Options)) (*eks.ListClustersOutput, error)
 
 	// Fargate Profile methods
 	ListFargateProfiles(ctx context.Context, params *eks.ListFargateProfilesInput, optFns ...func(*eks.Options)) (*eks.ListFargateProfilesOutput, error)
@@ -39,15 +42,32 @@ type EKSClientAPI interface {
 	// Pod Identity Association methods
 	ListPodIdentityAssociations(ctx context.Context, params *eks.ListPodIdentityAssociationsInput, optFns ...func(*eks.Options)) (*eks.ListPodIdentityAssociationsOutput, error)
 	DescribePodIdentityAssociation(ctx context.Context, params *eks.DescribePodIdentityAssociationInput, optFns ...func(*eks.Options)) (*eks.DescribePodIdentityAssociationOutput, error)
+
+	// Update methods
+	ListUpdates(ctx context.Context, params *eks.ListUpdatesInput, optFns ...func(*eks.Options)) (*eks.ListUpdatesOutput, error)
+	DescribeUpdate(ctx context.Context, params *eks.DescribeUpdateInput, optFns ...func(*eks.Options)) (*eks.DescribeUpdateOutput, error)
 }
 
+// maxRetryAttempts is the number of attempts made for an EKS API call before
+// giving up. Describe calls run concurrently, so throttling is expected on
+// large clusters and is retried with backoff.
+const maxRetryAttempts = 10
+
 type EKSClient struct {
 	client      EKSClientAPI
 	clusterName *string
+	pool        *workerPool
+	// nameFilter restricts List calls to the matching names, so that
+	// excluded items are never described.
+	nameFilter nameMatcher
+	// region is the AWS region of the cluster
+	region string
+	// addonVersions caches DescribeAddonVersions, without caching when nil
+	addonVersions *addonVersionCache
 }
 
-func NewEKSClient(clusterName *string) (*EKSClient, error) {
-	cfg, err := config.LoadDefaultConfig(context.Background())
+func NewEKSClient(clusterName *string, concurrency int, settings awsSettings) (*EKSClient, error) {
+	cfg, err := loadAWSConfig(context.Background(), settings)
 	if err != nil {
 		return nil, err
 	}
@@ -55,20 +75,51 @@ func NewEKSClient(clusterName *string) (*EKSClient, error) {
 	return &EKSClient{
 		client:      eks.NewFromConfig(cfg),
 		clusterName: clusterName,
+		pool:        newWorkerPool(concurrency),
+		region:      cfg.Region,
 	}, nil
 }
 
 type ResourceList struct {
 	metav1.TypeMeta
-	Items struct {
-		AccessEntries           []AccessEntry            `json:"access-entries"`
-		Addons                  []Addon                  `json:"addons"`
-		Nodegroups              []Nodegroup              `json:"nodegroups"`
-		FargateProfiles         []FargateProfile         `json:"fargate-profiles"`
-		PodIdentityAssociations []PodIdentityAssociation `json:"pod-identity-associations"`
-		Insights                []Insight                `json:"insights"`
-		Cluster                 []Cluster                `json:"cluster"`
-	} `json:"items"`
+	Errors []ResourceError `json:"errors,omitempty"`
+	Items  ResourceItems   `json:"items"`
+	// clusters are the clusters of the items of each resource type when the
+	// items of several contexts are printed together
+	clusters map[string][]clusterRef
+}
+
+// ResourceItems holds the items of every resource type.
+type ResourceItems struct {
+	AccessEntries           []AccessEntry            `json:"access-entries"`
+	Addons                  []Addon                  `json:"addons"`
+	Nodegroups              []Nodegroup              `json:"nodegroups"`
+	FargateProfiles         []FargateProfile         `json:"fargate-profiles"`
+	PodIdentityAssociations []PodIdentityAssociation `json:"pod-identity-associations"`
+	Insights                []Insight                `json:"insights"`
+	Updates                 []Update                 `json:"updates"`
+	Cluster                 []Cluster                `json:"cluster"`
+	// NodegroupNodes are the Kubernetes Nodes of each nodegroup, by name,
+	// with --nodes
+	NodegroupNodes map[string]NodegroupNodes `json:"nodegroup-nodes,omitempty"`
+	// addonUpgrades are the published versions of each addon of Addons, in
+	// the same order, in wide tables
+	addonUpgrades []AddonUpgrade
+}
+
+func (r ResourceItems) deepCopy() ResourceItems {
+	return ResourceItems{
+		AccessEntries:           append([]AccessEntry(nil), r.AccessEntries...),
+		Addons:                  append([]Addon(nil), r.Addons...),
+		Nodegroups:              append([]Nodegroup(nil), r.Nodegroups...),
+		FargateProfiles:         append([]FargateProfile(nil), r.FargateProfiles...),
+		PodIdentityAssociations: append([]PodIdentityAssociation(nil), r.PodIdentityAssociations...),
+		Insights:                append([]Insight(nil), r.Insights...),
+		Updates:                 append([]Update(nil), r.Updates...),
+		Cluster:                 append([]Cluster(nil), r.Cluster...),
+		NodegroupNodes:          maps.Clone(r.NodegroupNodes),
+		addonUpgrades:           append([]AddonUpgrade(nil), r.addonUpgrades...),
+	}
 }
 
 // Implement runtime.Object interface for ResourceList
@@ -79,22 +130,7 @@ func (r *ResourceList) GetObjectKind() schema.ObjectKind {
 func (r *ResourceList) DeepCopyObject() runtime.Object {
 	return &ResourceList{
 		TypeMeta: r.TypeMeta,
-		Items: struct {
-			AccessEntries           []AccessEntry            `json:"access-entries"`
-			Addons                  []Addon                  `json:"addons"`
-			Nodegroups              []Nodegroup              `json:"nodegroups"`
-			FargateProfiles         []FargateProfile         `json:"fargate-profiles"`
-			PodIdentityAssociations []PodIdentityAssociation `json:"pod-identity-associations"`
-			Insights                []Insight                `json:"insights"`
-			Cluster                 []Cluster                `json:"cluster"`
-		}{
-			AccessEntries:           append([]AccessEntry(nil), r.Items.AccessEntries...),
-			Addons:                  append([]Addon(nil), r.Items.Addons...),
-			Nodegroups:              append([]Nodegroup(nil), r.Items.Nodegroups...),
-			FargateProfiles:         append([]FargateProfile(nil), r.Items.FargateProfiles...),
-			PodIdentityAssociations: append([]PodIdentityAssociation(nil), r.Items.PodIdentityAssociations...),
-			Insights:                append([]Insight(nil), r.Items.Insights...),
-			Cluster:                 append([]Cluster(nil), r.Items.Cluster...),
-		},
+		Errors:   append([]ResourceError(nil), r.Errors...),
+		Items:    r.Items.deepCopy(),
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
 )
 
 // mockEKSClient implements the necessary methods for testing
@@ -12,13 +13,17 @@ type mockEKSClient struct {
 	listAssociatedAccessPoliciesFunc func(ctx context.Context, params *eks.ListAssociatedAccessPoliciesInput) (*eks.ListAssociatedAccessPoliciesOutput, error)
 	listAccessEntriesFunc            func(ctx context.Context, params *eks.ListAccessEntriesInput) (*eks.ListAccessEntriesOutput, error)
 	describeAccessEntryFunc          func(ctx context.Context, params *eks.DescribeAccessEntryInput) (*eks.DescribeAccessEntryOutput, error)
+	listAccessPoliciesFunc           func(ctx context.Context, params *eks.ListAccessPoliciesInput) (*eks.ListAccessPoliciesOutput, error)
 
 	// Addon methods
 	listAddonsFunc    func(ctx context.Context, params *eks.ListAddonsInput) (*eks.ListAddonsOutput, error)
 	describeAddonFunc func(ctx context.Context, params *eks.DescribeAddonInput) (*eks.DescribeAddonOutput, error)
 
+	describeAddonVersionsFunc func(ctx context.Context, params *eks.DescribeAddonVersionsInput) (*eks.DescribeAddonVersionsOutput, error)
+
 	// Cluster methods
 	describeClusterFunc func(ctx context.Context, params *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error)
+	listClustersFunc    func(ctx context.Context, params *eks.ListClustersInput) (*eks.ListClustersOutput, error)
 
 	// Fargate Profile methods
 	listFargateProfilesFunc    func(ctx context.Context, params *eks.ListFargateProfilesInput) (*eks.ListFargateProfilesOutput, error)
@@ -35,6 +40,10 @@ type mockEKSClient struct {
 	// Pod Identity Association methods
 	listPodIdentityAssociationsFunc    func(ctx context.Context, params *eks.ListPodIdentityAssociationsInput) (*eks.ListPodIdentityAssociationsOutput, error)
 	describePodIdentityAssociationFunc func(ctx context.Context, params *eks.DescribePodIdentityAssociationInput) (*eks.DescribePodIdentityAssociationOutput, error)
+
+	// Update methods
+	listUpdatesFunc    func(ctx context.Context, params *eks.ListUpdatesInput) (*eks.ListUpdatesOutput, error)
+	describeUpdateFunc func(ctx context.Context, params *eks.DescribeUpdateInput) (*eks.DescribeUpdateOutput, error)
 }
 
 func (m *mockEKSClient) ListAssociatedAccessPolicies(ctx context.Context, params *eks.ListAssociatedAccessPoliciesInput, optFns ...func(*eks.Options)) (*eks.ListAssociatedAccessPoliciesOutput, error) {
@@ -49,6 +58,10 @@ func (m *mockEKSClient) DescribeAccessEntry(ctx context.Context, params *eks.Des
 	return m.describeAccessEntryFunc(ctx, params)
 }
 
+func (m *mockEKSClient) ListAccessPolicies(ctx context.Context, params *eks.ListAccessPoliciesInput, optFns ...func(*eks.Options)) (*eks.ListAccessPoliciesOutput, error) {
+	return m.listAccessPoliciesFunc(ctx, params)
+}
+
 func (m *mockEKSClient) ListAddons(ctx context.Context, params *eks.ListAddonsInput, optFns ...func(*eks.Options)) (*eks.ListAddonsOutput, error) {
 	return m.listAddonsFunc(ctx, params)
 }
@@ -57,10 +70,18 @@ func (m *mockEKSClient) DescribeAddon(ctx context.Context, params *eks.DescribeA
 	return m.describeAddonFunc(ctx, params)
 }
 
+func (m *mockEKSClient) DescribeAddonVersions(ctx context.Context, params *eks.DescribeAddonVersionsInput, optFns ...func(*eks.Options)) (*eks.DescribeAddonVersionsOutput, error) {
+	return m.describeAddonVersionsFunc(ctx, params)
+}
+
 func (m *mockEKSClient) DescribeCluster(ctx context.Context, params *eks.DescribeClusterInput, optFns ...func(*eks.Options)) (*eks.DescribeClusterOutput, error) {
 	return m.describeClusterFunc(ctx, params)
 }
 
+func (m *mockEKSClient) ListClusters(ctx context.Context, params *eks.ListClustersInput, optFns ...func(*eks.Options)) (*eks.ListClustersOutput, error) {
+	return m.listClustersFunc(ctx, params)
+}
+
 func (m *mockEKSClient) ListFargateProfiles(ctx context.Context, params *eks.ListFargateProfilesInput, optFns ...func(*eks.Options)) (*eks.ListFargateProfilesOutput, error) {
 	return m.listFargateProfilesFunc(ctx, params)
 }
@@ -92,3 +113,52 @@ func (m *mockEKSClient) ListPodIdentityAssociations(ctx context.Context, params
 func (m *mockEKSClient) DescribePodIdentityAssociation(ctx context.Context, params *eks.DescribePodIdentityAssociationInput, optFns ...func(*eks.Options)) (*eks.DescribePodIdentityAssociationOutput, error) {
 	return m.describePodIdentityAssociationFunc(ctx, params)
 }
+
+func (m *mockEKSClient) ListUpdates(ctx context.Context, params *eks.ListUpdatesInput, optFns ...func(*eks.Options)) (*eks.ListUpdatesOutput, error) {
+	return m.listUpdatesFunc(ctx, params)
+}
+
+func (m *mockEKSClient) DescribeUpdate(ctx context.Context, params *eks.DescribeUpdateInput, optFns ...func(*eks.Options)) (*eks.DescribeUpdateOutput, error) {
+	return m.describeUpdateFunc(ctx, params)
+}
+
+// newMockEKSClient returns a mock for a cluster named test-cluster with no
+// resources. Tests override the funcs they care about.
+func newMockEKSClient() *mockEKSClient {
+	return &mockEKSClient{
+		listAssociatedAccessPoliciesFunc: func(ctx context.Context, params *eks.ListAssociatedAccessPoliciesInput) (*eks.ListAssociatedAccessPoliciesOutput, error) {
+			return &eks.ListAssociatedAccessPoliciesOutput{}, nil
+		},
+		listAccessEntriesFunc: func(ctx context.Context, params *eks.ListAccessEntriesInput) (*eks.ListAccessEntriesOutput, error) {
+			return &eks.ListAccessEntriesOutput{}, nil
+		},
+		listAddonsFunc: func(ctx context.Context, params *eks.ListAddonsInput) (*eks.ListAddonsOutput, error) {
+			return &eks.ListAddonsOutput{}, nil
+		},
+		describeClusterFunc: func(ctx context.Context, params *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error) {
+			return &eks.DescribeClusterOutput{
+				Cluster: &types.Cluster{
+					Name:            params.Name,
+					Version:         stringPtr("1.31"),
+					Status:          types.ClusterStatusActive,
+					PlatformVersion: stringPtr("eks.1"),
+				},
+			}, nil
+		},
+		listFargateProfilesFunc: func(ctx context.Context, params *eks.ListFargateProfilesInput) (*eks.ListFargateProfilesOutput, error) {
+			return &eks.ListFargateProfilesOutput{}, nil
+		},
+		listInsightsFunc: func(ctx context.Context, params *eks.ListInsightsInput) (*eks.ListInsightsOutput, error) {
+			return &eks.ListInsightsOutput{}, nil
+		},
+		listNodegroupsFunc: func(ctx context.Context, params *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error) {
+			return &eks.ListNodegroupsOutput{}, nil
+		},
+		listPodIdentityAssociationsFunc: func(ctx context.Context, params *eks.ListPodIdentityAssociationsInput) (*eks.ListPodIdentityAssociationsOutput, error) {
+			return &eks.ListPodIdentityAssociationsOutput{}, nil
+		},
+		listUpdatesFunc: func(ctx context.Context, params *eks.ListUpdatesInput) (*eks.ListUpdatesOutput, error) {
+			return &eks.ListUpdatesOutput{}, nil
+		},
+	}
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ResourceError records a failure to fetch a whole resource type, or a single
+// item of it when Name is set.
+type ResourceError struct {
+	ResourceType string `json:"resourceType"`
+	Name         string `json:"name,omitempty"`
+	Message      string `json:"message"`
+}
+
+func (e ResourceError) String() string {
+	if e.Name == "" {
+		return fmt.Sprintf("failed to list %s: %s", e.ResourceType, e.Message)
+	}
+	return fmt.Sprintf("failed to describe %q: %s", e.Name, e.Message)
+}
+
+// ItemError is the failure to describe a single item of a list.
+type ItemError struct {
+	Name string
+	Err  error
+}
+
+// PartialError is returned alongside the items that were fetched when some
+// items of a list could not be described.
+type PartialError struct {
+	Errors []ItemError
+}
+
+func (e *PartialError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, item := range e.Errors {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", item.Name, item.Err))
+	}
+	return fmt.Sprintf("failed to describe %d item(s): %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// withItemError adds item to the *PartialError err, which may be nil.
+func withItemError(err error, item ItemError) error {
+	var partial *PartialError
+	if errors.As(err, &partial) {
+		partial.Errors = append(partial.Errors, item)
+		return partial
+	}
+	return &PartialError{Errors: []ItemError{item}}
+}
+
+func isPartialError(err error) bool {
+	var partial *PartialError
+	return errors.As(err, &partial)
+}
+
+// printResourceErrors writes one marker line per recorded error.
+func printResourceErrors(w io.Writer, resourceErrors []ResourceError) {
+	for _, e := range resourceErrors {
+		fmt.Fprintf(w, "error: %s\n", e)
+	}
+}
+
+// toResourceErrors converts an error returned while fetching resourceType into
+// the entries reported in ResourceList.Errors.
+func toResourceErrors(resourceType string, err error) []ResourceError {
+	var partial *PartialError
+	if !errors.As(err, &partial) {
+		return []ResourceError{{ResourceType: resourceType, Message: err.Error()}}
+	}
+
+	resourceErrors := make([]ResourceError, 0, len(partial.Errors))
+	for _, item := range partial.Errors {
+		resourceErrors = append(resourceErrors, ResourceError{
+			ResourceType: resourceType,
+			Name:         item.Name,
+			Message:      item.Err.Error(),
+		})
+	}
+	return resourceErrors
+}
+
+// ExitError is an error that should end the process with a specific exit
+// code, for commands such as wait that are used from scripts.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code for an error returned by the command: the
+// code of an ExitError, 1 for any other error and 0 for nil.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return 1
+}
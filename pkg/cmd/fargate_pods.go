@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// fargateProfileLabel is the label EKS sets on the pods scheduled on Fargate
+// to the profile they matched. Setting it chooses between several profiles
+// that match a pod.
+const fargateProfileLabel = "eks.amazonaws.com/fargate-profile"
+
+// FargatePod is a pod matched by a Fargate profile.
+type FargatePod struct {
+	Profile   string
+	Namespace string
+	Pod       string
+	Phase     string
+	// Node is the Fargate node of the pod, empty until it is scheduled
+	Node string `json:",omitempty"`
+}
+
+// UnmatchedPod is a pending pod that no Fargate profile matches.
+type UnmatchedPod struct {
+	Namespace string
+	Pod       string
+	// SchedulerName is fargate-scheduler when a profile matched the pod
+	// when it was created
+	SchedulerName string
+}
+
+type FargatePodList struct {
+	metav1.TypeMeta
+	Errors []ResourceError `json:"errors,omitempty"`
+	Items  []FargatePod    `json:"items"`
+	// Unmatched are the pending pods that match no Fargate profile. They are
+	// only listed when every profile is.
+	Unmatched []UnmatchedPod `json:"unmatched,omitempty"`
+}
+
+// Implement runtime.Object interface
+func (f *FargatePodList) GetObjectKind() schema.ObjectKind {
+	return &f.TypeMeta
+}
+
+func (f *FargatePodList) DeepCopyObject() runtime.Object {
+	copied := *f
+	copied.Errors = append([]ResourceError(nil), f.Errors...)
+	copied.Items = append([]FargatePod(nil), f.Items...)
+	copied.Unmatched = append([]UnmatchedPod(nil), f.Unmatched...)
+	return &copied
+}
+
+// RunFargatePods prints the pods matched by each Fargate profile and the
+// pending pods that no profile matches.
+func (o *Options) RunFargatePods(ctx context.Context) error {
+	list, err := fetchReport(ctx, o, "fargate-profiles", o.fetchFargatePods)
+	if err != nil {
+		return err
+	}
+	return o.printReport(list, list.Errors, NewFargatePodPrinter)
+}
+
+func (o *Options) fetchFargatePods(ctx context.Context) (*FargatePodList, error) {
+	profiles, listErr := o.eksClient.ListFargateProfiles(ctx)
+	if listErr != nil && !isPartialError(listErr) {
+		return nil, fmt.Errorf("failed to list fargate profiles: %v", listErr)
+	}
+	profiles = selectItems(o, "fargate-profiles", profiles, fargateProfileTags, fargateProfileFields)
+
+	pods, err := listAllPages(ctx, metav1.ListOptions{}, func(ctx context.Context, options metav1.ListOptions) ([]corev1.Pod, string, error) {
+		list, err := o.kubeClient.CoreV1().Pods("").List(ctx, options)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+
+	list := matchFargatePods(profiles, pods)
+	if len(o.names) > 0 || o.fieldSelector != "" || o.tagSelector != "" || listErr != nil {
+		// The profiles that were not selected or listed may match them
+		list.Unmatched = nil
+	}
+	if listErr != nil {
+		list.Errors = toResourceErrors("fargate-profiles", listErr)
+	}
+	return list, nil
+}
+
+// matchFargatePods returns the pods that have not terminated matched by each
+// profile, and the pending pods that match none.
+func matchFargatePods(profiles []FargateProfile, pods []corev1.Pod) *FargatePodList {
+	list := &FargatePodList{
+		TypeMeta:  metav1.TypeMeta{APIVersion: "v1", Kind: "EksFargatePodList"},
+		Items:     []FargatePod{},
+		Unmatched: []UnmatchedPod{},
+	}
+
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		matched := false
+		for _, profile := range profiles {
+			name := aws.ToString(profile.FargateProfileName)
+			// The pod chose its profile or was scheduled with it
+			if chosen, ok := pod.Labels[fargateProfileLabel]; ok && chosen != name {
+				continue
+			}
+			if !matchesFargateProfile(profile, pod) {
+				continue
+			}
+			matched = true
+			list.Items = append(list.Items, FargatePod{
+				Profile:   name,
+				Namespace: pod.Namespace,
+				Pod:       pod.Name,
+				Phase:     string(pod.Status.Phase),
+				Node:      pod.Spec.NodeName,
+			})
+		}
+
+		if !matched && pod.Status.Phase == corev1.PodPending {
+			list.Unmatched = append(list.Unmatched, UnmatchedPod{
+				Namespace:     pod.Namespace,
+				Pod:           pod.Name,
+				SchedulerName: pod.Spec.SchedulerName,
+			})
+		}
+	}
+
+	sort.Slice(list.Items, func(i, j int) bool {
+		a, b := list.Items[i], list.Items[j]
+		if a.Profile != b.Profile {
+			return a.Profile < b.Profile
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Pod < b.Pod
+	})
+	sort.Slice(list.Unmatched, func(i, j int) bool {
+		a, b := list.Unmatched[i], list.Unmatched[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Pod < b.Pod
+	})
+	return list
+}
+
+// matchesFargateProfile reports whether a selector of profile matches pod:
+// the namespace of the pod matches the namespace of the selector, and the pod
+// has every label of the selector. Namespaces, label keys and label values
+// may contain the * and ? wildcards.
+func matchesFargateProfile(profile FargateProfile, pod corev1.Pod) bool {
+	for _, selector := range profile.Selectors {
+		if matchesFargateSelector(selector, pod) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesFargateSelector(selector types.FargateProfileSelector, pod corev1.Pod) bool {
+	if !newNameMatcher([]string{aws.ToString(selector.Namespace)}).Matches(pod.Namespace) {
+		return false
+	}
+	for key, value := range selector.Labels {
+		keyMatcher, valueMatcher := newNameMatcher([]string{key}), newNameMatcher([]string{value})
+		found := false
+		for podKey, podValue := range pod.Labels {
+			if keyMatcher.Matches(podKey) && valueMatcher.Matches(podValue) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func NewFargatePodPrinter(options printers.PrintOptions) printers.ResourcePrinter {
+	return printers.ResourcePrinterFunc(func(obj runtime.Object, w io.Writer) error {
+		list, ok := obj.(*FargatePodList)
+		if !ok {
+			return fmt.Errorf("expected *FargatePodList, got %T", obj)
+		}
+
+		table := &metav1.Table{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "FargatePod",
+			},
+			ColumnDefinitions: []metav1.TableColumnDefinition{
+				{Name: "PROFILE", Type: "string"},
+				{Name: "NAMESPACE", Type: "string"},
+				{Name: "POD", Type: "string"},
+				{Name: "STATUS", Type: "string"},
+				{Name: "NODE", Type: "string"},
+			},
+		}
+		for _, item := range list.Items {
+			table.Rows = append(table.Rows, metav1.TableRow{
+				Cells: []interface{}{item.Profile, item.Namespace, item.Pod, item.Phase, stringOrNone(&item.Node)},
+			})
+		}
+		if err := printTable(w, table, "fargate profile pods", options); err != nil {
+			return err
+		}
+
+		if list.Unmatched == nil {
+			return nil
+		}
+		unmatched := &metav1.Table{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "UnmatchedPod",
+			},
+			ColumnDefinitions: []metav1.TableColumnDefinition{
+				{Name: "NAMESPACE", Type: "string"},
+				{Name: "POD", Type: "string"},
+				{Name: "SCHEDULER", Type: "string"},
+			},
+		}
+		for _, item := range list.Unmatched {
+			unmatched.Rows = append(unmatched.Rows, metav1.TableRow{
+				Cells: []interface{}{item.Namespace, item.Pod, stringOrNone(&item.SchedulerName)},
+			})
+		}
+		fmt.Fprintln(w)
+		return printTable(w, unmatched, "pending pods matching no fargate profile", options)
+	})
+}
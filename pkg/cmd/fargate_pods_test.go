@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestMatchesFargateProfile(t *testing.T) {
+	profile := types.FargateProfile{
+		Selectors: []types.FargateProfileSelector{
+			{Namespace: stringPtr("batch"), Labels: map[string]string{"app": "job-*", "tier": "low"}},
+			{Namespace: stringPtr("reports-?")},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		namespace string
+		labels    map[string]string
+		expected  bool
+	}{
+		{name: "every label", namespace: "batch", labels: map[string]string{"app": "job-nightly", "tier": "low", "extra": "yes"}, expected: true},
+		{name: "missing label", namespace: "batch", labels: map[string]string{"app": "job-nightly"}},
+		{name: "other label value", namespace: "batch", labels: map[string]string{"app": "web", "tier": "low"}},
+		{name: "namespace wildcard", namespace: "reports-1", expected: true},
+		{name: "other namespace", namespace: "reports-10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := *newPod(tt.namespace, "pod", "", corev1.PodRunning)
+			pod.Labels = tt.labels
+			if got := matchesFargateProfile(profile, pod); got != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, got)
+			}
+		})
+	}
+}
+
+// newFargatePodsMock has a batch profile for the pods labeled compute=fargate
+// and a default profile for the default namespace.
+func newFargatePodsMock() *mockEKSClient {
+	selectors := map[string][]types.FargateProfileSelector{
+		"batch":   {{Namespace: stringPtr("batch"), Labels: map[string]string{"compute": "fargate"}}},
+		"default": {{Namespace: stringPtr("default")}},
+	}
+	mockClient := newMockEKSClient()
+	mockClient.listFargateProfilesFunc = func(ctx context.Context, params *eks.ListFargateProfilesInput) (*eks.ListFargateProfilesOutput, error) {
+		return &eks.ListFargateProfilesOutput{FargateProfileNames: []string{"batch", "default"}}, nil
+	}
+	mockClient.describeFargateProfileFunc = func(ctx context.Context, params *eks.DescribeFargateProfileInput) (*eks.DescribeFargateProfileOutput, error) {
+		return &eks.DescribeFargateProfileOutput{
+			FargateProfile: &types.FargateProfile{
+				FargateProfileName:  params.FargateProfileName,
+				PodExecutionRoleArn: stringPtr("arn:aws:iam::123456789012:role/eks-fargate-pods"),
+				Status:              types.FargateProfileStatusActive,
+				Selectors:           selectors[*params.FargateProfileName],
+			},
+		}, nil
+	}
+	return mockClient
+}
+
+func newFargatePodsClientset() *fake.Clientset {
+	onFargate := newPod("batch", "job-1", "", corev1.PodRunning)
+	onFargate.Labels = map[string]string{"compute": "fargate", fargateProfileLabel: "batch"}
+	onFargate.Spec.NodeName = "fargate-ip-10-0-1-10.ec2.internal"
+	waiting := newPod("batch", "job-2", "", corev1.PodPending)
+	waiting.Labels = map[string]string{"compute": "fargate"}
+	mislabeled := newPod("batch", "job-3", "", corev1.PodPending)
+	mislabeled.Labels = map[string]string{"compute": "fargte"}
+
+	return fake.NewSimpleClientset(
+		onFargate,
+		waiting,
+		mislabeled,
+		newPod("default", "web-1", "", corev1.PodRunning),
+		newPod("default", "migrate-1", "", corev1.PodSucceeded),
+		newPod("kube-system", "coredns-1", "", corev1.PodRunning),
+	)
+}
+
+func TestRunFargatePods(t *testing.T) {
+	o, out, _ := newTestOptions(newFargatePodsMock(), "json")
+	o.resourceType, o.matchPods = "fargate-profiles", true
+	o.kubeClient = newFargatePodsClientset()
+
+	if err := o.RunFargatePods(context.Background()); err != nil {
+		t.Fatalf("RunFargatePods returned error: %v", err)
+	}
+
+	list := &FargatePodList{}
+	if err := json.Unmarshal(out.Bytes(), list); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+
+	var got []string
+	for _, item := range list.Items {
+		got = append(got, item.Profile+" "+item.Namespace+"/"+item.Pod+" "+item.Phase)
+	}
+	expected := []string{
+		"batch batch/job-1 Running",
+		"batch batch/job-2 Pending",
+		"default default/web-1 Running",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected pods:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+	if len(list.Unmatched) != 1 || list.Unmatched[0].Pod != "job-3" {
+		t.Errorf("expected the mislabeled pending pod to match no profile, got %+v", list.Unmatched)
+	}
+}
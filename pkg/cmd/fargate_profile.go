@@ -6,9 +6,11 @@ import (
 	"io"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/eks/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/printers"
@@ -19,6 +21,8 @@ type FargateProfile = types.FargateProfile
 type FargateProfileList struct {
 	metav1.TypeMeta
 	Items []types.FargateProfile
+	// clusters are the clusters of the items when viewing several contexts
+	clusters []clusterRef
 }
 
 // Implement runtime.Object interface
@@ -30,10 +34,11 @@ func (f *FargateProfileList) DeepCopyObject() runtime.Object {
 	return &FargateProfileList{
 		TypeMeta: f.TypeMeta,
 		Items:    append([]types.FargateProfile(nil), f.Items...),
+		clusters: append([]clusterRef(nil), f.clusters...),
 	}
 }
 
-func NewFargateProfilePrinter() printers.ResourcePrinter {
+func NewFargateProfilePrinter(options printers.PrintOptions) printers.ResourcePrinter {
 	return printers.ResourcePrinterFunc(func(obj runtime.Object, w io.Writer) error {
 		list, ok := obj.(*FargateProfileList)
 		if !ok {
@@ -47,69 +52,130 @@ func NewFargateProfilePrinter() printers.ResourcePrinter {
 			},
 			ColumnDefinitions: []metav1.TableColumnDefinition{
 				{Name: "NAME", Type: "string"},
-				{Name: "SELECTOR NAMESPACE", Type: "string"},
-				{Name: "SELECTOR LABELS", Type: "string"},
+				{Name: "SELECTORS", Type: "string"},
 				{Name: "POD EXECUTION ROLE ARN", Type: "string"},
 				{Name: "SUBNETS", Type: "string"},
 				{Name: "STATUS", Type: "string"},
+				{Name: "CREATED AT", Type: "string", Priority: 1},
 			},
 		}
 
 		for _, item := range list.Items {
-			selectorNamespace := "<none>"
-			selectorLabels := "<none>"
-			if len(item.Selectors) > 0 {
-				selector := item.Selectors[0]
-				if selector.Namespace != nil {
-					selectorNamespace = *selector.Namespace
-				}
-				if len(selector.Labels) > 0 {
-					var labels []string
-					for k, v := range selector.Labels {
-						labels = append(labels, fmt.Sprintf("%s=%s", k, v))
-					}
-					selectorLabels = strings.Join(labels, ",")
-				}
-			}
-
 			table.Rows = append(table.Rows, metav1.TableRow{
 				Cells: []interface{}{
 					*item.FargateProfileName,
-					selectorNamespace,
-					selectorLabels,
+					formatFargateSelectors(item.Selectors),
 					*item.PodExecutionRoleArn,
 					strings.Join(item.Subnets, ","),
 					string(item.Status),
+					tableTime(item.CreatedAt),
 				},
 			})
 		}
 
-		return printTable(w, table, "fargate-profiles")
+		addClusterColumns(table, list.clusters)
+		addTagsColumn(table, options, func(i int) map[string]string { return list.Items[i].Tags })
+		return printTable(w, table, "fargate-profiles", options)
 	})
 }
 
+// formatFargateSelectors formats each selector as its namespace followed by
+// its sorted labels, e.g. default,batch{app=job,tier=low}.
+func formatFargateSelectors(selectors []types.FargateProfileSelector) string {
+	var formatted []string
+	for _, selector := range selectors {
+		s := aws.ToString(selector.Namespace)
+		if len(selector.Labels) > 0 {
+			s += "{" + strings.Join(sortedKeyValues(selector.Labels), ",") + "}"
+		}
+		formatted = append(formatted, s)
+	}
+	return joinOrNone(formatted)
+}
+
 func (c *EKSClient) ListFargateProfiles(ctx context.Context) ([]types.FargateProfile, error) {
 	input := &eks.ListFargateProfilesInput{
 		ClusterName: c.clusterName,
 	}
 
-	result, err := c.client.ListFargateProfiles(ctx, input)
+	var profileNames []string
+	paginator := eks.NewListFargateProfilesPaginator(c.client, input)
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		profileNames = append(profileNames, result.FargateProfileNames...)
+	}
+
+	profileNames = filterNames(c.nameFilter, profileNames, stringNames)
+	return describeAll(ctx, c.pool, profileNames, stringName, c.DescribeFargateProfile)
+}
+
+func (c *EKSClient) DescribeFargateProfile(ctx context.Context, profileName string) (types.FargateProfile, error) {
+	profile, err := c.client.DescribeFargateProfile(ctx, &eks.DescribeFargateProfileInput{
+		ClusterName:        c.clusterName,
+		FargateProfileName: &profileName,
+	})
 	if err != nil {
-		return nil, err
+		return types.FargateProfile{}, err
 	}
 
-	var profiles []types.FargateProfile
-	for _, profileName := range result.FargateProfileNames {
-		profile, err := c.client.DescribeFargateProfile(ctx, &eks.DescribeFargateProfileInput{
-			ClusterName:        c.clusterName,
-			FargateProfileName: &profileName,
-		})
-		if err != nil {
-			return nil, err
+	return *profile.FargateProfile, nil
+}
+
+func describeFargateProfile(w *prefixWriter, item types.FargateProfile) {
+	w.Write(levelZero, "Name:\t%s\n", stringOrNone(item.FargateProfileName))
+	w.Write(levelZero, "Cluster:\t%s\n", stringOrNone(item.ClusterName))
+	w.Write(levelZero, "ARN:\t%s\n", stringOrNone(item.FargateProfileArn))
+	w.Write(levelZero, "Status:\t%s\n", item.Status)
+	w.Write(levelZero, "Pod Execution Role ARN:\t%s\n", stringOrNone(item.PodExecutionRoleArn))
+	w.Write(levelZero, "Created At:\t%s\n", timeOrNone(item.CreatedAt))
+	w.WriteList(levelZero, "Subnets", item.Subnets)
+
+	if len(item.Selectors) == 0 {
+		w.Write(levelZero, "Selectors:\t<none>\n")
+	} else {
+		w.Write(levelZero, "Selectors:\n")
+		for _, selector := range item.Selectors {
+			w.Write(levelOne, "Namespace:\t%s\n", stringOrNone(selector.Namespace))
+			w.WriteMap(levelTwo, "Labels", selector.Labels)
 		}
+	}
 
-		profiles = append(profiles, *profile.FargateProfile)
+	var issues []healthIssue
+	if item.Health != nil {
+		for _, issue := range item.Health.Issues {
+			issues = append(issues, healthIssue{code: string(issue.Code), message: issue.Message, resourceIDs: issue.ResourceIds})
+		}
 	}
+	w.WriteHealthIssues(levelZero, issues)
+	w.WriteMap(levelZero, "Tags", item.Tags)
+}
+
+// fargateProfileFields are the fields matched by --field-selector. There is a
+// set per selector so that namespace matches any of the profile's namespaces.
+func fargateProfileFields(item types.FargateProfile) []fields.Set {
+	base := fields.Set{
+		"name":   aws.ToString(item.FargateProfileName),
+		"status": string(item.Status),
+	}
+	if len(item.Selectors) == 0 {
+		return []fields.Set{base}
+	}
+
+	sets := make([]fields.Set, 0, len(item.Selectors))
+	for _, selector := range item.Selectors {
+		set := fields.Set{"namespace": aws.ToString(selector.Namespace)}
+		for k, v := range base {
+			set[k] = v
+		}
+		sets = append(sets, set)
+	}
+	return sets
+}
 
-	return profiles, nil
+// fargateProfileTags are the tags matched by --selector.
+func fargateProfileTags(item types.FargateProfile) map[string]string {
+	return item.Tags
 }
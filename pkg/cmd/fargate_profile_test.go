@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"k8s.io/cli-runtime/pkg/printers"
 )
 
 func TestNewFargateProfilePrinter(t *testing.T) {
@@ -34,14 +37,12 @@ func TestNewFargateProfilePrinter(t *testing.T) {
 			},
 			expectedOutput: []string{
 				"NAME",
-				"SELECTOR NAMESPACE",
-				"SELECTOR LABELS",
+				"SELECTORS",
 				"POD EXECUTION ROLE ARN",
 				"SUBNETS",
 				"STATUS",
 				"default",
-				"default",
-				"environment=prod",
+				"default{environment=prod}",
 				"arn:aws:iam::123456789012:role/eks-fargate-pods",
 				"subnet-1234,subnet-5678",
 				"ACTIVE",
@@ -60,25 +61,41 @@ func TestNewFargateProfilePrinter(t *testing.T) {
 			},
 			expectedOutput: []string{
 				"NAME",
-				"SELECTOR NAMESPACE",
-				"SELECTOR LABELS",
+				"SELECTORS",
 				"POD EXECUTION ROLE ARN",
 				"SUBNETS",
 				"STATUS",
 				"minimal",
 				"<none>",
-				"<none>",
 				"arn:aws:iam::123456789012:role/eks-fargate-pods",
 				"subnet-abcd",
 				"CREATING",
 			},
 		},
+		{
+			name: "profile with several selectors",
+			profiles: []types.FargateProfile{
+				{
+					FargateProfileName:  stringPtr("batch"),
+					PodExecutionRoleArn: stringPtr("arn:aws:iam::123456789012:role/eks-fargate-pods"),
+					Status:              types.FargateProfileStatusActive,
+					Subnets:             []string{"subnet-abcd"},
+					Selectors: []types.FargateProfileSelector{
+						{Namespace: stringPtr("batch"), Labels: map[string]string{"tier": "low", "app": "job", "team": "data"}},
+						{Namespace: stringPtr("reports-*")},
+					},
+				},
+			},
+			expectedOutput: []string{
+				"batch{app=job,team=data,tier=low},reports-*",
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create printer
-			printer := NewFargateProfilePrinter()
+			printer := NewFargateProfilePrinter(printers.PrintOptions{})
 
 			// Create buffer to capture output
 			buf := &bytes.Buffer{}
@@ -104,3 +121,45 @@ func TestNewFargateProfilePrinter(t *testing.T) {
 		})
 	}
 }
+
+func TestListFargateProfilesPagination(t *testing.T) {
+	pages := map[string]*eks.ListFargateProfilesOutput{
+		"":       {FargateProfileNames: []string{"default"}, NextToken: stringPtr("page-2")},
+		"page-2": {FargateProfileNames: []string{"kube-system"}},
+	}
+
+	mockClient := &mockEKSClient{
+		listFargateProfilesFunc: func(ctx context.Context, params *eks.ListFargateProfilesInput) (*eks.ListFargateProfilesOutput, error) {
+			token := ""
+			if params.NextToken != nil {
+				token = *params.NextToken
+			}
+			return pages[token], nil
+		},
+		describeFargateProfileFunc: func(ctx context.Context, params *eks.DescribeFargateProfileInput) (*eks.DescribeFargateProfileOutput, error) {
+			return &eks.DescribeFargateProfileOutput{
+				FargateProfile: &types.FargateProfile{FargateProfileName: params.FargateProfileName},
+			}, nil
+		},
+	}
+
+	client := &EKSClient{
+		client:      mockClient,
+		clusterName: stringPtr("test-cluster"),
+	}
+
+	profiles, err := client.ListFargateProfiles(context.Background())
+	if err != nil {
+		t.Fatalf("ListFargateProfiles returned error: %v", err)
+	}
+
+	expected := []string{"default", "kube-system"}
+	if len(profiles) != len(expected) {
+		t.Fatalf("expected %d fargate profiles, got %d", len(expected), len(profiles))
+	}
+	for i, name := range expected {
+		if *profiles[i].FargateProfileName != name {
+			t.Errorf("fargate profile %d: expected %s, got %s", i, name, *profiles[i].FargateProfileName)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/keidarcy/kubectl-eks-viewer/pkg/filter"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// fieldLabels are the fields supported by --field-selector for each resource
+// type. The *Fields functions next to each printer must set exactly these.
+var fieldLabels = map[string][]string{
+	"access-entries":            {"principalArn", "type", "username"},
+	"addons":                    {"name", "status", "version"},
+	"cluster":                   {"name", "status", "version", "platformVersion", "authMode"},
+	"fargate-profiles":          {"name", "status", "namespace"},
+	"insights":                  {"name", "category", "status", "kubernetesVersion"},
+	"nodegroups":                {"name", "status", "version", "releaseVersion", "capacityType", "amiType"},
+	"pod-identity-associations": {"namespace", "serviceAccount", "roleArn", "ownerArn"},
+	"updates":                   {"type", "status", "resource"},
+}
+
+// untaggedResourceTypes are the resource types that cannot be tagged, which
+// --selector would always leave empty.
+var untaggedResourceTypes = map[string]bool{
+	"insights": true,
+	"updates":  true,
+}
+
+// parseFieldSelector parses a kubectl style field selector and checks that
+// every field it uses is supported by resourceType.
+func parseFieldSelector(resourceType, selector string) (fields.Selector, error) {
+	parsed, err := fields.ParseSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid field selector %q: %v", selector, err)
+	}
+
+	supported := fieldLabels[resourceType]
+	for _, req := range parsed.Requirements() {
+		found := false
+		for _, label := range supported {
+			if req.Field == label {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("field label %q not supported for %s. Supported fields are: %s",
+				req.Field, resourceType, strings.Join(supported, ", "))
+		}
+	}
+	return parsed, nil
+}
+
+// filterByFields returns the items whose fields match selector. A nil
+// selector matches everything.
+func filterByFields[T any](items []T, selector fields.Selector, fieldsOf func(T) []fields.Set) []T {
+	if selector == nil || selector.Empty() {
+		return items
+	}
+
+	var matched []T
+	for _, item := range items {
+		// An item matches if any of its field sets does, e.g. a Fargate
+		// profile with several selectors matches each of their namespaces.
+		for _, set := range fieldsOf(item) {
+			if selector.Matches(set) {
+				matched = append(matched, item)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// selectItems applies the --selector and --field-selector filters of o to
+// the items of resourceType and sorts the result by --sort-by.
+func selectItems[T any](o *Options, resourceType string, items []T, tagsOf func(T) map[string]string, fieldsOf func(T) []fields.Set) []T {
+	items = filterByFields(filter.ByTags(items, o.tagFilter, tagsOf), o.fieldFilter, fieldsOf)
+	sortItems(items, sortPaths(resourceType, o.sortBy))
+	return items
+}
+
+// nameMatcher matches resource names against shell style globs, where *
+// matches any sequence of characters and ? any single character. An empty
+// matcher matches every name.
+type nameMatcher []*regexp.Regexp
+
+func newNameMatcher(patterns []string) nameMatcher {
+	var m nameMatcher
+	for _, pattern := range patterns {
+		expr := regexp.QuoteMeta(pattern)
+		expr = strings.ReplaceAll(expr, `\*`, ".*")
+		expr = strings.ReplaceAll(expr, `\?`, ".")
+		m = append(m, regexp.MustCompile("^"+expr+"$"))
+	}
+	return m
+}
+
+// Matches reports whether any of names, the identifiers of a single item,
+// matches one of the globs.
+func (m nameMatcher) Matches(names ...string) bool {
+	if len(m) == 0 {
+		return true
+	}
+	for _, re := range m {
+		for _, name := range names {
+			if re.MatchString(name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// filterNames returns the items for which namesOf returns a matching name.
+func filterNames[T any](m nameMatcher, items []T, namesOf func(T) []string) []T {
+	if len(m) == 0 {
+		return items
+	}
+
+	var matched []T
+	for _, item := range items {
+		if m.Matches(namesOf(item)...) {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
+
+// stringNames names items that are already identified by a string.
+func stringNames(s string) []string {
+	return []string{s}
+}
+
+// supportedFieldLabels lists the field selector labels of every resource type
+// for the command help.
+func supportedFieldLabels() string {
+	resourceTypes := make([]string, 0, len(fieldLabels))
+	for resourceType := range fieldLabels {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	sort.Strings(resourceTypes)
+
+	var b strings.Builder
+	for _, resourceType := range resourceTypes {
+		fmt.Fprintf(&b, "  - %s: %s\n", resourceType, strings.Join(fieldLabels[resourceType], ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+)
+
+func TestNameMatcher(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		names    []string
+		expected bool
+	}{
+		{
+			name:     "no patterns match everything",
+			names:    []string{"anything"},
+			expected: true,
+		},
+		{
+			name:     "exact name",
+			patterns: []string{"vpc-cni"},
+			names:    []string{"vpc-cni"},
+			expected: true,
+		},
+		{
+			name:     "star glob",
+			patterns: []string{"gpu-*"},
+			names:    []string{"gpu-a100"},
+			expected: true,
+		},
+		{
+			name:     "star glob does not match a different prefix",
+			patterns: []string{"gpu-*"},
+			names:    []string{"cpu-gpu-a100"},
+			expected: false,
+		},
+		{
+			name:     "question mark matches one character",
+			patterns: []string{"ng-?"},
+			names:    []string{"ng-12"},
+			expected: false,
+		},
+		{
+			name:     "regexp characters are literal",
+			patterns: []string{"kube-proxy.v1"},
+			names:    []string{"kube-proxy-v1"},
+			expected: false,
+		},
+		{
+			name:     "any of several patterns and names",
+			patterns: []string{"coredns", "default/*"},
+			names:    []string{"a-1234", "default/my-app"},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newNameMatcher(tt.patterns).Matches(tt.names...); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseFieldSelector(t *testing.T) {
+	tests := []struct {
+		name          string
+		resourceType  string
+		selector      string
+		expectedError string
+	}{
+		{
+			name:         "supported fields",
+			resourceType: "nodegroups",
+			selector:     "status=ACTIVE,capacityType!=SPOT",
+		},
+		{
+			name:          "unsupported field",
+			resourceType:  "addons",
+			selector:      "capacityType=SPOT",
+			expectedError: `field label "capacityType" not supported for addons`,
+		},
+		{
+			name:          "invalid syntax",
+			resourceType:  "addons",
+			selector:      "status",
+			expectedError: "invalid field selector",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseFieldSelector(tt.resourceType, tt.selector)
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("expected error containing %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestFieldLabelsMatchFieldSets(t *testing.T) {
+	sets := map[string]int{
+		"access-entries":            len(accessEntryFields(AccessEntry{})[0]),
+		"addons":                    len(addonFields(types.Addon{})[0]),
+		"cluster":                   len(clusterFields(types.Cluster{})[0]),
+		"fargate-profiles":          len(fargateProfileFields(types.FargateProfile{Selectors: []types.FargateProfileSelector{{}}})[0]),
+		"insights":                  len(insightFields(types.Insight{})[0]),
+		"nodegroups":                len(nodegroupFields(types.Nodegroup{})[0]),
+		"pod-identity-associations": len(podIdentityAssociationFields(types.PodIdentityAssociation{})[0]),
+		"updates":                   len(updateFields(Update{})[0]),
+	}
+
+	for _, resourceType := range validResourceTypes {
+		if got, expected := sets[resourceType], len(fieldLabels[resourceType]); got != expected {
+			t.Errorf("%s: expected %d fields, got %d", resourceType, expected, got)
+		}
+	}
+}
+
+func TestFilterByFields(t *testing.T) {
+	profiles := []types.FargateProfile{
+		{
+			FargateProfileName: stringPtr("default"),
+			Status:             types.FargateProfileStatusActive,
+			Selectors: []types.FargateProfileSelector{
+				{Namespace: stringPtr("default")},
+				{Namespace: stringPtr("kube-system")},
+			},
+		},
+		{
+			FargateProfileName: stringPtr("batch"),
+			Status:             types.FargateProfileStatusActive,
+			Selectors:          []types.FargateProfileSelector{{Namespace: stringPtr("batch")}},
+		},
+	}
+
+	selector, err := parseFieldSelector("fargate-profiles", "namespace=kube-system")
+	if err != nil {
+		t.Fatal(err)
+	}
+	matched := filterByFields(profiles, selector, fargateProfileFields)
+	if len(matched) != 1 || *matched[0].FargateProfileName != "default" {
+		t.Errorf("expected only the default profile, got %+v", matched)
+	}
+
+	if matched := filterByFields(profiles, nil, fargateProfileFields); len(matched) != 2 {
+		t.Errorf("expected a nil selector to match everything, got %+v", matched)
+	}
+}
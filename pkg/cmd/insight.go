@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/eks/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/printers"
@@ -18,6 +20,8 @@ type Insight = types.Insight
 type InsightList struct {
 	metav1.TypeMeta
 	Items []types.Insight
+	// clusters are the clusters of the items when viewing several contexts
+	clusters []clusterRef
 }
 
 // Implement runtime.Object interface
@@ -29,10 +33,11 @@ func (i *InsightList) DeepCopyObject() runtime.Object {
 	return &InsightList{
 		TypeMeta: i.TypeMeta,
 		Items:    append([]types.Insight(nil), i.Items...),
+		clusters: append([]clusterRef(nil), i.clusters...),
 	}
 }
 
-func NewInsightPrinter() printers.ResourcePrinter {
+func NewInsightPrinter(options printers.PrintOptions) printers.ResourcePrinter {
 	return printers.ResourcePrinterFunc(func(obj runtime.Object, w io.Writer) error {
 		list, ok := obj.(*InsightList)
 		if !ok {
@@ -48,6 +53,9 @@ func NewInsightPrinter() printers.ResourcePrinter {
 				{Name: "NAME", Type: "string"},
 				{Name: "CATEGORY", Type: "string"},
 				{Name: "STATUS", Type: "string"},
+				{Name: "ID", Type: "string", Priority: 1},
+				{Name: "KUBERNETES VERSION", Type: "string", Priority: 1},
+				{Name: "LAST REFRESH", Type: "string", Priority: 1},
 			},
 		}
 
@@ -65,36 +73,131 @@ func NewInsightPrinter() printers.ResourcePrinter {
 					*item.Name,
 					string(item.Category),
 					status,
+					stringOrNone(item.Id),
+					stringOrNone(item.KubernetesVersion),
+					tableTime(item.LastRefreshTime),
 				},
 			})
 		}
 
-		return printTable(w, table, "insights")
+		addClusterColumns(table, list.clusters)
+		return printTable(w, table, "insights", options)
 	})
 }
 
 func (c *EKSClient) ListInsights(ctx context.Context) ([]types.Insight, error) {
+	summaries, err := c.listInsightSummaries(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Insights can be selected by name or ID
+	summaries = filterNames(c.nameFilter, summaries, func(summary types.InsightSummary) []string {
+		return []string{aws.ToString(summary.Name), aws.ToString(summary.Id)}
+	})
+
+	insightID := func(summary types.InsightSummary) string {
+		return *summary.Id
+	}
+
+	return describeAll(ctx, c.pool, summaries, insightID, func(ctx context.Context, summary types.InsightSummary) (types.Insight, error) {
+		return c.DescribeInsight(ctx, *summary.Id)
+	})
+}
+
+// listInsightSummaries returns the insights of the cluster matching filter,
+// which may be nil.
+func (c *EKSClient) listInsightSummaries(ctx context.Context, filter *types.InsightsFilter) ([]types.InsightSummary, error) {
 	input := &eks.ListInsightsInput{
 		ClusterName: c.clusterName,
+		Filter:      filter,
+	}
+
+	var summaries []types.InsightSummary
+	paginator := eks.NewListInsightsPaginator(c.client, input)
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, result.Insights...)
 	}
+	return summaries, nil
+}
 
-	result, err := c.client.ListInsights(ctx, input)
+func (c *EKSClient) DescribeInsight(ctx context.Context, id string) (types.Insight, error) {
+	// Get detailed information for the insight
+	detail, err := c.client.DescribeInsight(ctx, &eks.DescribeInsightInput{
+		ClusterName: c.clusterName,
+		Id:          &id,
+	})
 	if err != nil {
-		return nil, err
+		return types.Insight{}, err
 	}
+	return *detail.Insight, nil
+}
 
-	var insights []types.Insight
-	for _, summary := range result.Insights {
-		// Get detailed information for each insight
-		detail, err := c.client.DescribeInsight(ctx, &eks.DescribeInsightInput{
-			ClusterName: c.clusterName,
-			Id:          summary.Id,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to describe insight %s: %v", *summary.Id, err)
+func describeInsight(w *prefixWriter, item types.Insight) {
+	w.Write(levelZero, "ID:\t%s\n", stringOrNone(item.Id))
+	w.Write(levelZero, "Name:\t%s\n", stringOrNone(item.Name))
+	w.Write(levelZero, "Category:\t%s\n", item.Category)
+	w.Write(levelZero, "Kubernetes Version:\t%s\n", stringOrNone(item.KubernetesVersion))
+	if item.InsightStatus != nil {
+		w.Write(levelZero, "Status:\t%s\n", item.InsightStatus.Status)
+		w.Write(levelZero, "Reason:\t%s\n", stringOrNone(item.InsightStatus.Reason))
+	} else {
+		w.Write(levelZero, "Status:\t<none>\n")
+	}
+	w.Write(levelZero, "Last Refresh Time:\t%s\n", timeOrNone(item.LastRefreshTime))
+	w.Write(levelZero, "Last Transition Time:\t%s\n", timeOrNone(item.LastTransitionTime))
+	w.Write(levelZero, "Description:\t%s\n", stringOrNone(item.Description))
+	w.Write(levelZero, "Recommendation:\t%s\n", stringOrNone(item.Recommendation))
+	w.WriteMap(levelZero, "Additional Info", item.AdditionalInfo)
+
+	if summary := item.CategorySpecificSummary; summary != nil && len(summary.DeprecationDetails) > 0 {
+		w.Write(levelZero, "Deprecations:\n")
+		w.Write(levelOne, "Usage\tReplaced With\tStop Serving Version\n")
+		w.Write(levelOne, "-----\t-------------\t--------------------\n")
+		for _, detail := range summary.DeprecationDetails {
+			w.Write(levelOne, "%s\t%s\t%s\n", stringOrNone(detail.Usage), stringOrNone(detail.ReplacedWith), stringOrNone(detail.StopServingVersion))
 		}
-		insights = append(insights, *detail.Insight)
 	}
 
-	return insights, nil
+	if len(item.Resources) == 0 {
+		w.Write(levelZero, "Resources:\t<none>\n")
+	} else {
+		w.Write(levelZero, "Resources:\n")
+		w.Write(levelOne, "Resource\tStatus\n")
+		w.Write(levelOne, "--------\t------\n")
+		for _, resource := range item.Resources {
+			name := stringOrNone(resource.KubernetesResourceUri)
+			if resource.Arn != nil {
+				name = *resource.Arn
+			}
+			status := "<none>"
+			if resource.InsightStatus != nil {
+				status = string(resource.InsightStatus.Status)
+			}
+			w.Write(levelOne, "%s\t%s\n", name, status)
+		}
+	}
+}
+
+// insightFields are the fields matched by --field-selector.
+func insightFields(item types.Insight) []fields.Set {
+	set := fields.Set{
+		"name":              aws.ToString(item.Name),
+		"category":          string(item.Category),
+		"status":            "",
+		"kubernetesVersion": aws.ToString(item.KubernetesVersion),
+	}
+	if item.InsightStatus != nil {
+		set["status"] = string(item.InsightStatus.Status)
+	}
+	return []fields.Set{set}
+}
+
+// insightTags are the tags matched by --selector. Insights cannot be tagged.
+func insightTags(item types.Insight) map[string]string {
+	return nil
 }
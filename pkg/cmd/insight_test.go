@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"k8s.io/cli-runtime/pkg/printers"
 )
 
 func TestNewInsightPrinter(t *testing.T) {
@@ -61,7 +64,7 @@ func TestNewInsightPrinter(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create printer
-			printer := NewInsightPrinter()
+			printer := NewInsightPrinter(printers.PrintOptions{})
 
 			// Create buffer to capture output
 			buf := &bytes.Buffer{}
@@ -87,3 +90,45 @@ func TestNewInsightPrinter(t *testing.T) {
 		})
 	}
 }
+
+func TestListInsightsPagination(t *testing.T) {
+	pages := map[string]*eks.ListInsightsOutput{
+		"":       {Insights: []types.InsightSummary{{Id: stringPtr("insight-1")}}, NextToken: stringPtr("page-2")},
+		"page-2": {Insights: []types.InsightSummary{{Id: stringPtr("insight-2")}}},
+	}
+
+	mockClient := &mockEKSClient{
+		listInsightsFunc: func(ctx context.Context, params *eks.ListInsightsInput) (*eks.ListInsightsOutput, error) {
+			token := ""
+			if params.NextToken != nil {
+				token = *params.NextToken
+			}
+			return pages[token], nil
+		},
+		describeInsightFunc: func(ctx context.Context, params *eks.DescribeInsightInput) (*eks.DescribeInsightOutput, error) {
+			return &eks.DescribeInsightOutput{
+				Insight: &types.Insight{Id: params.Id},
+			}, nil
+		},
+	}
+
+	client := &EKSClient{
+		client:      mockClient,
+		clusterName: stringPtr("test-cluster"),
+	}
+
+	insights, err := client.ListInsights(context.Background())
+	if err != nil {
+		t.Fatalf("ListInsights returned error: %v", err)
+	}
+
+	expected := []string{"insight-1", "insight-2"}
+	if len(insights) != len(expected) {
+		t.Fatalf("expected %d insights, got %d", len(expected), len(insights))
+	}
+	for i, id := range expected {
+		if *insights[i].Id != id {
+			t.Errorf("insight %d: expected %s, got %s", i, id, *insights[i].Id)
+		}
+	}
+}
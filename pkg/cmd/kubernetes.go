@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// kubeListPageSize is the number of objects fetched per Kubernetes API call.
+const kubeListPageSize = 500
+
+// completeKubernetesClient creates the client of the Kubernetes API server of
+// the kubeconfig context, for the flags that compare EKS with what runs in
+// the cluster.
+func (o *Options) completeKubernetesClient() error {
+	if !o.needsKubernetesClient() {
+		return nil
+	}
+	return o.ensureKubernetesClient()
+}
+
+// ensureKubernetesClient creates the client of the Kubernetes API server of
+// the kubeconfig context. A client set beforehand, e.g. by tests, is kept.
+func (o *Options) ensureKubernetesClient() error {
+	if o.kubeClient != nil {
+		return nil
+	}
+	if o.kubeContextName == "" {
+		return fmt.Errorf("the Kubernetes API can only be reached through a kubeconfig context")
+	}
+
+	config, err := o.configFlags.ToRESTConfig()
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %v", err)
+	}
+	o.kubeClient, err = kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %v", err)
+	}
+	return nil
+}
+
+// needsKubernetesClient reports whether a flag that reads from the Kubernetes
+// API is set.
+func (o *Options) needsKubernetesClient() bool {
+	return o.nodes || o.checkPodIdentity || o.matchPods
+}
+
+// listAllPages calls list until the Kubernetes API returns no continue token
+// and returns the items of every page. list returns the items and continue
+// token of a page.
+func listAllPages[T any](ctx context.Context, options metav1.ListOptions, list func(context.Context, metav1.ListOptions) ([]T, string, error)) ([]T, error) {
+	options.Limit = kubeListPageSize
+	var items []T
+	for {
+		page, next, err := list(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		if next == "" {
+			return items, nil
+		}
+		options.Continue = next
+	}
+}
@@ -5,10 +5,15 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
+	"github.com/keidarcy/kubectl-eks-viewer/pkg/filter"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd/api"
 )
 
@@ -20,13 +25,57 @@ type Options struct {
 
 	genericclioptions.IOStreams
 	resourceType string
+	concurrency  int
+	failOnError  bool
+	verbose      bool
+	quiet        bool
+	clusterName  string
+	awsFlags     awsSettings
+	// noKubeconfig uses the cluster of --cluster-name without reading the
+	// kubeconfig
+	noKubeconfig bool
+	// fromFile is a snapshot to render instead of a live cluster
+	fromFile        string
+	kubeContextName string
+	// contexts are the values of --context, which may be repeated
+	contexts     []string
+	allContexts  bool
+	contextRegex string
+
+	// names are the globs given after the resource type
+	names         []string
+	fieldSelector string
+	fieldFilter   fields.Selector
+	tagSelector   string
+	tagFilter     filter.Selector
+	showTags      bool
+	sortBy        string
+	watch         bool
+	interval      time.Duration
+	// upgrades shows the addon upgrades instead of the addons
+	upgrades bool
+	// nodes adds the Kubernetes Nodes of each nodegroup
+	nodes bool
+	// checkPodIdentity checks the pod identity associations against the
+	// ServiceAccounts of the cluster
+	checkPodIdentity bool
+	// matchPods lists the pods matched by each Fargate profile
+	matchPods bool
+	// addonVersions is shared by the clients of every context
+	addonVersions *addonVersionCache
+
+	// kubeClient reaches the Kubernetes API server of the kubeconfig context
+	kubeClient kubernetes.Interface
 }
 
 func NewOptions(streams genericclioptions.IOStreams) *Options {
 	return &Options{
-		configFlags: genericclioptions.NewConfigFlags(true),
-		printFlags:  genericclioptions.NewPrintFlags(""),
-		IOStreams:   streams,
+		configFlags:   genericclioptions.NewConfigFlags(true),
+		printFlags:    genericclioptions.NewPrintFlags(""),
+		IOStreams:     streams,
+		concurrency:   defaultConcurrency,
+		interval:      defaultWatchInterval,
+		addonVersions: &addonVersionCache{},
 	}
 }
 
@@ -38,17 +87,20 @@ var validResourceTypes = []string{
 	"insights",
 	"nodegroups",
 	"pod-identity-associations",
+	"updates",
 }
 
 func NewCmd(streams genericclioptions.IOStreams) *cobra.Command {
 	o := NewOptions(streams)
 
 	cmd := &cobra.Command{
-		Use:   "kubectl eks-viewer [resource-type]",
+		Use:   "eks-viewer [resource-type] [name...]",
 		Short: "View EKS cluster resources",
 		Long: `View EKS cluster resources.
 Without arguments, shows all resource types.
-Optionally specify a resource type to show only that type.
+Optionally specify a resource type to show only that type, followed by
+names to show only those resources. Names may contain the globs * and ?.
+Resources excluded by name are never described.
 
 Valid resource types:
   - access-entries
@@ -57,7 +109,17 @@ Valid resource types:
   - fargate-profiles
   - insights
   - nodegroups
-  - pod-identity-associations`,
+  - pod-identity-associations
+  - updates
+
+Filter a resource type with --field-selector. Supported fields:
+` + supportedFieldLabels() + `
+Select resources by their AWS tags with -l/--selector, which takes the syntax
+of Kubernetes label selectors. Insights and updates cannot be tagged and are
+left out.
+
+Use "kubectl eks-viewer describe [resource-type] [name]" to show details of
+a single resource.`,
 		Example: `  # List all EKS resources 
   kubectl eks-viewer
   kubectl eks-viewer -o json
@@ -65,23 +127,121 @@ Valid resource types:
   # List specific resources
   kubectl eks-viewer addons
   kubectl eks-viewer -o json nodegroups
+  kubectl eks-viewer -o wide nodegroups
   kubectl eks-viewer nodegroups --output=jsonpath='{.items.nodegroups[*].NodegroupName}
+
+  # Filter resources by name or by field
+  kubectl eks-viewer nodegroups 'gpu-*'
+  kubectl eks-viewer addons --field-selector status!=ACTIVE
+  kubectl eks-viewer -o json nodegroups --field-selector capacityType=SPOT,status=ACTIVE
+
+  # Show the recent updates of the cluster, its nodegroups and addons
+  kubectl eks-viewer updates
+  kubectl eks-viewer updates 'nodegroup/*' --field-selector status=Failed
+
+  # Show which addons are behind or would block the next Kubernetes upgrade
+  kubectl eks-viewer addons --upgrades
+
+  # Check pod identity associations against the ServiceAccounts of the cluster
+  kubectl eks-viewer pod-identity-associations --check
+
+  # List the pods each Fargate profile matches and the pending pods matching none
+  kubectl eks-viewer fargate-profiles --match-pods
+
+  # Check whether the cluster is ready for a Kubernetes upgrade
+  kubectl eks-viewer upgrade-plan --target 1.31
+
+  # Compare the aws-auth ConfigMap with the access entries before switching to the API mode
+  kubectl eks-viewer auth-migration
+
+  # List the IAM principals that can read secrets in the payments namespace
+  kubectl eks-viewer who-can get secrets -n payments
+
+  # Filter resources by AWS tags and show the tags
+  kubectl eks-viewer -l team=payments,env!=dev --show-tags
+
+  # Sort by a column or by a JSONPath
+  kubectl eks-viewer nodegroups --sort-by='desired size'
+  kubectl eks-viewer -o yaml addons --sort-by='{.CreatedAt}'
+
+  # Watch nodegroups during an upgrade, polling every 10 seconds
+  kubectl eks-viewer nodegroups --watch --interval=10s
   
+  # Show details of a single resource
+  kubectl eks-viewer describe nodegroups my-nodegroup
+
+  # Wait for a nodegroup upgrade to finish
+  kubectl eks-viewer wait nodegroups/my-nodegroup --for=status=ACTIVE --timeout=20m
+
+  # Save the EKS state of the cluster and review it offline
+  kubectl eks-viewer snapshot -f state.yaml
+  kubectl eks-viewer --from-file state.yaml
+
+  # Compare the configuration of two clusters, or of a snapshot and the current cluster
+  kubectl eks-viewer diff --context staging --context production
+  kubectl eks-viewer diff --from-file state.yaml
+
   # Use with a specific context
-  kubectl eks-viewer --context=my-context`,
+  kubectl eks-viewer --context=my-context
+
+  # View several clusters at once
+  kubectl eks-viewer nodegroups --context=staging --context=production
+  kubectl eks-viewer addons --context-regex='^prod-' -o wide
+  kubectl eks-viewer cluster --all-contexts -o json
+
+  # Use a specific EKS cluster name and show how it was resolved otherwise
+  kubectl eks-viewer --cluster-name=my-cluster
+  kubectl eks-viewer --verbose
+
+  # Discover the clusters of the AWS account and view one without a kubeconfig entry
+  kubectl eks-viewer clusters --all-regions
+  kubectl eks-viewer --cluster-name=my-cluster --region=eu-west-1 --no-kubeconfig
+
+  # Override the AWS settings taken from the kubeconfig context
+  kubectl eks-viewer --region=us-west-2 --profile=prod --role-arn=arn:aws:iam::123456789012:role/eks-viewer
+
+  # Limit the number of concurrent EKS API calls
+  kubectl eks-viewer --concurrency=4
+
+  # Exit non-zero if any resource could not be fetched
+  kubectl eks-viewer --fail-on-error
+
+  # Hide the progress shown on stderr
+  kubectl eks-viewer --quiet`,
+		Args:         cobra.ArbitraryArgs,
 		SilenceUsage: true,
+		Annotations: map[string]string{
+			// Subcommand usage reads "kubectl eks-viewer describe" rather than "kubectl describe"
+			cobra.CommandDisplayNameAnnotation: "kubectl eks-viewer",
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
 				o.resourceType = args[0]
+				o.names = args[1:]
 			}
 
 			if err := o.Validate(); err != nil {
 				return err
 			}
+			if o.isMultiContext() {
+				return o.RunMultiContext()
+			}
 
 			if err := o.Complete(); err != nil {
 				return err
 			}
+			if err := o.completeKubernetesClient(); err != nil {
+				return err
+			}
+			if o.upgrades {
+				return o.RunAddonUpgrades(context.Background())
+			}
+			if o.checkPodIdentity {
+				return o.RunPodIdentityCheck(context.Background())
+			}
+			if o.matchPods {
+				return o.RunFargatePods(context.Background())
+			}
 
 			if err := o.Run(); err != nil {
 				return err
@@ -90,68 +250,241 @@ Valid resource types:
 		},
 	}
 
-	o.configFlags.AddFlags(cmd.Flags())
+	// Flags shared with the subcommands
+	o.configFlags.AddFlags(cmd.PersistentFlags())
+	contextFlag := cmd.PersistentFlags().Lookup("context")
+	contextFlag.Value = &contextsValue{o: o}
+	contextFlag.Usage = "The name of the kubeconfig context to use. Repeat to view several clusters at once"
+	cmd.PersistentFlags().IntVar(&o.concurrency, "concurrency", o.concurrency, "Maximum number of concurrent EKS API calls")
+	cmd.PersistentFlags().StringVar(&o.clusterName, "cluster-name", "", "Name of the EKS cluster. Defaults to the cluster identified from the kubeconfig context")
+	cmd.PersistentFlags().BoolVar(&o.noKubeconfig, "no-kubeconfig", o.noKubeconfig, "Use the cluster of --cluster-name with the AWS settings of the flags and the AWS SDK defaults instead of the kubeconfig context")
+	cmd.PersistentFlags().StringVar(&o.awsFlags.Region, "region", "", "AWS region of the EKS cluster. Defaults to the region in the kubeconfig context")
+	cmd.PersistentFlags().StringVar(&o.awsFlags.Profile, "profile", "", "AWS shared config profile. Defaults to the profile in the kubeconfig context")
+	cmd.PersistentFlags().StringVar(&o.awsFlags.RoleARN, "role-arn", "", "IAM role to assume. Defaults to the role in the kubeconfig context")
+	cmd.PersistentFlags().StringVar(&o.fromFile, "from-file", "", "Render a snapshot written by the snapshot command instead of a live cluster")
+	cmd.PersistentFlags().BoolVar(&o.quiet, "quiet", o.quiet, "Do not show progress while fetching resources")
+	cmd.PersistentFlags().BoolVar(&o.verbose, "verbose", o.verbose, "Print how the EKS cluster was identified from the kubeconfig context")
+
 	o.printFlags.AddFlags(cmd)
+	cmd.Flags().Lookup("output").Usage = fmt.Sprintf("Output format. One of: (%s).",
+		strings.Join(append(o.printFlags.AllowedFormats(), "wide"), ", "))
+	cmd.Flags().StringVar(&o.fieldSelector, "field-selector", "", "Selector (field query) to filter on, supports '=', '==', and '!='. The resource type is required")
+	cmd.Flags().StringVarP(&o.tagSelector, "selector", "l", "", "Selector (label query) matched against AWS resource tags, supports '=', '==', '!=', 'in', 'notin' and existence (e.g. -l team=payments,env!=dev)")
+	cmd.Flags().BoolVar(&o.showTags, "show-tags", o.showTags, "When printing tables, show the AWS tags of each resource as the last column")
+	cmd.Flags().StringVar(&o.sortBy, "sort-by", "", "Sort by a table column (e.g. 'desired size') or a JSONPath over the items as printed by -o json (e.g. '{.CreatedAt}'). Defaults to the name")
+	cmd.Flags().BoolVarP(&o.watch, "watch", "w", o.watch, "After listing the resources, poll them every --interval and print the rows that changed, or redraw the tables on a terminal")
+	cmd.Flags().DurationVar(&o.interval, "interval", o.interval, "Time between polls with --watch")
+	cmd.Flags().BoolVar(&o.allContexts, "all-contexts", o.allContexts, "View the EKS clusters of every kubeconfig context at once. Other contexts are skipped")
+	cmd.Flags().StringVar(&o.contextRegex, "context-regex", "", "View the EKS clusters of the kubeconfig contexts matching this regular expression at once")
+	cmd.Flags().BoolVar(&o.upgrades, "upgrades", o.upgrades, "With addons, show the latest and default versions for the cluster's Kubernetes version and whether each addon supports the next minor version")
+	cmd.Flags().BoolVar(&o.nodes, "nodes", o.nodes, "With nodegroups, count the ready and not ready Kubernetes Nodes of each nodegroup and flag missing, cordoned and mismatched kubelet version Nodes")
+	cmd.Flags().BoolVar(&o.checkPodIdentity, "check", o.checkPodIdentity, "With pod-identity-associations, check that the namespace and ServiceAccount of each association exist without an IRSA role, count the pods using them, and list the IRSA ServiceAccounts without an association")
+	cmd.Flags().BoolVar(&o.matchPods, "match-pods", o.matchPods, "With fargate-profiles, list the pods each profile matches and the pending pods that match no profile")
+	cmd.Flags().BoolVar(&o.failOnError, "fail-on-error", o.failOnError, "Exit with a non-zero status if any resource could not be fetched")
+
+	cmd.AddCommand(NewDescribeCmd(o))
+	cmd.AddCommand(NewWaitCmd(o))
+	cmd.AddCommand(NewSnapshotCmd(o))
+	cmd.AddCommand(NewDiffCmd(o))
+	cmd.AddCommand(NewClustersCmd(o))
+	cmd.AddCommand(NewUpgradePlanCmd(o))
+	cmd.AddCommand(NewAuthMigrationCmd(o))
+	cmd.AddCommand(NewWhoCanCmd(o))
 
 	return cmd
 }
 
 func (o *Options) Complete() error {
-	var err error
-	o.rawConfig, err = o.configFlags.ToRawKubeConfigLoader().RawConfig()
-	if err != nil {
-		return fmt.Errorf("failed to load kubeconfig: %v", err)
+	if len(o.contexts) > 1 {
+		return fmt.Errorf("several contexts can only be viewed when listing resources, got %d --context flags", len(o.contexts))
+	}
+	if o.fromFile != "" {
+		return o.completeFromFile()
+	}
+
+	// The kubeconfig loader reads the single --context from configFlags
+	if len(o.contexts) == 1 {
+		*o.configFlags.Context = o.contexts[0]
+	}
+	contextFlag := ""
+	if cf := o.configFlags.Context; cf != nil {
+		contextFlag = *cf
+	}
+	if o.noKubeconfig {
+		switch {
+		case o.clusterName == "":
+			return fmt.Errorf("--no-kubeconfig requires --cluster-name")
+		case contextFlag != "":
+			return fmt.Errorf("--no-kubeconfig cannot be used with --context")
+		}
+		return o.completeFromAWS()
+	}
+
+	// A named cluster needs no kubeconfig entry when there is no usable
+	// kubeconfig or context
+	if err := o.loadKubeconfig(); err != nil {
+		if o.clusterName != "" && contextFlag == "" {
+			return o.completeFromAWS()
+		}
+		return err
 	}
 
 	// Get context from flag if specified, otherwise use current-context
-	currentContext := ""
-	if cf := o.configFlags.Context; cf != nil && *cf != "" {
-		currentContext = *cf
-	} else {
+	currentContext := contextFlag
+	if currentContext == "" {
 		currentContext = o.rawConfig.CurrentContext
 	}
 
 	if currentContext == "" {
+		if o.clusterName != "" {
+			return o.completeFromAWS()
+		}
 		return fmt.Errorf("no context specified and no current-context found in kubeconfig")
 	}
 
-	context, exists := o.rawConfig.Contexts[currentContext]
+	var err error
+	o.eksClient, err = o.newEKSClientForContext(currentContext)
+	if err != nil {
+		return err
+	}
+	o.kubeContextName = currentContext
+
+	return nil
+}
+
+func (o *Options) loadKubeconfig() error {
+	var err error
+	o.rawConfig, err = o.configFlags.ToRawKubeConfigLoader().RawConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %v", err)
+	}
+	return nil
+}
+
+// newEKSClientForContext creates a client for the EKS cluster of the
+// kubeconfig context contextName.
+func (o *Options) newEKSClientForContext(contextName string) (*EKSClient, error) {
+	kubeContext, exists := o.rawConfig.Contexts[contextName]
 	if !exists {
-		return fmt.Errorf("context %q not found in kubeconfig", currentContext)
+		return nil, fmt.Errorf("context %q not found in kubeconfig", contextName)
 	}
 
-	// EKS cluster ARN format: arn:aws:eks:<region>:<account>:cluster/<cluster-name>
-	// Or cluster name format: <cluster-name>.<region>.eksctl.io
-	clusterName := context.Cluster
-	if strings.Contains(clusterName, "/") {
-		// Extract from ARN
-		parts := strings.Split(clusterName, "/")
-		clusterName = parts[len(parts)-1]
-	} else if strings.Contains(clusterName, ".eksctl.io") {
-		// Extract from eksctl format
-		clusterName = strings.Split(clusterName, ".")[0]
+	settings := awsSettingsFromKubeconfig(o.rawConfig, kubeContext).override(o.awsFlags)
+	eksClient, err := NewEKSClient(nil, o.concurrency, settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS client: %v", err)
+	}
+
+	var verbose io.Writer
+	if o.verbose {
+		verbose = o.ErrOut
+	}
+	clusterName, strategy, err := resolveClusterName(context.Background(), o.rawConfig, kubeContext, o.clusterName, eksClient, verbose)
+	if err != nil {
+		return nil, err
+	}
+	if o.verbose {
+		fmt.Fprintf(o.ErrOut, "Using EKS cluster %q (resolved from %s)\n", clusterName, strategy)
 	}
+	eksClient.clusterName = &clusterName
+	eksClient.nameFilter = newNameMatcher(o.names)
+	eksClient.addonVersions = o.addonVersions
 
-	o.eksClient, err = NewEKSClient(&clusterName)
+	return eksClient, nil
+}
+
+// completeFromAWS uses the cluster named by --cluster-name without a
+// kubeconfig context. The AWS settings come from the flags and the AWS SDK
+// defaults.
+func (o *Options) completeFromAWS() error {
+	settings := awsSettings{}.override(o.awsFlags)
+	eksClient, err := NewEKSClient(&o.clusterName, o.concurrency, settings)
 	if err != nil {
 		return fmt.Errorf("failed to create AWS client: %v", err)
 	}
+	if o.verbose {
+		fmt.Fprintf(o.ErrOut, "Using EKS cluster %q (resolved from %s, without kubeconfig)\n", o.clusterName, strategyFlag)
+	}
+	eksClient.nameFilter = newNameMatcher(o.names)
+	eksClient.addonVersions = o.addonVersions
+	o.eksClient = eksClient
+	return nil
+}
+
+// completeFromFile serves the EKS API from the --from-file snapshot. Neither
+// the kubeconfig nor AWS credentials are needed.
+func (o *Options) completeFromFile() error {
+	snapshot, err := readSnapshot(o.fromFile)
+	if err != nil {
+		return err
+	}
+	if o.verbose {
+		fmt.Fprintf(o.ErrOut, "Using snapshot of EKS cluster %q captured at %s\n",
+			snapshot.Metadata.ClusterName, snapshot.Metadata.CapturedAt.Format(time.RFC3339))
+	}
 
+	o.eksClient = &EKSClient{
+		client:      newSnapshotClient(snapshot),
+		clusterName: &snapshot.Metadata.ClusterName,
+		pool:        newWorkerPool(o.concurrency),
+		nameFilter:  newNameMatcher(o.names),
+	}
 	return nil
 }
 
 func (o *Options) Validate() error {
+	if o.concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1, got %d", o.concurrency)
+	}
+	if o.interval <= 0 {
+		return fmt.Errorf("--interval must be positive, got %s", o.interval)
+	}
+
+	if err := o.validateMultiContext(); err != nil {
+		return err
+	}
+	if err := o.validateReportFlags(); err != nil {
+		return err
+	}
+
+	var err error
+	if o.tagFilter, err = filter.Parse(o.tagSelector); err != nil {
+		return err
+	}
+	if o.sortBy != "" {
+		if err := validateSortBy(o.resourceType, o.sortBy); err != nil {
+			return err
+		}
+	}
+
 	if o.resourceType == "" {
+		if o.fieldSelector != "" {
+			return fmt.Errorf("--field-selector requires a resource type")
+		}
 		return nil
 	}
+	if o.tagSelector != "" && untaggedResourceTypes[o.resourceType] {
+		return fmt.Errorf("--selector is not supported for %s, which cannot be tagged", o.resourceType)
+	}
 
+	valid := false
 	for _, validType := range validResourceTypes {
 		if o.resourceType == validType {
-			return nil
+			valid = true
+			break
 		}
 	}
-	return fmt.Errorf("invalid resource type %q. Valid types are: %s",
-		o.resourceType, strings.Join(validResourceTypes, ", "))
+	if !valid {
+		return fmt.Errorf("invalid resource type %q. Valid types are: %s",
+			o.resourceType, strings.Join(validResourceTypes, ", "))
+	}
+
+	if o.fieldSelector != "" {
+		if o.fieldFilter, err = parseFieldSelector(o.resourceType, o.fieldSelector); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type resourceFetcher struct {
@@ -160,160 +493,285 @@ type resourceFetcher struct {
 	printer      func(interface{}, io.Writer) error
 }
 
-func (o *Options) fetchResource(ctx context.Context, f resourceFetcher) error {
-	name := strings.ReplaceAll(f.resourceType, "-", " ")
-	fmt.Printf("\r \033[36mFetching %s...\033[m", name)
-	if err := f.fetch(ctx); err != nil {
-		return fmt.Errorf("failed to list %s: %v", name, err)
-	}
-	fmt.Printf("\r%s\r", strings.Repeat(" ", 50)) // Clear the line
-	return nil
-}
-
-func (o *Options) Run() error {
-	ctx := context.Background()
-	resourceList := &ResourceList{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: "v1",
-			Kind:       "EksResourceList",
-		},
-	}
-
-	printer, err := o.printFlags.ToPrinter()
-	if err != nil {
-		return err
-	}
-	isTableFormat := *o.printFlags.OutputFormat == "" || *o.printFlags.OutputFormat == "wide"
-
-	// Define all available resources
-	allResources := []resourceFetcher{
+// resourceFetchers returns a fetcher for every resource type, in the order
+// they are printed. Fetched items are stored in resourceList.
+func (o *Options) resourceFetchers(resourceList *ResourceList, tableOptions printers.PrintOptions) []resourceFetcher {
+	return []resourceFetcher{
 		{
 			resourceType: "cluster",
 			fetch: func(ctx context.Context) error {
 				cluster, err := o.eksClient.DescribeCluster(ctx)
-				resourceList.Items.Cluster = cluster
+				cluster = filterNames(o.eksClient.nameFilter, cluster, clusterNames)
+				resourceList.Items.Cluster = selectItems(o, "cluster", cluster, clusterTags, clusterFields)
 				return err
 			},
 			printer: func(obj interface{}, w io.Writer) error {
-				return NewClusterPrinter().PrintObj(&ClusterList{Items: resourceList.Items.Cluster}, w)
+				return NewClusterPrinter(tableOptions).PrintObj(&ClusterList{Items: resourceList.Items.Cluster, clusters: resourceList.clusters["cluster"]}, w)
 			},
 		},
 		{
 			resourceType: "access-entries",
 			fetch: func(ctx context.Context) error {
 				entries, err := o.eksClient.ListAccessEntries(ctx)
-				resourceList.Items.AccessEntries = entries
+				resourceList.Items.AccessEntries = selectItems(o, "access-entries", entries, accessEntryTags, accessEntryFields)
 				return err
 			},
 			printer: func(obj interface{}, w io.Writer) error {
-				return NewAccessEntryPrinter(o.eksClient).PrintObj(&AccessEntryList{Items: resourceList.Items.AccessEntries}, w)
+				return NewAccessEntryPrinter(o.eksClient, tableOptions).PrintObj(&AccessEntryList{Items: resourceList.Items.AccessEntries, clusters: resourceList.clusters["access-entries"]}, w)
 			},
 		},
 		{
 			resourceType: "addons",
 			fetch: func(ctx context.Context) error {
 				addons, err := o.eksClient.ListAddons(ctx)
-				resourceList.Items.Addons = addons
+				resourceList.Items.Addons = selectItems(o, "addons", addons, addonTags, addonFields)
+				// Snapshots do not record addon versions
+				if tableOptions.Wide && o.fromFile == "" && (err == nil || isPartialError(err)) {
+					upgrades, _, _, upgradesErr := o.eksClient.clusterAddonUpgrades(ctx, resourceList.Items.Addons)
+					if upgradesErr != nil {
+						// The addons are still printed, without their versions
+						return withItemError(err, ItemError{Name: "addon versions", Err: upgradesErr})
+					}
+					resourceList.Items.addonUpgrades = upgrades
+				}
 				return err
 			},
 			printer: func(obj interface{}, w io.Writer) error {
-				return NewAddonPrinter().PrintObj(&AddonList{Items: resourceList.Items.Addons}, w)
+				return NewAddonPrinter(tableOptions).PrintObj(&AddonList{Items: resourceList.Items.Addons, upgrades: resourceList.Items.addonUpgrades, clusters: resourceList.clusters["addons"]}, w)
 			},
 		},
 		{
 			resourceType: "nodegroups",
 			fetch: func(ctx context.Context) error {
 				nodeGroups, err := o.eksClient.ListNodeGroups(ctx)
-				resourceList.Items.Nodegroups = nodeGroups
+				resourceList.Items.Nodegroups = selectItems(o, "nodegroups", nodeGroups, nodegroupTags, nodegroupFields)
+				if o.nodes && (err == nil || isPartialError(err)) {
+					nodes, nodesErr := o.listNodegroupNodes(ctx, resourceList.Items.Nodegroups)
+					if nodesErr != nil {
+						// The nodegroups are still printed, without their Nodes
+						return withItemError(err, ItemError{Name: "Kubernetes nodes", Err: nodesErr})
+					}
+					resourceList.Items.NodegroupNodes = nodes
+				}
 				return err
 			},
 			printer: func(obj interface{}, w io.Writer) error {
-				return NewNodeGroupPrinter().PrintObj(&NodeGroupList{Items: resourceList.Items.Nodegroups}, w)
+				return NewNodeGroupPrinter(tableOptions).PrintObj(&NodeGroupList{Items: resourceList.Items.Nodegroups, nodes: resourceList.Items.NodegroupNodes, clusters: resourceList.clusters["nodegroups"]}, w)
 			},
 		},
 		{
 			resourceType: "fargate-profiles",
 			fetch: func(ctx context.Context) error {
 				fargateProfiles, err := o.eksClient.ListFargateProfiles(ctx)
-				resourceList.Items.FargateProfiles = fargateProfiles
+				resourceList.Items.FargateProfiles = selectItems(o, "fargate-profiles", fargateProfiles, fargateProfileTags, fargateProfileFields)
 				return err
 			},
 			printer: func(obj interface{}, w io.Writer) error {
-				return NewFargateProfilePrinter().PrintObj(&FargateProfileList{Items: resourceList.Items.FargateProfiles}, w)
+				return NewFargateProfilePrinter(tableOptions).PrintObj(&FargateProfileList{Items: resourceList.Items.FargateProfiles, clusters: resourceList.clusters["fargate-profiles"]}, w)
 			},
 		},
 		{
 			resourceType: "pod-identity-associations",
 			fetch: func(ctx context.Context) error {
 				podIdentityAssociations, err := o.eksClient.ListPodIdentityAssociations(ctx)
-				resourceList.Items.PodIdentityAssociations = podIdentityAssociations
+				resourceList.Items.PodIdentityAssociations = selectItems(o, "pod-identity-associations", podIdentityAssociations, podIdentityAssociationTags, podIdentityAssociationFields)
 				return err
 			},
 			printer: func(obj interface{}, w io.Writer) error {
-				return NewPodIdentityAssociationPrinter().PrintObj(&PodIdentityAssociationList{Items: resourceList.Items.PodIdentityAssociations}, w)
+				return NewPodIdentityAssociationPrinter(tableOptions).PrintObj(&PodIdentityAssociationList{Items: resourceList.Items.PodIdentityAssociations, clusters: resourceList.clusters["pod-identity-associations"]}, w)
 			},
 		},
 		{
 			resourceType: "insights",
 			fetch: func(ctx context.Context) error {
 				insights, err := o.eksClient.ListInsights(ctx)
-				resourceList.Items.Insights = insights
+				resourceList.Items.Insights = selectItems(o, "insights", insights, insightTags, insightFields)
 				return err
 			},
 			printer: func(obj interface{}, w io.Writer) error {
-				return NewInsightPrinter().PrintObj(&InsightList{Items: resourceList.Items.Insights}, w)
+				return NewInsightPrinter(tableOptions).PrintObj(&InsightList{Items: resourceList.Items.Insights, clusters: resourceList.clusters["insights"]}, w)
+			},
+		},
+		{
+			resourceType: "updates",
+			fetch: func(ctx context.Context) error {
+				updates, err := o.eksClient.ListUpdates(ctx)
+				resourceList.Items.Updates = selectItems(o, "updates", updates, updateTags, updateFields)
+				return err
+			},
+			printer: func(obj interface{}, w io.Writer) error {
+				return NewUpdatePrinter(tableOptions).PrintObj(&UpdateList{Items: resourceList.Items.Updates, clusters: resourceList.clusters["updates"]}, w)
 			},
 		},
 	}
+}
 
-	// Select resources to process
-	resourcesToFetch := allResources
-	if o.resourceType != "" {
-		found := false
+// selectFetchers returns the fetcher of the requested resource type, or all
+// of them. Resource types that cannot be tagged are left out when selecting
+// by tags.
+func (o *Options) selectFetchers(allResources []resourceFetcher) ([]resourceFetcher, error) {
+	if o.resourceType == "" {
+		if o.tagSelector == "" {
+			return allResources, nil
+		}
+		var tagged []resourceFetcher
 		for _, res := range allResources {
-			if res.resourceType == o.resourceType {
-				resourcesToFetch = []resourceFetcher{res}
-				found = true
-				break
+			if !untaggedResourceTypes[res.resourceType] {
+				tagged = append(tagged, res)
 			}
 		}
-		if !found {
-			return fmt.Errorf("resource type %q not supported. Valid types are: %s",
-				o.resourceType, strings.Join(validResourceTypes, ", "))
+		return tagged, nil
+	}
+	for _, res := range allResources {
+		if res.resourceType == o.resourceType {
+			return []resourceFetcher{res}, nil
+		}
+	}
+	return nil, fmt.Errorf("resource type %q not supported. Valid types are: %s",
+		o.resourceType, strings.Join(validResourceTypes, ", "))
+}
+
+// fetchAll starts fetching every resource concurrently and returns one
+// channel per resource, in the same order, that receives its fetch error.
+func (o *Options) fetchAll(ctx context.Context, resources []resourceFetcher, progress progressReporter) []<-chan error {
+	resourceTypes := make([]string, 0, len(resources))
+	for _, res := range resources {
+		resourceTypes = append(resourceTypes, res.resourceType)
+	}
+	progress.Start(resourceTypes)
+
+	done := make([]<-chan error, len(resources))
+	for i, res := range resources {
+		ch := make(chan error, 1)
+		done[i] = ch
+		go func() {
+			err := res.fetch(ctx)
+			progress.Done(res.resourceType)
+			ch <- err
+		}()
+	}
+	return done
+}
+
+// fetchResource waits for one resource to be fetched while showing progress,
+// and clears the progress line before its section is printed.
+func (o *Options) fetchResource(done <-chan error, progress progressReporter) error {
+	progress.Show()
+	err := <-done
+	progress.Clear()
+	return err
+}
+
+// printResource prints one table section and returns the errors recorded for
+// it. A resource type that failed entirely is printed as a header followed by
+// its error; items that failed are reported below the table.
+func (o *Options) printResource(out io.Writer, res resourceFetcher, fetchErr error) ([]ResourceError, error) {
+	var resourceErrors []ResourceError
+	if fetchErr != nil {
+		resourceErrors = toResourceErrors(res.resourceType, fetchErr)
+	}
+
+	if fetchErr != nil && !isPartialError(fetchErr) {
+		printSectionHeader(out, res.resourceType)
+	} else if err := res.printer(nil, out); err != nil {
+		if !isPartialError(err) {
+			return nil, err
+		}
+		resourceErrors = append(resourceErrors, toResourceErrors(res.resourceType, err)...)
+	}
+
+	printResourceErrors(out, resourceErrors)
+	return resourceErrors, nil
+}
+
+// checkErrors turns the recorded errors into the command's exit status. By
+// default a run that printed anything succeeds; --fail-on-error makes any
+// recorded error fatal.
+func (o *Options) checkErrors(resourceErrors []ResourceError) error {
+	if len(resourceErrors) == 0 || !o.failOnError {
+		return nil
+	}
+	return fmt.Errorf("failed to fetch %d EKS resource(s)", len(resourceErrors))
+}
+
+// isTableFormat reports whether resources are printed as tables.
+func (o *Options) isTableFormat() bool {
+	return *o.printFlags.OutputFormat == "" || *o.printFlags.OutputFormat == "wide"
+}
+
+func (o *Options) Run() error {
+	if o.watch {
+		return o.RunWatch()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	return o.runOnce(ctx, o.Out)
+}
+
+// runOnce fetches the selected resources once and prints them to out.
+func (o *Options) runOnce(ctx context.Context, out io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	resourceList := &ResourceList{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "EksResourceList",
+		},
+	}
+
+	isTableFormat := o.isTableFormat()
+	tableOptions := printers.PrintOptions{
+		Wide:       *o.printFlags.OutputFormat == "wide",
+		ShowLabels: o.showTags,
+	}
+
+	var printer printers.ResourcePrinter
+	if !isTableFormat {
+		var err error
+		if printer, err = o.printFlags.ToPrinter(); err != nil {
+			return err
 		}
 	}
 
+	resourcesToFetch, err := o.selectFetchers(o.resourceFetchers(resourceList, tableOptions))
+	if err != nil {
+		return err
+	}
+
+	// Resource types are fetched concurrently; their describe calls share the
+	// client's worker pool. Output is still printed in the order above.
+	progress := newProgressReporter(o.ErrOut, o.quiet)
+	done := o.fetchAll(ctx, resourcesToFetch, progress)
+
 	if isTableFormat {
-		// Fetch and print each resource type individually
+		// Print each resource type as soon as it and everything before it is fetched
 		for i, res := range resourcesToFetch {
-			if err := o.fetchResource(ctx, res); err != nil {
-				return err
-			}
-			if err := res.printer(nil, o.Out); err != nil {
+			resourceErrors, err := o.printResource(out, res, o.fetchResource(done[i], progress))
+			if err != nil {
 				return err
 			}
+			resourceList.Errors = append(resourceList.Errors, resourceErrors...)
 			// Add newline between resource types, but not after the last one
 			if i < len(resourcesToFetch)-1 {
-				fmt.Fprintln(o.Out)
+				fmt.Fprintln(out)
 			}
 		}
-		return nil
+		return o.checkErrors(resourceList.Errors)
 	}
 
 	// For non-table formats, fetch all requested resources
-	progressMsg := "Fetching EKS resources..."
-	if o.resourceType != "" {
-		progressMsg = fmt.Sprintf("Fetching %s...", o.resourceType)
-	}
-	fmt.Printf("\r \033[36m%s\033[m", progressMsg)
-
-	for _, res := range resourcesToFetch {
-		if err := res.fetch(ctx); err != nil {
-			return err
+	for i, res := range resourcesToFetch {
+		if err := <-done[i]; err != nil {
+			resourceList.Errors = append(resourceList.Errors, toResourceErrors(res.resourceType, err)...)
 		}
 	}
 
 	// Clear the progress line
-	fmt.Printf("\r%s\r", strings.Repeat(" ", 50))
-	return printer.PrintObj(resourceList, o.Out)
+	progress.Clear()
+	if err := printer.PrintObj(resourceList, out); err != nil {
+		return err
+	}
+	printResourceErrors(o.ErrOut, resourceList.Errors)
+	return o.checkErrors(resourceList.Errors)
 }
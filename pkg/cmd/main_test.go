@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func newTestOptions(mockClient *mockEKSClient, outputFormat string) (*Options, *bytes.Buffer, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	o := NewOptions(genericclioptions.IOStreams{Out: out, ErrOut: errOut})
+	o.eksClient = &EKSClient{
+		client:        mockClient,
+		clusterName:   stringPtr("test-cluster"),
+		addonVersions: o.addonVersions,
+	}
+	*o.printFlags.OutputFormat = outputFormat
+	return o, out, errOut
+}
+
+// newPartiallyFailingMock denies ListInsights and fails to describe one of two
+// nodegroups.
+func newPartiallyFailingMock() *mockEKSClient {
+	mockClient := newMockEKSClient()
+	mockClient.listInsightsFunc = func(ctx context.Context, params *eks.ListInsightsInput) (*eks.ListInsightsOutput, error) {
+		return nil, errors.New("AccessDeniedException: not authorized to perform eks:ListInsights")
+	}
+	mockClient.listNodegroupsFunc = func(ctx context.Context, params *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error) {
+		return &eks.ListNodegroupsOutput{Nodegroups: []string{"ng-ok", "ng-broken"}}, nil
+	}
+	mockClient.describeNodegroupFunc = func(ctx context.Context, params *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error) {
+		if *params.NodegroupName == "ng-broken" {
+			return nil, errors.New("AccessDeniedException: not authorized to perform eks:DescribeNodegroup")
+		}
+		return &eks.DescribeNodegroupOutput{
+			Nodegroup: &types.Nodegroup{
+				NodegroupName: params.NodegroupName,
+				Status:        types.NodegroupStatusActive,
+				ScalingConfig: &types.NodegroupScalingConfig{
+					DesiredSize: int32Ptr(2),
+					MinSize:     int32Ptr(1),
+					MaxSize:     int32Ptr(3),
+				},
+				Version: stringPtr("1.31"),
+			},
+		}, nil
+	}
+	return mockClient
+}
+
+func TestRunPartialFailureTable(t *testing.T) {
+	o, out, _ := newTestOptions(newPartiallyFailingMock(), "")
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	output := out.String()
+	expectedOutput := []string{
+		"=== cluster ===",
+		"test-cluster",
+		"=== nodegroups ===",
+		"ng-ok",
+		`error: failed to describe "ng-broken": AccessDeniedException`,
+		"=== insights ===",
+		"error: failed to list insights: AccessDeniedException",
+	}
+	for _, expected := range expectedOutput {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Output does not contain expected string: %s\nGot: %s", expected, output)
+		}
+	}
+}
+
+func TestRunPartialFailureJSON(t *testing.T) {
+	o, out, _ := newTestOptions(newPartiallyFailingMock(), "json")
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	var result struct {
+		Errors []ResourceError `json:"errors"`
+		Items  struct {
+			Nodegroups []types.Nodegroup `json:"nodegroups"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("failed to parse output: %v\nGot: %s", err, out.String())
+	}
+
+	if len(result.Items.Nodegroups) != 1 || *result.Items.Nodegroups[0].NodegroupName != "ng-ok" {
+		t.Errorf("expected only ng-ok in nodegroups, got %+v", result.Items.Nodegroups)
+	}
+
+	expectedErrors := []ResourceError{
+		{ResourceType: "nodegroups", Name: "ng-broken"},
+		{ResourceType: "insights"},
+	}
+	if len(result.Errors) != len(expectedErrors) {
+		t.Fatalf("expected %d errors, got %+v", len(expectedErrors), result.Errors)
+	}
+	for i, expected := range expectedErrors {
+		got := result.Errors[i]
+		if got.ResourceType != expected.ResourceType || got.Name != expected.Name || got.Message == "" {
+			t.Errorf("error %d: expected %+v, got %+v", i, expected, got)
+		}
+	}
+}
+
+func TestRunFailOnError(t *testing.T) {
+	o, out, _ := newTestOptions(newPartiallyFailingMock(), "")
+	o.failOnError = true
+
+	if err := o.Run(); err == nil {
+		t.Fatal("expected error with --fail-on-error, got nil")
+	}
+	if !strings.Contains(out.String(), "ng-ok") {
+		t.Errorf("expected successful sections to be printed, got: %s", out.String())
+	}
+}
+
+func TestRunStdoutContainsOnlyPrinterOutput(t *testing.T) {
+	o, out, errOut := newTestOptions(newPartiallyFailingMock(), "json")
+
+	// Anything written to the process stdout directly would end up mixed
+	// into the printer output of `kubectl eks-viewer -o json | jq`.
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	runErr := o.Run()
+	os.Stdout = stdout
+	w.Close()
+
+	leaked, _ := io.ReadAll(r)
+	if runErr != nil {
+		t.Fatalf("Run returned error: %v", runErr)
+	}
+	if len(leaked) != 0 {
+		t.Errorf("expected nothing written to stdout directly, got %q", leaked)
+	}
+	if !json.Valid(out.Bytes()) {
+		t.Errorf("expected only JSON on the output stream, got %q", out.String())
+	}
+	if strings.Contains(out.String(), "\033") || strings.Contains(errOut.String(), "\033") {
+		t.Errorf("expected no escape sequences when not writing to a terminal")
+	}
+}
+
+func TestRunFilters(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		described []string
+	)
+	mockClient := newMockEKSClient()
+	mockClient.listNodegroupsFunc = func(ctx context.Context, params *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error) {
+		return &eks.ListNodegroupsOutput{Nodegroups: []string{"gpu-spot", "gpu-ondemand", "general"}}, nil
+	}
+	mockClient.describeNodegroupFunc = func(ctx context.Context, params *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error) {
+		mu.Lock()
+		described = append(described, *params.NodegroupName)
+		mu.Unlock()
+		capacityType := types.CapacityTypesOnDemand
+		if strings.HasSuffix(*params.NodegroupName, "spot") {
+			capacityType = types.CapacityTypesSpot
+		}
+		return &eks.DescribeNodegroupOutput{
+			Nodegroup: &types.Nodegroup{
+				NodegroupName: params.NodegroupName,
+				CapacityType:  capacityType,
+			},
+		}, nil
+	}
+
+	o, out, _ := newTestOptions(mockClient, "json")
+	o.resourceType = "nodegroups"
+	o.fieldSelector = "capacityType=SPOT"
+	o.eksClient.nameFilter = newNameMatcher([]string{"gpu-*"})
+	if err := o.Validate(); err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	for _, name := range described {
+		if name == "general" {
+			t.Errorf("expected nodegroups excluded by name not to be described, got %v", described)
+		}
+	}
+
+	var result struct {
+		Items struct {
+			Nodegroups []types.Nodegroup `json:"nodegroups"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("failed to parse output: %v\nGot: %s", err, out.String())
+	}
+	if len(result.Items.Nodegroups) != 1 || *result.Items.Nodegroups[0].NodegroupName != "gpu-spot" {
+		t.Errorf("expected only gpu-spot, got %+v", result.Items.Nodegroups)
+	}
+}
+
+func TestValidateFieldSelectorRequiresResourceType(t *testing.T) {
+	o, _, _ := newTestOptions(newMockEKSClient(), "")
+	o.fieldSelector = "status=ACTIVE"
+
+	if err := o.Validate(); err == nil {
+		t.Error("expected error for --field-selector without a resource type, got nil")
+	}
+}
+
+func TestValidateTagSelectorUntaggedResourceType(t *testing.T) {
+	o, _, _ := newTestOptions(newMockEKSClient(), "")
+	o.resourceType, o.tagSelector = "insights", "team=network"
+
+	if err := o.Validate(); err == nil || !strings.Contains(err.Error(), "cannot be tagged") {
+		t.Errorf("expected error for --selector with insights, got %v", err)
+	}
+}
+
+func TestRunTagSelector(t *testing.T) {
+	mockClient := newMockEKSClient()
+	mockClient.listAddonsFunc = func(ctx context.Context, params *eks.ListAddonsInput) (*eks.ListAddonsOutput, error) {
+		return &eks.ListAddonsOutput{Addons: []string{"vpc-cni", "coredns"}}, nil
+	}
+	mockClient.describeAddonFunc = func(ctx context.Context, params *eks.DescribeAddonInput) (*eks.DescribeAddonOutput, error) {
+		addon := &types.Addon{
+			AddonName:    params.AddonName,
+			AddonVersion: stringPtr("v1.0.0"),
+			Status:       types.AddonStatusActive,
+			Health:       &types.AddonHealth{},
+		}
+		if *params.AddonName == "vpc-cni" {
+			addon.Tags = map[string]string{"team": "network"}
+		}
+		return &eks.DescribeAddonOutput{Addon: addon}, nil
+	}
+
+	o, out, _ := newTestOptions(mockClient, "")
+	o.tagSelector = "team=network"
+	o.showTags = true
+	if err := o.Validate(); err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	output := out.String()
+	for _, expected := range []string{"=== addons ===", "vpc-cni", "team=network"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Output does not contain expected string: %s\nGot: %s", expected, output)
+		}
+	}
+	// Insights and updates cannot be tagged
+	for _, unexpected := range []string{"coredns", "=== insights ===", "=== updates ==="} {
+		if strings.Contains(output, unexpected) {
+			t.Errorf("Output contains unexpected string: %s\nGot: %s", unexpected, output)
+		}
+	}
+}
+
+func TestRunSortByJSON(t *testing.T) {
+	mockClient := newPartiallyFailingMock()
+	mockClient.listNodegroupsFunc = func(ctx context.Context, params *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error) {
+		return &eks.ListNodegroupsOutput{Nodegroups: []string{"ng-b", "ng-c", "ng-a"}}, nil
+	}
+
+	o, out, _ := newTestOptions(mockClient, "json")
+	o.resourceType = "nodegroups"
+	o.sortBy = "{.NodegroupName}"
+	if err := o.Validate(); err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	var result struct {
+		Items struct {
+			Nodegroups []types.Nodegroup `json:"nodegroups"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("failed to parse output: %v\nGot: %s", err, out.String())
+	}
+	var names []string
+	for _, ng := range result.Items.Nodegroups {
+		names = append(names, *ng.NodegroupName)
+	}
+	if strings.Join(names, ",") != "ng-a,ng-b,ng-c" {
+		t.Errorf("expected nodegroups sorted by name, got %v", names)
+	}
+}
@@ -0,0 +1,378 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// clusterRef identifies the cluster an item was fetched from when several
+// contexts are viewed at once.
+type clusterRef struct {
+	Context string
+	Cluster string
+	Region  string
+	Account string
+	client  *EKSClient
+}
+
+// MultiClusterResourceList is the -o json and -o yaml output of several
+// contexts, keyed by kubeconfig context.
+type MultiClusterResourceList struct {
+	metav1.TypeMeta
+	Clusters map[string]ClusterResourceList `json:"clusters"`
+	// Skipped are the contexts that are not EKS clusters, with the reason
+	Skipped map[string]string `json:"skipped,omitempty"`
+}
+
+// ClusterResourceList holds the resources of the cluster of one context. Error
+// is set instead when the cluster could not be reached at all.
+type ClusterResourceList struct {
+	Cluster string          `json:"cluster"`
+	Region  string          `json:"region,omitempty"`
+	Account string          `json:"account,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Errors  []ResourceError `json:"errors,omitempty"`
+	Items   *ResourceItems  `json:"items,omitempty"`
+}
+
+// Implement runtime.Object interface for MultiClusterResourceList
+func (m *MultiClusterResourceList) GetObjectKind() schema.ObjectKind {
+	return &m.TypeMeta
+}
+
+func (m *MultiClusterResourceList) DeepCopyObject() runtime.Object {
+	clusters := make(map[string]ClusterResourceList, len(m.Clusters))
+	for name, cluster := range m.Clusters {
+		cluster.Errors = append([]ResourceError(nil), cluster.Errors...)
+		if cluster.Items != nil {
+			items := cluster.Items.deepCopy()
+			cluster.Items = &items
+		}
+		clusters[name] = cluster
+	}
+	skipped := make(map[string]string, len(m.Skipped))
+	for name, reason := range m.Skipped {
+		skipped[name] = reason
+	}
+	return &MultiClusterResourceList{TypeMeta: m.TypeMeta, Clusters: clusters, Skipped: skipped}
+}
+
+// contextsValue makes --context repeatable.
+type contextsValue struct {
+	o *Options
+}
+
+func (v *contextsValue) String() string {
+	return strings.Join(v.o.contexts, ",")
+}
+
+func (v *contextsValue) Set(s string) error {
+	v.o.contexts = append(v.o.contexts, s)
+	return nil
+}
+
+func (v *contextsValue) Type() string {
+	return "stringArray"
+}
+
+// isMultiContext reports whether several contexts are viewed at once.
+func (o *Options) isMultiContext() bool {
+	return o.allContexts || o.contextRegex != "" || len(o.contexts) > 1
+}
+
+// validateMultiContext rejects the flags that only apply to a single cluster.
+func (o *Options) validateMultiContext() error {
+	if !o.isMultiContext() {
+		return nil
+	}
+	if o.contextRegex != "" {
+		if _, err := regexp.Compile(o.contextRegex); err != nil {
+			return fmt.Errorf("invalid --context-regex %q: %v", o.contextRegex, err)
+		}
+	}
+	switch {
+	case o.fromFile != "":
+		return fmt.Errorf("--from-file cannot be used with several contexts")
+	case o.clusterName != "":
+		return fmt.Errorf("--cluster-name cannot be used with several contexts")
+	case o.watch:
+		return fmt.Errorf("--watch cannot be used with several contexts")
+	}
+	return nil
+}
+
+// selectContexts returns the contexts given with --context, followed by those
+// matching --context-regex, or every context with --all-contexts.
+func (o *Options) selectContexts() ([]string, error) {
+	var all []string
+	for name := range o.rawConfig.Contexts {
+		all = append(all, name)
+	}
+	sort.Strings(all)
+	if o.allContexts {
+		return all, nil
+	}
+
+	var selected []string
+	seen := map[string]bool{}
+	for _, name := range o.contexts {
+		if _, ok := o.rawConfig.Contexts[name]; !ok {
+			return nil, fmt.Errorf("context %q not found in kubeconfig", name)
+		}
+		if !seen[name] {
+			selected = append(selected, name)
+			seen[name] = true
+		}
+	}
+	if o.contextRegex != "" {
+		pattern := regexp.MustCompile(o.contextRegex)
+		for _, name := range all {
+			if pattern.MatchString(name) && !seen[name] {
+				selected = append(selected, name)
+				seen[name] = true
+			}
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no kubeconfig context matches --context-regex %q", o.contextRegex)
+	}
+	return selected, nil
+}
+
+// RunMultiContext shows the selected resources of every selected context.
+func (o *Options) RunMultiContext() error {
+	if err := o.loadKubeconfig(); err != nil {
+		return err
+	}
+	contextNames, err := o.selectContexts()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	return o.runContexts(ctx, o.Out, contextNames, o.newEKSClientForContext)
+}
+
+// contextResult is what was fetched from the cluster of one context. err is
+// set when the cluster could not be reached.
+type contextResult struct {
+	cluster clusterRef
+	list    ResourceList
+	err     error
+}
+
+// runContexts fetches the contexts concurrently, each with its own client
+// created by connect, and prints their resources together. Contexts that are
+// not EKS clusters are skipped with a note.
+func (o *Options) runContexts(ctx context.Context, out io.Writer, contextNames []string, connect func(string) (*EKSClient, error)) error {
+	var eksContexts []string
+	skipped := map[string]string{}
+	for _, name := range contextNames {
+		if !isEKSContext(o.rawConfig, o.rawConfig.Contexts[name]) {
+			skipped[name] = "not an EKS cluster"
+			fmt.Fprintf(o.ErrOut, "Skipping context %q: not an EKS cluster\n", name)
+			continue
+		}
+		eksContexts = append(eksContexts, name)
+	}
+
+	progress := newProgressReporter(o.ErrOut, o.quiet)
+	progress.Start(eksContexts)
+	results, err := mapConcurrent(ctx, newWorkerPool(o.concurrency), eksContexts, func(ctx context.Context, name string) (contextResult, error) {
+		defer progress.Done(name)
+		return o.fetchContext(ctx, name, connect), nil
+	})
+	progress.Clear()
+	if err != nil {
+		return err
+	}
+
+	errorCount := 0
+	for _, result := range results {
+		errorCount += len(result.list.Errors)
+		if result.err != nil {
+			errorCount++
+		}
+	}
+
+	if o.isTableFormat() {
+		if err := o.printContextTables(out, results); err != nil {
+			return err
+		}
+	} else {
+		printer, err := o.printFlags.ToPrinter()
+		if err != nil {
+			return err
+		}
+		list := &MultiClusterResourceList{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EksMultiClusterResourceList"},
+			Clusters: map[string]ClusterResourceList{},
+		}
+		if len(skipped) > 0 {
+			list.Skipped = skipped
+		}
+		for _, result := range results {
+			cluster := ClusterResourceList{
+				Cluster: result.cluster.Cluster,
+				Region:  result.cluster.Region,
+				Account: result.cluster.Account,
+			}
+			if result.err != nil {
+				cluster.Error = result.err.Error()
+			} else {
+				cluster.Errors = result.list.Errors
+				cluster.Items = &result.list.Items
+			}
+			list.Clusters[result.cluster.Context] = cluster
+		}
+		if err := printer.PrintObj(list, out); err != nil {
+			return err
+		}
+		printContextErrors(o.ErrOut, results, "")
+	}
+
+	if errorCount > 0 && o.failOnError {
+		return fmt.Errorf("failed to fetch %d EKS resource(s)", errorCount)
+	}
+	return nil
+}
+
+// fetchContext fetches the selected resources of the cluster of a context.
+func (o *Options) fetchContext(ctx context.Context, contextName string, connect func(string) (*EKSClient, error)) contextResult {
+	result := contextResult{cluster: clusterRef{Context: contextName}}
+
+	eksClient, err := connect(contextName)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	result.cluster.Cluster = *eksClient.clusterName
+	result.cluster.client = eksClient
+
+	// The cluster ARN holds the region and account shown for every item
+	clusters, err := eksClient.DescribeCluster(ctx)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	if clusters[0].Arn != nil {
+		if clusterARN, err := arn.Parse(*clusters[0].Arn); err == nil {
+			result.cluster.Region = clusterARN.Region
+			result.cluster.Account = clusterARN.AccountID
+		}
+	}
+
+	contextOptions := *o
+	contextOptions.eksClient = eksClient
+	contextOptions.kubeContextName = contextName
+	resources, err := contextOptions.selectFetchers(contextOptions.resourceFetchers(&result.list, printers.PrintOptions{Wide: *o.printFlags.OutputFormat == "wide"}))
+	if err != nil {
+		result.err = err
+		return result
+	}
+	done := contextOptions.fetchAll(ctx, resources, noopProgress{})
+	for i, res := range resources {
+		if err := <-done[i]; err != nil {
+			result.list.Errors = append(result.list.Errors, toResourceErrors(res.resourceType, err)...)
+		}
+	}
+	return result
+}
+
+// printContextTables prints one table per resource type holding the items of
+// every context, each row prefixed with its cluster. The errors of each
+// context are printed below the table they belong to.
+func (o *Options) printContextTables(out io.Writer, results []contextResult) error {
+	for _, result := range results {
+		if result.err != nil {
+			fmt.Fprintf(o.ErrOut, "error: context %q: %v\n", result.cluster.Context, result.err)
+		}
+	}
+
+	merged := &ResourceList{clusters: map[string][]clusterRef{}}
+	appendItems := func(resourceType string, count int, cluster clusterRef) {
+		for i := 0; i < count; i++ {
+			merged.clusters[resourceType] = append(merged.clusters[resourceType], cluster)
+		}
+	}
+	for _, result := range results {
+		if result.err != nil {
+			continue
+		}
+		items := result.list.Items
+		merged.Items.Cluster = append(merged.Items.Cluster, items.Cluster...)
+		appendItems("cluster", len(items.Cluster), result.cluster)
+		merged.Items.AccessEntries = append(merged.Items.AccessEntries, items.AccessEntries...)
+		appendItems("access-entries", len(items.AccessEntries), result.cluster)
+		merged.Items.Addons = append(merged.Items.Addons, items.Addons...)
+		// Unknown versions keep the upgrades aligned with the addons
+		upgrades := items.addonUpgrades
+		if len(upgrades) != len(items.Addons) {
+			upgrades = make([]AddonUpgrade, len(items.Addons))
+		}
+		merged.Items.addonUpgrades = append(merged.Items.addonUpgrades, upgrades...)
+		appendItems("addons", len(items.Addons), result.cluster)
+		merged.Items.Nodegroups = append(merged.Items.Nodegroups, items.Nodegroups...)
+		appendItems("nodegroups", len(items.Nodegroups), result.cluster)
+		merged.Items.FargateProfiles = append(merged.Items.FargateProfiles, items.FargateProfiles...)
+		appendItems("fargate-profiles", len(items.FargateProfiles), result.cluster)
+		merged.Items.PodIdentityAssociations = append(merged.Items.PodIdentityAssociations, items.PodIdentityAssociations...)
+		appendItems("pod-identity-associations", len(items.PodIdentityAssociations), result.cluster)
+		merged.Items.Insights = append(merged.Items.Insights, items.Insights...)
+		appendItems("insights", len(items.Insights), result.cluster)
+		merged.Items.Updates = append(merged.Items.Updates, items.Updates...)
+		appendItems("updates", len(items.Updates), result.cluster)
+	}
+
+	// There is no client of all contexts: the access entries printer lists
+	// the policies of each entry with the client of its cluster
+	tableOptions := printers.PrintOptions{
+		Wide:       *o.printFlags.OutputFormat == "wide",
+		ShowLabels: o.showTags,
+	}
+	resources, err := o.selectFetchers(o.resourceFetchers(merged, tableOptions))
+	if err != nil {
+		return err
+	}
+
+	for i, res := range resources {
+		if err := res.printer(nil, out); err != nil {
+			if !isPartialError(err) {
+				return err
+			}
+			printResourceErrors(out, toResourceErrors(res.resourceType, err))
+		}
+		printContextErrors(out, results, res.resourceType)
+		if i < len(resources)-1 {
+			fmt.Fprintln(out)
+		}
+	}
+	return nil
+}
+
+// printContextErrors prints the errors recorded for resourceType, or for every
+// resource type when it is empty, naming the context they occurred in.
+func printContextErrors(w io.Writer, results []contextResult, resourceType string) {
+	for _, result := range results {
+		if result.err != nil && resourceType == "" {
+			fmt.Fprintf(w, "error: context %q: %v\n", result.cluster.Context, result.err)
+		}
+		for _, e := range result.list.Errors {
+			if resourceType == "" || e.ResourceType == resourceType {
+				fmt.Fprintf(w, "error: context %q: %s\n", result.cluster.Context, e)
+			}
+		}
+	}
+}
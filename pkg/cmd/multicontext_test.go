@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// newMultiContextConfig has two EKS contexts, one that cannot be reached and
+// one that is not an EKS cluster.
+func newMultiContextConfig() api.Config {
+	config := api.Config{
+		Clusters: map[string]*api.Cluster{
+			"kind-dev": {Server: "https://127.0.0.1:6443"},
+		},
+		Contexts: map[string]*api.Context{
+			"kind-dev": {Cluster: "kind-dev"},
+		},
+	}
+	for _, name := range []string{"staging", "production", "broken"} {
+		clusterARN := fmt.Sprintf("arn:aws:eks:eu-west-1:123456789012:cluster/%s", name)
+		config.Clusters[clusterARN] = &api.Cluster{Server: "https://ABCDEF.gr7.eu-west-1.eks.amazonaws.com"}
+		config.Contexts[name] = &api.Context{Cluster: clusterARN}
+	}
+	return config
+}
+
+// connectMultiContext returns a client whose cluster has a nodegroup and an
+// access entry policy named after the context.
+func connectMultiContext(contextName string) (*EKSClient, error) {
+	if contextName == "broken" {
+		return nil, errors.New("failed to create AWS client: no credentials")
+	}
+
+	mockClient := newMockEKSClient()
+	mockClient.describeClusterFunc = func(ctx context.Context, params *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error) {
+		return &eks.DescribeClusterOutput{
+			Cluster: &types.Cluster{
+				Name:            params.Name,
+				Arn:             stringPtr("arn:aws:eks:eu-west-1:123456789012:cluster/" + *params.Name),
+				Version:         stringPtr("1.31"),
+				Status:          types.ClusterStatusActive,
+				PlatformVersion: stringPtr("eks.1"),
+			},
+		}, nil
+	}
+	mockClient.listNodegroupsFunc = func(ctx context.Context, params *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error) {
+		return &eks.ListNodegroupsOutput{Nodegroups: []string{contextName + "-ng"}}, nil
+	}
+	mockClient.describeNodegroupFunc = func(ctx context.Context, params *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error) {
+		return &eks.DescribeNodegroupOutput{
+			Nodegroup: &types.Nodegroup{
+				NodegroupName: params.NodegroupName,
+				Status:        types.NodegroupStatusActive,
+				InstanceTypes: []string{"m6i.large"},
+				ScalingConfig: &types.NodegroupScalingConfig{DesiredSize: int32Ptr(2), MinSize: int32Ptr(1), MaxSize: int32Ptr(3)},
+				Version:       stringPtr("1.31"),
+			},
+		}, nil
+	}
+	mockClient.listAccessEntriesFunc = func(ctx context.Context, params *eks.ListAccessEntriesInput) (*eks.ListAccessEntriesOutput, error) {
+		return &eks.ListAccessEntriesOutput{AccessEntries: []string{"arn:aws:iam::123456789012:role/admins"}}, nil
+	}
+	mockClient.describeAccessEntryFunc = func(ctx context.Context, params *eks.DescribeAccessEntryInput) (*eks.DescribeAccessEntryOutput, error) {
+		return &eks.DescribeAccessEntryOutput{AccessEntry: &types.AccessEntry{PrincipalArn: params.PrincipalArn}}, nil
+	}
+	mockClient.listAssociatedAccessPoliciesFunc = func(ctx context.Context, params *eks.ListAssociatedAccessPoliciesInput) (*eks.ListAssociatedAccessPoliciesOutput, error) {
+		return &eks.ListAssociatedAccessPoliciesOutput{AssociatedAccessPolicies: []types.AssociatedAccessPolicy{{
+			PolicyArn: stringPtr("arn:aws:eks::aws:cluster-access-policy/" + contextName + "-policy"),
+		}}}, nil
+	}
+	return &EKSClient{
+		client:      mockClient,
+		clusterName: stringPtr(contextName + "-cluster"),
+		pool:        newWorkerPool(2),
+	}, nil
+}
+
+func TestSelectContexts(t *testing.T) {
+	tests := []struct {
+		name        string
+		contexts    []string
+		all         bool
+		regex       string
+		expected    []string
+		expectedErr string
+	}{
+		{
+			name:     "all contexts",
+			all:      true,
+			expected: []string{"broken", "kind-dev", "production", "staging"},
+		},
+		{
+			name:     "repeated context",
+			contexts: []string{"staging", "production", "staging"},
+			expected: []string{"staging", "production"},
+		},
+		{
+			name:     "context and regex",
+			contexts: []string{"staging"},
+			regex:    "^(prod|stag)",
+			expected: []string{"staging", "production"},
+		},
+		{
+			name:        "unknown context",
+			contexts:    []string{"staging", "qa"},
+			expectedErr: `context "qa" not found in kubeconfig`,
+		},
+		{
+			name:        "no match",
+			regex:       "^qa-",
+			expectedErr: `no kubeconfig context matches --context-regex "^qa-"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, _, _ := newTestOptions(newMockEKSClient(), "")
+			o.rawConfig = newMultiContextConfig()
+			o.contexts, o.allContexts, o.contextRegex = tt.contexts, tt.all, tt.regex
+
+			contexts, err := o.selectContexts()
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Fatalf("expected error %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("selectContexts returned error: %v", err)
+			}
+			if !reflect.DeepEqual(contexts, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, contexts)
+			}
+		})
+	}
+}
+
+func TestRunContextsTable(t *testing.T) {
+	o, out, errOut := newTestOptions(newMockEKSClient(), "wide")
+	o.rawConfig = newMultiContextConfig()
+	o.resourceType = "nodegroups"
+
+	err := o.runContexts(context.Background(), out, []string{"broken", "kind-dev", "production", "staging"}, connectMultiContext)
+	if err != nil {
+		t.Fatalf("runContexts returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and two rows, got:\n%s", out.String())
+	}
+	for i, expected := range [][]string{
+		{"CLUSTER", "REGION", "ACCOUNT", "NAME"},
+		{"production-cluster", "eu-west-1", "123456789012", "production-ng"},
+		{"staging-cluster", "eu-west-1", "123456789012", "staging-ng"},
+	} {
+		if fields := strings.Fields(lines[i+1]); !reflect.DeepEqual(fields[:4], expected) {
+			t.Errorf("line %d: expected %v, got %v", i+1, expected, fields[:4])
+		}
+	}
+
+	for _, expected := range []string{
+		`Skipping context "kind-dev": not an EKS cluster`,
+		`error: context "broken": failed to create AWS client: no credentials`,
+	} {
+		if !strings.Contains(errOut.String(), expected) {
+			t.Errorf("stderr does not contain %q\nGot: %s", expected, errOut.String())
+		}
+	}
+}
+
+func TestRunContextsAccessEntries(t *testing.T) {
+	o, out, _ := newTestOptions(newMockEKSClient(), "")
+	o.rawConfig = newMultiContextConfig()
+	o.resourceType = "access-entries"
+
+	if err := o.runContexts(context.Background(), out, []string{"production", "staging"}, connectMultiContext); err != nil {
+		t.Fatalf("runContexts returned error: %v", err)
+	}
+
+	// The policies of each entry are listed with the client of its cluster
+	rows := map[string]string{}
+	for _, line := range strings.Split(out.String(), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			rows[fields[0]] = line
+		}
+	}
+	for _, name := range []string{"production", "staging"} {
+		if !strings.Contains(rows[name+"-cluster"], "cluster-access-policy/"+name+"-policy") {
+			t.Errorf("expected the %s policy on the %s row, got:\n%s", name, name, out.String())
+		}
+	}
+}
+
+func TestRunContextsJSON(t *testing.T) {
+	o, out, _ := newTestOptions(newMockEKSClient(), "json")
+	o.rawConfig = newMultiContextConfig()
+	o.resourceType = "nodegroups"
+
+	if err := o.runContexts(context.Background(), out, []string{"broken", "kind-dev", "production", "staging"}, connectMultiContext); err != nil {
+		t.Fatalf("runContexts returned error: %v", err)
+	}
+
+	list := &MultiClusterResourceList{}
+	if err := json.Unmarshal(out.Bytes(), list); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if list.Kind != "EksMultiClusterResourceList" || list.Skipped["kind-dev"] == "" {
+		t.Errorf("unexpected list: %+v", list)
+	}
+	staging := list.Clusters["staging"]
+	if staging.Cluster != "staging-cluster" || staging.Region != "eu-west-1" || len(staging.Items.Nodegroups) != 1 {
+		t.Errorf("unexpected staging entry: %+v", staging)
+	}
+	if broken := list.Clusters["broken"]; broken.Error == "" || broken.Items != nil {
+		t.Errorf("expected the broken context to report its error, got %+v", broken)
+	}
+}
+
+func TestRunContextsFailOnError(t *testing.T) {
+	o, out, _ := newTestOptions(newMockEKSClient(), "")
+	o.rawConfig = newMultiContextConfig()
+	o.failOnError = true
+
+	err := o.runContexts(context.Background(), out, []string{"broken", "staging"}, connectMultiContext)
+	if err == nil || !strings.Contains(err.Error(), "failed to fetch 1 EKS resource(s)") {
+		t.Errorf("expected the unreachable context to fail the command, got %v", err)
+	}
+}
+
+func TestRepeatedContextFlag(t *testing.T) {
+	cmd := NewCmd(genericclioptions.IOStreams{Out: &bytes.Buffer{}, ErrOut: &bytes.Buffer{}})
+	if err := cmd.ParseFlags([]string{"--context", "staging", "--context", "production"}); err != nil {
+		t.Fatalf("ParseFlags returned error: %v", err)
+	}
+
+	o := cmd.PersistentFlags().Lookup("context").Value.(*contextsValue).o
+	if !reflect.DeepEqual(o.contexts, []string{"staging", "production"}) || !o.isMultiContext() {
+		t.Errorf("unexpected contexts %v", o.contexts)
+	}
+	if err := o.Complete(); err == nil || !strings.Contains(err.Error(), "several contexts") {
+		t.Errorf("expected Complete to reject several contexts, got %v", err)
+	}
+}
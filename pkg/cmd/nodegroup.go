@@ -4,11 +4,15 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/eks/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/printers"
@@ -19,6 +23,10 @@ type Nodegroup = types.Nodegroup
 type NodeGroupList struct {
 	metav1.TypeMeta
 	Items []types.Nodegroup
+	// nodes are the Kubernetes Nodes of each nodegroup with --nodes
+	nodes map[string]NodegroupNodes
+	// clusters are the clusters of the items when viewing several contexts
+	clusters []clusterRef
 }
 
 // Implement runtime.Object interface
@@ -30,10 +38,12 @@ func (n *NodeGroupList) DeepCopyObject() runtime.Object {
 	return &NodeGroupList{
 		TypeMeta: n.TypeMeta,
 		Items:    append([]types.Nodegroup(nil), n.Items...),
+		nodes:    maps.Clone(n.nodes),
+		clusters: append([]clusterRef(nil), n.clusters...),
 	}
 }
 
-func NewNodeGroupPrinter() printers.ResourcePrinter {
+func NewNodeGroupPrinter(options printers.PrintOptions) printers.ResourcePrinter {
 	return printers.ResourcePrinterFunc(func(obj runtime.Object, w io.Writer) error {
 		list, ok := obj.(*NodeGroupList)
 		if !ok {
@@ -55,6 +65,13 @@ func NewNodeGroupPrinter() printers.ResourcePrinter {
 				{Name: "VERSION", Type: "string"},
 				{Name: "AMI TYPE", Type: "string"},
 				{Name: "CAPACITY TYPE", Type: "string"},
+				{Name: "SUBNETS", Type: "string", Priority: 1},
+				{Name: "DISK SIZE", Type: "string", Priority: 1},
+				{Name: "LAUNCH TEMPLATE", Type: "string", Priority: 1},
+				{Name: "LABELS", Type: "string", Priority: 1},
+				{Name: "TAINTS", Type: "string", Priority: 1},
+				{Name: "NODE ROLE", Type: "string", Priority: 1},
+				{Name: "CREATED AT", Type: "string", Priority: 1},
 			},
 		}
 
@@ -70,36 +87,196 @@ func NewNodeGroupPrinter() printers.ResourcePrinter {
 					*item.Version,
 					string(item.AmiType),
 					string(item.CapacityType),
+					joinOrNone(item.Subnets),
+					int32OrNone(item.DiskSize),
+					formatLaunchTemplate(item.LaunchTemplate),
+					joinOrNone(sortedKeyValues(item.Labels)),
+					joinOrNone(formatTaints(item.Taints)),
+					stringOrNone(item.NodeRole),
+					tableTime(item.CreatedAt),
 				},
 			})
 		}
 
-		return printTable(w, table, "nodegroups")
+		addNodeColumns(table, list)
+		addClusterColumns(table, list.clusters)
+		addTagsColumn(table, options, func(i int) map[string]string { return list.Items[i].Tags })
+		return printTable(w, table, "nodegroups", options)
 	})
 }
 
-func (c *EKSClient) ListNodeGroups(ctx context.Context) ([]types.Nodegroup, error) {
-	input := &eks.ListNodegroupsInput{
-		ClusterName: c.clusterName,
+// addNodeColumns inserts the Kubernetes Nodes of each nodegroup after the
+// CAPACITY TYPE column when they were listed with --nodes.
+func addNodeColumns(table *metav1.Table, list *NodeGroupList) {
+	if list.nodes == nil {
+		return
 	}
 
-	result, err := c.client.ListNodegroups(ctx, input)
+	position := slices.IndexFunc(table.ColumnDefinitions, func(column metav1.TableColumnDefinition) bool {
+		return column.Name == "CAPACITY TYPE"
+	}) + 1
+	table.ColumnDefinitions = slices.Insert(table.ColumnDefinitions, position,
+		metav1.TableColumnDefinition{Name: "READY", Type: "integer"},
+		metav1.TableColumnDefinition{Name: "NOT-READY", Type: "integer"},
+		metav1.TableColumnDefinition{Name: "TOTAL", Type: "integer"},
+		metav1.TableColumnDefinition{Name: "NODE ISSUES", Type: "string"},
+	)
+	for i, item := range list.Items {
+		nodes := list.nodes[aws.ToString(item.NodegroupName)]
+		table.Rows[i].Cells = slices.Insert(table.Rows[i].Cells, position,
+			interface{}(nodes.Ready), interface{}(nodes.NotReady), interface{}(nodes.Total), interface{}(nodeIssues(item, nodes)))
+	}
+}
+
+func (c *EKSClient) ListNodeGroups(ctx context.Context) ([]types.Nodegroup, error) {
+	ngNames, err := c.listNodegroupNames(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	var nodeGroups []types.Nodegroup
-	for _, ngName := range result.Nodegroups {
-		ngOutput, err := c.client.DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{
-			ClusterName:   c.clusterName,
-			NodegroupName: &ngName,
-		})
+	ngNames = filterNames(c.nameFilter, ngNames, stringNames)
+	return describeAll(ctx, c.pool, ngNames, stringName, c.DescribeNodeGroup)
+}
+
+// listNodegroupNames returns the names of all nodegroups of the cluster.
+func (c *EKSClient) listNodegroupNames(ctx context.Context) ([]string, error) {
+	input := &eks.ListNodegroupsInput{
+		ClusterName: c.clusterName,
+	}
+
+	var ngNames []string
+	paginator := eks.NewListNodegroupsPaginator(c.client, input)
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
 		if err != nil {
 			return nil, err
 		}
+		ngNames = append(ngNames, result.Nodegroups...)
+	}
+	return ngNames, nil
+}
+
+func (c *EKSClient) DescribeNodeGroup(ctx context.Context, ngName string) (types.Nodegroup, error) {
+	ngOutput, err := c.client.DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{
+		ClusterName:   c.clusterName,
+		NodegroupName: &ngName,
+	})
+	if err != nil {
+		return types.Nodegroup{}, err
+	}
+
+	return *ngOutput.Nodegroup, nil
+}
 
-		nodeGroups = append(nodeGroups, *ngOutput.Nodegroup)
+func describeNodegroup(w *prefixWriter, item types.Nodegroup) {
+	w.Write(levelZero, "Name:\t%s\n", stringOrNone(item.NodegroupName))
+	w.Write(levelZero, "Cluster:\t%s\n", stringOrNone(item.ClusterName))
+	w.Write(levelZero, "ARN:\t%s\n", stringOrNone(item.NodegroupArn))
+	w.Write(levelZero, "Status:\t%s\n", item.Status)
+	w.Write(levelZero, "Version:\t%s\n", stringOrNone(item.Version))
+	w.Write(levelZero, "Release Version:\t%s\n", stringOrNone(item.ReleaseVersion))
+	w.Write(levelZero, "Capacity Type:\t%s\n", item.CapacityType)
+	w.Write(levelZero, "AMI Type:\t%s\n", item.AmiType)
+	w.Write(levelZero, "Instance Types:\t%s\n", joinOrNone(item.InstanceTypes))
+	w.Write(levelZero, "Disk Size:\t%s\n", int32OrNone(item.DiskSize))
+	w.Write(levelZero, "Node Role:\t%s\n", stringOrNone(item.NodeRole))
+	w.Write(levelZero, "Created At:\t%s\n", timeOrNone(item.CreatedAt))
+	w.Write(levelZero, "Modified At:\t%s\n", timeOrNone(item.ModifiedAt))
+
+	w.Write(levelZero, "Scaling:\n")
+	if item.ScalingConfig != nil {
+		w.Write(levelOne, "Desired Size:\t%s\n", int32OrNone(item.ScalingConfig.DesiredSize))
+		w.Write(levelOne, "Min Size:\t%s\n", int32OrNone(item.ScalingConfig.MinSize))
+		w.Write(levelOne, "Max Size:\t%s\n", int32OrNone(item.ScalingConfig.MaxSize))
 	}
 
-	return nodeGroups, nil
+	w.Write(levelZero, "Update Config:\n")
+	if item.UpdateConfig != nil {
+		w.Write(levelOne, "Max Unavailable:\t%s\n", int32OrNone(item.UpdateConfig.MaxUnavailable))
+		w.Write(levelOne, "Max Unavailable Percentage:\t%s\n", int32OrNone(item.UpdateConfig.MaxUnavailablePercentage))
+		if item.UpdateConfig.UpdateStrategy != "" {
+			w.Write(levelOne, "Update Strategy:\t%s\n", item.UpdateConfig.UpdateStrategy)
+		}
+	}
+
+	if item.LaunchTemplate == nil {
+		w.Write(levelZero, "Launch Template:\t<none>\n")
+	} else {
+		w.Write(levelZero, "Launch Template:\n")
+		w.Write(levelOne, "Name:\t%s\n", stringOrNone(item.LaunchTemplate.Name))
+		w.Write(levelOne, "ID:\t%s\n", stringOrNone(item.LaunchTemplate.Id))
+		w.Write(levelOne, "Version:\t%s\n", stringOrNone(item.LaunchTemplate.Version))
+	}
+
+	if item.RemoteAccess != nil {
+		w.Write(levelZero, "Remote Access:\n")
+		w.Write(levelOne, "EC2 SSH Key:\t%s\n", stringOrNone(item.RemoteAccess.Ec2SshKey))
+		w.Write(levelOne, "Source Security Groups:\t%s\n", joinOrNone(item.RemoteAccess.SourceSecurityGroups))
+	}
+
+	w.WriteList(levelZero, "Subnets", item.Subnets)
+	if item.Resources != nil {
+		var asgNames []string
+		for _, asg := range item.Resources.AutoScalingGroups {
+			asgNames = append(asgNames, stringOrNone(asg.Name))
+		}
+		w.WriteList(levelZero, "Auto Scaling Groups", asgNames)
+	}
+	w.WriteMap(levelZero, "Labels", item.Labels)
+	w.WriteList(levelZero, "Taints", formatTaints(item.Taints))
+
+	var issues []healthIssue
+	if item.Health != nil {
+		for _, issue := range item.Health.Issues {
+			issues = append(issues, healthIssue{code: string(issue.Code), message: issue.Message, resourceIDs: issue.ResourceIds})
+		}
+	}
+	w.WriteHealthIssues(levelZero, issues)
+	w.WriteMap(levelZero, "Tags", item.Tags)
+}
+
+// formatTaints renders taints as key=value:EFFECT like kubectl.
+func formatTaints(taints []types.Taint) []string {
+	var formatted []string
+	for _, taint := range taints {
+		keyValue := stringOrNone(taint.Key)
+		if taint.Value != nil && *taint.Value != "" {
+			keyValue = fmt.Sprintf("%s=%s", keyValue, *taint.Value)
+		}
+		formatted = append(formatted, fmt.Sprintf("%s:%s", keyValue, taint.Effect))
+	}
+	return formatted
+}
+
+// formatLaunchTemplate renders a launch template as name:version, falling back
+// to its ID when it has no name.
+func formatLaunchTemplate(lt *types.LaunchTemplateSpecification) string {
+	if lt == nil {
+		return "<none>"
+	}
+	name := stringOrNone(lt.Name)
+	if lt.Name == nil && lt.Id != nil {
+		name = *lt.Id
+	}
+	if lt.Version == nil {
+		return name
+	}
+	return fmt.Sprintf("%s:%s", name, *lt.Version)
+}
+
+// nodegroupFields are the fields matched by --field-selector.
+func nodegroupFields(item types.Nodegroup) []fields.Set {
+	return []fields.Set{{
+		"name":           aws.ToString(item.NodegroupName),
+		"status":         string(item.Status),
+		"version":        aws.ToString(item.Version),
+		"releaseVersion": aws.ToString(item.ReleaseVersion),
+		"capacityType":   string(item.CapacityType),
+		"amiType":        string(item.AmiType),
+	}}
+}
+
+// nodegroupTags are the tags matched by --selector.
+func nodegroupTags(item types.Nodegroup) map[string]string {
+	return item.Tags
 }
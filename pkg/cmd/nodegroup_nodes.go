@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// nodegroupLabel is the label EKS sets on the Nodes of a managed nodegroup.
+const nodegroupLabel = "eks.amazonaws.com/nodegroup"
+
+// NodegroupNodes are the Kubernetes Nodes registered by a nodegroup.
+type NodegroupNodes struct {
+	Ready    int `json:"ready"`
+	NotReady int `json:"notReady"`
+	Total    int `json:"total"`
+	// Cordoned are the names of the unschedulable Nodes
+	Cordoned []string `json:"cordoned,omitempty"`
+	// KubeletVersionMismatches are the names of the Nodes whose kubelet minor
+	// version differs from the nodegroup version
+	KubeletVersionMismatches []string `json:"kubeletVersionMismatches,omitempty"`
+}
+
+// listNodegroupNodes groups the Nodes of the cluster by nodegroup. Every
+// nodegroup is in the result, with no Nodes when none registered.
+func (o *Options) listNodegroupNodes(ctx context.Context, nodegroups []Nodegroup) (map[string]NodegroupNodes, error) {
+	nodes, err := listAllPages(ctx, metav1.ListOptions{LabelSelector: nodegroupLabel}, func(ctx context.Context, options metav1.ListOptions) ([]corev1.Node, string, error) {
+		list, err := o.kubeClient.CoreV1().Nodes().List(ctx, options)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	versions := map[string]string{}
+	result := map[string]NodegroupNodes{}
+	for _, ng := range nodegroups {
+		name := aws.ToString(ng.NodegroupName)
+		versions[name] = aws.ToString(ng.Version)
+		result[name] = NodegroupNodes{}
+	}
+
+	for _, node := range nodes {
+		name := node.Labels[nodegroupLabel]
+		if _, ok := result[name]; !ok {
+			continue
+		}
+		counts := result[name]
+		counts.Total++
+		if isNodeReady(node) {
+			counts.Ready++
+		} else {
+			counts.NotReady++
+		}
+		if node.Spec.Unschedulable {
+			counts.Cordoned = append(counts.Cordoned, node.Name)
+		}
+		if !sameMinorVersion(node.Status.NodeInfo.KubeletVersion, versions[name]) {
+			counts.KubeletVersionMismatches = append(counts.KubeletVersionMismatches, node.Name)
+		}
+		result[name] = counts
+	}
+
+	for name, counts := range result {
+		slices.Sort(counts.Cordoned)
+		slices.Sort(counts.KubeletVersionMismatches)
+		result[name] = counts
+	}
+	return result, nil
+}
+
+func isNodeReady(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// sameMinorVersion reports whether a kubelet version such as
+// v1.30.4-eks-a737599 has the minor version of a nodegroup version such as
+// 1.30. Unknown versions are not reported as different.
+func sameMinorVersion(kubeletVersion, nodegroupVersion string) bool {
+	kubelet, err := version.ParseGeneric(kubeletVersion)
+	if err != nil {
+		return true
+	}
+	ng, err := version.ParseGeneric(nodegroupVersion)
+	if err != nil {
+		return true
+	}
+	return kubelet.Major() == ng.Major() && kubelet.Minor() == ng.Minor()
+}
+
+// nodeIssues summarizes how the Nodes of a nodegroup differ from what EKS
+// reports for it.
+func nodeIssues(ng Nodegroup, nodes NodegroupNodes) string {
+	var issues []string
+	if ng.ScalingConfig != nil && ng.ScalingConfig.DesiredSize != nil && int(*ng.ScalingConfig.DesiredSize) != nodes.Total {
+		issues = append(issues, fmt.Sprintf("%d/%d registered", nodes.Total, *ng.ScalingConfig.DesiredSize))
+	}
+	if len(nodes.Cordoned) > 0 {
+		issues = append(issues, fmt.Sprintf("%d cordoned", len(nodes.Cordoned)))
+	}
+	if len(nodes.KubeletVersionMismatches) > 0 {
+		issues = append(issues, fmt.Sprintf("%d kubelet version mismatch", len(nodes.KubeletVersionMismatches)))
+	}
+	if len(issues) == 0 {
+		return "<none>"
+	}
+	return strings.Join(issues, ", ")
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newNode(name, nodegroup, kubeletVersion string, ready, unschedulable bool) *corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{nodegroupLabel: nodegroup}},
+		Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+			NodeInfo:   corev1.NodeSystemInfo{KubeletVersion: kubeletVersion},
+		},
+	}
+}
+
+// newNodegroupNodesMock has two 1.31 nodegroups wanting 3 and 2 Nodes.
+func newNodegroupNodesMock() *mockEKSClient {
+	desired := map[string]int32{"ng-a": 3, "ng-b": 2}
+	mockClient := newMockEKSClient()
+	mockClient.listNodegroupsFunc = func(ctx context.Context, params *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error) {
+		return &eks.ListNodegroupsOutput{Nodegroups: []string{"ng-a", "ng-b"}}, nil
+	}
+	mockClient.describeNodegroupFunc = func(ctx context.Context, params *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error) {
+		return &eks.DescribeNodegroupOutput{
+			Nodegroup: &types.Nodegroup{
+				NodegroupName: params.NodegroupName,
+				Version:       stringPtr("1.31"),
+				Status:        types.NodegroupStatusActive,
+				ScalingConfig: &types.NodegroupScalingConfig{
+					DesiredSize: int32Ptr(desired[*params.NodegroupName]),
+					MinSize:     int32Ptr(1),
+					MaxSize:     int32Ptr(5),
+				},
+			},
+		}, nil
+	}
+	return mockClient
+}
+
+func TestListNodegroupNodes(t *testing.T) {
+	o, _, _ := newTestOptions(newNodegroupNodesMock(), "")
+	o.kubeClient = fake.NewSimpleClientset(
+		newNode("node-3", "ng-a", "v1.31.2-eks-94953ac", true, true),
+		newNode("node-1", "ng-a", "v1.31.2-eks-94953ac", true, false),
+		newNode("node-2", "ng-a", "v1.30.4-eks-a737599", false, false),
+		newNode("node-4", "ng-deleted", "v1.31.2-eks-94953ac", true, false),
+	)
+
+	nodegroups, err := o.eksClient.ListNodeGroups(context.Background())
+	if err != nil {
+		t.Fatalf("ListNodeGroups returned error: %v", err)
+	}
+	nodes, err := o.listNodegroupNodes(context.Background(), nodegroups)
+	if err != nil {
+		t.Fatalf("listNodegroupNodes returned error: %v", err)
+	}
+
+	a := nodes["ng-a"]
+	if a.Ready != 2 || a.NotReady != 1 || a.Total != 3 {
+		t.Errorf("unexpected ng-a counts: %+v", a)
+	}
+	if strings.Join(a.Cordoned, ",") != "node-3" || strings.Join(a.KubeletVersionMismatches, ",") != "node-2" {
+		t.Errorf("unexpected ng-a issues: %+v", a)
+	}
+	if b, ok := nodes["ng-b"]; !ok || b.Total != 0 {
+		t.Errorf("expected ng-b without Nodes, got %+v", b)
+	}
+	if _, ok := nodes["ng-deleted"]; ok {
+		t.Errorf("expected the Nodes of unknown nodegroups to be ignored, got %+v", nodes)
+	}
+
+	for _, ng := range nodegroups {
+		expected := map[string]string{
+			"ng-a": "1 cordoned, 1 kubelet version mismatch",
+			"ng-b": "0/2 registered",
+		}[*ng.NodegroupName]
+		if got := nodeIssues(ng, nodes[*ng.NodegroupName]); got != expected {
+			t.Errorf("%s: expected issues %q, got %q", *ng.NodegroupName, expected, got)
+		}
+	}
+}
+
+func TestRunNodegroupsWithNodes(t *testing.T) {
+	o, out, _ := newTestOptions(newNodegroupNodesMock(), "")
+	o.resourceType, o.nodes = "nodegroups", true
+	o.kubeClient = fake.NewSimpleClientset(
+		newNode("node-1", "ng-a", "v1.31.2-eks-94953ac", true, false),
+		newNode("node-2", "ng-a", "v1.31.2-eks-94953ac", true, false),
+		newNode("node-3", "ng-a", "v1.31.2-eks-94953ac", true, false),
+	)
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if !strings.Contains(lines[1], "READY   NOT-READY   TOTAL   NODE ISSUES") {
+		t.Fatalf("expected the Node columns, got:\n%s", out.String())
+	}
+	expected := []string{"3 0 3 <none>", "0 0 0 0/2 registered"}
+	for i, row := range expected {
+		if !strings.Contains(strings.Join(strings.Fields(lines[i+2]), " "), row) {
+			t.Errorf("row %d: expected %q, got %q", i, row, lines[i+2])
+		}
+	}
+}
+
+func TestRunNodegroupsWithNodesError(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("list", "nodes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New(`nodes is forbidden: User "viewer" cannot list resource "nodes"`)
+	})
+	o, out, _ := newTestOptions(newNodegroupNodesMock(), "")
+	o.resourceType, o.nodes, o.kubeClient = "nodegroups", true, client
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	// The nodegroups are listed without the Node columns
+	if !strings.Contains(out.String(), "ng-a") || strings.Contains(out.String(), "NODE ISSUES") {
+		t.Errorf("expected the nodegroups without Nodes, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), `failed to describe "Kubernetes nodes": nodes is forbidden`) {
+		t.Errorf("expected the Kubernetes error to be reported, got:\n%s", out.String())
+	}
+}
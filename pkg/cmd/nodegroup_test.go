@@ -2,10 +2,14 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"k8s.io/cli-runtime/pkg/printers"
 )
 
 func TestNewNodeGroupPrinter(t *testing.T) {
@@ -97,7 +101,7 @@ func TestNewNodeGroupPrinter(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create printer
-			printer := NewNodeGroupPrinter()
+			printer := NewNodeGroupPrinter(printers.PrintOptions{})
 
 			// Create buffer to capture output
 			buf := &bytes.Buffer{}
@@ -127,3 +131,149 @@ func TestNewNodeGroupPrinter(t *testing.T) {
 func int32Ptr(i int32) *int32 {
 	return &i
 }
+
+func TestListNodeGroupsPagination(t *testing.T) {
+	pages := map[string]*eks.ListNodegroupsOutput{
+		"":       {Nodegroups: []string{"ng-1"}, NextToken: stringPtr("page-2")},
+		"page-2": {Nodegroups: []string{"ng-2"}, NextToken: stringPtr("page-3")},
+		"page-3": {Nodegroups: []string{"ng-3"}},
+	}
+
+	mockClient := &mockEKSClient{
+		listNodegroupsFunc: func(ctx context.Context, params *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error) {
+			token := ""
+			if params.NextToken != nil {
+				token = *params.NextToken
+			}
+			return pages[token], nil
+		},
+		describeNodegroupFunc: func(ctx context.Context, params *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error) {
+			return &eks.DescribeNodegroupOutput{
+				Nodegroup: &types.Nodegroup{NodegroupName: params.NodegroupName},
+			}, nil
+		},
+	}
+
+	client := &EKSClient{
+		client:      mockClient,
+		clusterName: stringPtr("test-cluster"),
+	}
+
+	nodeGroups, err := client.ListNodeGroups(context.Background())
+	if err != nil {
+		t.Fatalf("ListNodeGroups returned error: %v", err)
+	}
+
+	expected := []string{"ng-1", "ng-2", "ng-3"}
+	if len(nodeGroups) != len(expected) {
+		t.Fatalf("expected %d nodegroups, got %d", len(expected), len(nodeGroups))
+	}
+	for i, name := range expected {
+		if *nodeGroups[i].NodegroupName != name {
+			t.Errorf("nodegroup %d: expected %s, got %s", i, name, *nodeGroups[i].NodegroupName)
+		}
+	}
+}
+
+func TestNewNodeGroupPrinterWide(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	list := &NodeGroupList{
+		Items: []types.Nodegroup{
+			{
+				NodegroupName: stringPtr("gpu-ng"),
+				Status:        "ACTIVE",
+				InstanceTypes: []string{"g5.xlarge"},
+				ScalingConfig: &types.NodegroupScalingConfig{
+					DesiredSize: int32Ptr(1),
+					MinSize:     int32Ptr(0),
+					MaxSize:     int32Ptr(2),
+				},
+				Version:        stringPtr("1.31"),
+				AmiType:        "AL2_x86_64_GPU",
+				CapacityType:   "ON_DEMAND",
+				Subnets:        []string{"subnet-a", "subnet-b"},
+				LaunchTemplate: &types.LaunchTemplateSpecification{Name: stringPtr("gpu-lt"), Version: stringPtr("3")},
+				Labels:         map[string]string{"role": "gpu", "accelerator": "nvidia"},
+				Taints: []types.Taint{
+					{Key: stringPtr("nvidia.com/gpu"), Effect: types.TaintEffectNoSchedule},
+				},
+				NodeRole:  stringPtr("arn:aws:iam::123456789012:role/node"),
+				CreatedAt: &createdAt,
+			},
+		},
+	}
+	wideOutput := []string{
+		"SUBNETS",
+		"DISK SIZE",
+		"LAUNCH TEMPLATE",
+		"LABELS",
+		"TAINTS",
+		"NODE ROLE",
+		"CREATED AT",
+		"subnet-a,subnet-b",
+		"gpu-lt:3",
+		"accelerator=nvidia,role=gpu",
+		"nvidia.com/gpu:NO_SCHEDULE",
+		"arn:aws:iam::123456789012:role/node",
+		"2024-05-01T10:00:00Z",
+	}
+
+	for _, wide := range []bool{false, true} {
+		buf := &bytes.Buffer{}
+		if err := NewNodeGroupPrinter(printers.PrintOptions{Wide: wide}).PrintObj(list, buf); err != nil {
+			t.Fatalf("PrintObj returned error: %v", err)
+		}
+
+		output := buf.String()
+		for _, expected := range wideOutput {
+			if strings.Contains(output, expected) != wide {
+				t.Errorf("wide=%t: unexpected presence of %q\nGot: %s", wide, expected, output)
+			}
+		}
+	}
+}
+
+func TestNewNodeGroupPrinterShowTags(t *testing.T) {
+	list := &NodeGroupList{
+		Items: []types.Nodegroup{
+			{
+				NodegroupName: stringPtr("tagged"),
+				Status:        "ACTIVE",
+				ScalingConfig: &types.NodegroupScalingConfig{
+					DesiredSize: int32Ptr(1),
+					MinSize:     int32Ptr(1),
+					MaxSize:     int32Ptr(1),
+				},
+				Version: stringPtr("1.31"),
+				Tags:    map[string]string{"team": "payments", "cost-center": "1234"},
+			},
+			{
+				NodegroupName: stringPtr("untagged"),
+				Status:        "ACTIVE",
+				ScalingConfig: &types.NodegroupScalingConfig{
+					DesiredSize: int32Ptr(1),
+					MinSize:     int32Ptr(1),
+					MaxSize:     int32Ptr(1),
+				},
+				Version: stringPtr("1.31"),
+			},
+		},
+	}
+
+	for _, showTags := range []bool{false, true} {
+		buf := &bytes.Buffer{}
+		if err := NewNodeGroupPrinter(printers.PrintOptions{ShowLabels: showTags}).PrintObj(list, buf); err != nil {
+			t.Fatalf("PrintObj returned error: %v", err)
+		}
+
+		output := buf.String()
+		for _, expected := range []string{"TAGS", "cost-center=1234,team=payments"} {
+			if strings.Contains(output, expected) != showTags {
+				t.Errorf("showTags=%t: unexpected presence of %q\nGot: %s", showTags, expected, output)
+			}
+		}
+		if strings.Contains(output, "LABELS") {
+			t.Errorf("expected no LABELS column from the table printer\nGot: %s", output)
+		}
+	}
+}
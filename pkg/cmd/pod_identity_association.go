@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/eks/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/printers"
@@ -19,6 +21,8 @@ type PodIdentityAssociation = types.PodIdentityAssociation
 type PodIdentityAssociationList struct {
 	metav1.TypeMeta
 	Items []types.PodIdentityAssociation
+	// clusters are the clusters of the items when viewing several contexts
+	clusters []clusterRef
 }
 
 // Implement runtime.Object interface
@@ -30,10 +34,11 @@ func (p *PodIdentityAssociationList) DeepCopyObject() runtime.Object {
 	return &PodIdentityAssociationList{
 		TypeMeta: p.TypeMeta,
 		Items:    append([]types.PodIdentityAssociation(nil), p.Items...),
+		clusters: append([]clusterRef(nil), p.clusters...),
 	}
 }
 
-func NewPodIdentityAssociationPrinter() printers.ResourcePrinter {
+func NewPodIdentityAssociationPrinter(options printers.PrintOptions) printers.ResourcePrinter {
 	return printers.ResourcePrinterFunc(func(obj runtime.Object, w io.Writer) error {
 		list, ok := obj.(*PodIdentityAssociationList)
 		if !ok {
@@ -51,6 +56,8 @@ func NewPodIdentityAssociationPrinter() printers.ResourcePrinter {
 				{Name: "SERVICE ACCOUNT NAME", Type: "string"},
 				{Name: "IAM ROLE ARN", Type: "string"},
 				{Name: "OWNER ARN", Type: "string"},
+				{Name: "ASSOCIATION ID", Type: "string", Priority: 1},
+				{Name: "CREATED AT", Type: "string", Priority: 1},
 			},
 		}
 
@@ -67,11 +74,15 @@ func NewPodIdentityAssociationPrinter() printers.ResourcePrinter {
 					*item.ServiceAccount,
 					*item.RoleArn,
 					ownerArn,
+					stringOrNone(item.AssociationId),
+					tableTime(item.CreatedAt),
 				},
 			})
 		}
 
-		return printTable(w, table, "pod-identity-associations")
+		addClusterColumns(table, list.clusters)
+		addTagsColumn(table, options, func(i int) map[string]string { return list.Items[i].Tags })
+		return printTable(w, table, "pod-identity-associations", options)
 	})
 }
 
@@ -80,23 +91,69 @@ func (c *EKSClient) ListPodIdentityAssociations(ctx context.Context) ([]types.Po
 		ClusterName: c.clusterName,
 	}
 
-	result, err := c.client.ListPodIdentityAssociations(ctx, input)
-	if err != nil {
-		return nil, err
+	var summaries []types.PodIdentityAssociationSummary
+	paginator := eks.NewListPodIdentityAssociationsPaginator(c.client, input)
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, result.Associations...)
 	}
 
-	var associations []types.PodIdentityAssociation
-	for _, assoc := range result.Associations {
-		describeOut, err := c.client.DescribePodIdentityAssociation(ctx, &eks.DescribePodIdentityAssociationInput{
-			ClusterName:   c.clusterName,
-			AssociationId: assoc.AssociationId,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to describe pod identity association with associationID: %s", *assoc.AssociationId)
+	// Associations can be selected by ID or <namespace>/<service-account>
+	summaries = filterNames(c.nameFilter, summaries, func(assoc types.PodIdentityAssociationSummary) []string {
+		return []string{
+			aws.ToString(assoc.AssociationId),
+			aws.ToString(assoc.Namespace) + "/" + aws.ToString(assoc.ServiceAccount),
 		}
+	})
+
+	associationID := func(assoc types.PodIdentityAssociationSummary) string {
+		return *assoc.AssociationId
+	}
+
+	return describeAll(ctx, c.pool, summaries, associationID, func(ctx context.Context, assoc types.PodIdentityAssociationSummary) (types.PodIdentityAssociation, error) {
+		return c.DescribePodIdentityAssociation(ctx, *assoc.AssociationId)
+	})
+}
 
-		associations = append(associations, *describeOut.Association)
+func (c *EKSClient) DescribePodIdentityAssociation(ctx context.Context, associationID string) (types.PodIdentityAssociation, error) {
+	describeOut, err := c.client.DescribePodIdentityAssociation(ctx, &eks.DescribePodIdentityAssociationInput{
+		ClusterName:   c.clusterName,
+		AssociationId: &associationID,
+	})
+	if err != nil {
+		return types.PodIdentityAssociation{}, err
 	}
 
-	return associations, nil
+	return *describeOut.Association, nil
+}
+
+func describePodIdentityAssociation(w *prefixWriter, item types.PodIdentityAssociation) {
+	w.Write(levelZero, "Association ID:\t%s\n", stringOrNone(item.AssociationId))
+	w.Write(levelZero, "Cluster:\t%s\n", stringOrNone(item.ClusterName))
+	w.Write(levelZero, "ARN:\t%s\n", stringOrNone(item.AssociationArn))
+	w.Write(levelZero, "Namespace:\t%s\n", stringOrNone(item.Namespace))
+	w.Write(levelZero, "Service Account:\t%s\n", stringOrNone(item.ServiceAccount))
+	w.Write(levelZero, "IAM Role ARN:\t%s\n", stringOrNone(item.RoleArn))
+	w.Write(levelZero, "Owner ARN:\t%s\n", stringOrNone(item.OwnerArn))
+	w.Write(levelZero, "Created At:\t%s\n", timeOrNone(item.CreatedAt))
+	w.Write(levelZero, "Modified At:\t%s\n", timeOrNone(item.ModifiedAt))
+	w.WriteMap(levelZero, "Tags", item.Tags)
+}
+
+// podIdentityAssociationFields are the fields matched by --field-selector.
+func podIdentityAssociationFields(item types.PodIdentityAssociation) []fields.Set {
+	return []fields.Set{{
+		"namespace":      aws.ToString(item.Namespace),
+		"serviceAccount": aws.ToString(item.ServiceAccount),
+		"roleArn":        aws.ToString(item.RoleArn),
+		"ownerArn":       aws.ToString(item.OwnerArn),
+	}}
+}
+
+// podIdentityAssociationTags are the tags matched by --selector.
+func podIdentityAssociationTags(item types.PodIdentityAssociation) map[string]string {
+	return item.Tags
 }
@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"strings"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"k8s.io/cli-runtime/pkg/printers"
 )
 
 func TestNewPodIdentityAssociationPrinter(t *testing.T) {
@@ -66,7 +69,7 @@ func TestNewPodIdentityAssociationPrinter(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create printer
-			printer := NewPodIdentityAssociationPrinter()
+			printer := NewPodIdentityAssociationPrinter(printers.PrintOptions{})
 
 			// Create buffer to capture output
 			buf := &bytes.Buffer{}
@@ -92,3 +95,55 @@ func TestNewPodIdentityAssociationPrinter(t *testing.T) {
 		})
 	}
 }
+
+func TestListPodIdentityAssociationsPagination(t *testing.T) {
+	pages := map[string]*eks.ListPodIdentityAssociationsOutput{
+		"": {
+			Associations: []types.PodIdentityAssociationSummary{
+				{AssociationId: stringPtr("a-1")},
+				{AssociationId: stringPtr("a-2")},
+			},
+			NextToken: stringPtr("page-2"),
+		},
+		"page-2": {
+			Associations: []types.PodIdentityAssociationSummary{
+				{AssociationId: stringPtr("a-3")},
+			},
+		},
+	}
+
+	mockClient := &mockEKSClient{
+		listPodIdentityAssociationsFunc: func(ctx context.Context, params *eks.ListPodIdentityAssociationsInput) (*eks.ListPodIdentityAssociationsOutput, error) {
+			token := ""
+			if params.NextToken != nil {
+				token = *params.NextToken
+			}
+			return pages[token], nil
+		},
+		describePodIdentityAssociationFunc: func(ctx context.Context, params *eks.DescribePodIdentityAssociationInput) (*eks.DescribePodIdentityAssociationOutput, error) {
+			return &eks.DescribePodIdentityAssociationOutput{
+				Association: &types.PodIdentityAssociation{AssociationId: params.AssociationId},
+			}, nil
+		},
+	}
+
+	client := &EKSClient{
+		client:      mockClient,
+		clusterName: stringPtr("test-cluster"),
+	}
+
+	associations, err := client.ListPodIdentityAssociations(context.Background())
+	if err != nil {
+		t.Fatalf("ListPodIdentityAssociations returned error: %v", err)
+	}
+
+	expected := []string{"a-1", "a-2", "a-3"}
+	if len(associations) != len(expected) {
+		t.Fatalf("expected %d associations, got %d", len(expected), len(associations))
+	}
+	for i, id := range expected {
+		if *associations[i].AssociationId != id {
+			t.Errorf("association %d: expected %s, got %s", i, id, *associations[i].AssociationId)
+		}
+	}
+}
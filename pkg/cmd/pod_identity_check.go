@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// irsaRoleAnnotation is the ServiceAccount annotation of IAM roles for
+// service accounts (IRSA), which pod identity associations replace.
+const irsaRoleAnnotation = "eks.amazonaws.com/role-arn"
+
+// Statuses of a pod identity association in the cluster.
+const (
+	podIdentityOK           = "OK"
+	podIdentityMissingNS    = "MISSING-NS"
+	podIdentityMissingSA    = "MISSING-SA"
+	podIdentityIRSAConflict = "IRSA-CONFLICT"
+)
+
+// PodIdentityCheck is a pod identity association checked against the
+// ServiceAccounts of the cluster.
+type PodIdentityCheck struct {
+	AssociationId  string
+	Namespace      string
+	ServiceAccount string
+	RoleArn        string
+	Status         string
+	// IRSARoleArn is the IRSA role the ServiceAccount is also annotated with
+	IRSARoleArn string `json:",omitempty"`
+	// Pods is the number of pods using the ServiceAccount that have not
+	// terminated
+	Pods int
+}
+
+// IRSAServiceAccount is a ServiceAccount annotated with an IRSA role.
+type IRSAServiceAccount struct {
+	Namespace      string
+	ServiceAccount string
+	RoleArn        string
+	Pods           int
+}
+
+type PodIdentityCheckList struct {
+	metav1.TypeMeta
+	Errors []ResourceError    `json:"errors,omitempty"`
+	Items  []PodIdentityCheck `json:"items"`
+	// Unassociated are the ServiceAccounts with an IRSA role and no pod
+	// identity association, left out when some associations are missing.
+	Unassociated []IRSAServiceAccount `json:"unassociated,omitempty"`
+}
+
+// Implement runtime.Object interface
+func (p *PodIdentityCheckList) GetObjectKind() schema.ObjectKind {
+	return &p.TypeMeta
+}
+
+func (p *PodIdentityCheckList) DeepCopyObject() runtime.Object {
+	copied := *p
+	copied.Errors = append([]ResourceError(nil), p.Errors...)
+	copied.Items = append([]PodIdentityCheck(nil), p.Items...)
+	copied.Unassociated = append([]IRSAServiceAccount(nil), p.Unassociated...)
+	return &copied
+}
+
+// RunPodIdentityCheck prints whether the namespace and ServiceAccount of each
+// pod identity association exist, the pods using them, and the IRSA
+// ServiceAccounts without an association.
+func (o *Options) RunPodIdentityCheck(ctx context.Context) error {
+	list, err := fetchReport(ctx, o, "pod-identity-associations", o.fetchPodIdentityCheck)
+	if err != nil {
+		return err
+	}
+	return o.printReport(list, list.Errors, NewPodIdentityCheckPrinter)
+}
+
+func (o *Options) fetchPodIdentityCheck(ctx context.Context) (*PodIdentityCheckList, error) {
+	associations, listErr := o.eksClient.ListPodIdentityAssociations(ctx)
+	if listErr != nil && !isPartialError(listErr) {
+		return nil, fmt.Errorf("failed to list pod identity associations: %v", listErr)
+	}
+	associations = selectItems(o, "pod-identity-associations", associations, podIdentityAssociationTags, podIdentityAssociationFields)
+
+	namespaces, err := listAllPages(ctx, metav1.ListOptions{}, func(ctx context.Context, options metav1.ListOptions) ([]corev1.Namespace, string, error) {
+		list, err := o.kubeClient.CoreV1().Namespaces().List(ctx, options)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %v", err)
+	}
+	serviceAccounts, err := listAllPages(ctx, metav1.ListOptions{}, func(ctx context.Context, options metav1.ListOptions) ([]corev1.ServiceAccount, string, error) {
+		list, err := o.kubeClient.CoreV1().ServiceAccounts("").List(ctx, options)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service accounts: %v", err)
+	}
+	pods, err := listAllPages(ctx, metav1.ListOptions{}, func(ctx context.Context, options metav1.ListOptions) ([]corev1.Pod, string, error) {
+		list, err := o.kubeClient.CoreV1().Pods("").List(ctx, options)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+
+	list := checkPodIdentityAssociations(associations, namespaces, serviceAccounts, pods)
+	if len(o.names) > 0 || o.fieldSelector != "" || o.tagSelector != "" || listErr != nil {
+		// The ServiceAccounts of the associations that were not selected or
+		// described would be reported as unassociated
+		list.Unassociated = nil
+	}
+	if listErr != nil {
+		list.Errors = toResourceErrors("pod-identity-associations", listErr)
+	}
+	return list, nil
+}
+
+// checkPodIdentityAssociations checks associations against the namespaces,
+// ServiceAccounts and pods of the cluster.
+func checkPodIdentityAssociations(associations []PodIdentityAssociation, namespaces []corev1.Namespace, serviceAccounts []corev1.ServiceAccount, pods []corev1.Pod) *PodIdentityCheckList {
+	existingNamespaces := map[string]bool{}
+	for _, ns := range namespaces {
+		existingNamespaces[ns.Name] = true
+	}
+	accounts := map[string]corev1.ServiceAccount{}
+	for _, sa := range serviceAccounts {
+		accounts[sa.Namespace+"/"+sa.Name] = sa
+	}
+	podCounts := map[string]int{}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		name := pod.Spec.ServiceAccountName
+		if name == "" {
+			name = "default"
+		}
+		podCounts[pod.Namespace+"/"+name]++
+	}
+
+	list := &PodIdentityCheckList{
+		TypeMeta:     metav1.TypeMeta{APIVersion: "v1", Kind: "EksPodIdentityCheckList"},
+		Items:        []PodIdentityCheck{},
+		Unassociated: []IRSAServiceAccount{},
+	}
+	associated := map[string]bool{}
+	for _, assoc := range associations {
+		key := aws.ToString(assoc.Namespace) + "/" + aws.ToString(assoc.ServiceAccount)
+		associated[key] = true
+
+		check := PodIdentityCheck{
+			AssociationId:  aws.ToString(assoc.AssociationId),
+			Namespace:      aws.ToString(assoc.Namespace),
+			ServiceAccount: aws.ToString(assoc.ServiceAccount),
+			RoleArn:        aws.ToString(assoc.RoleArn),
+			Status:         podIdentityOK,
+			Pods:           podCounts[key],
+		}
+		sa, ok := accounts[key]
+		switch {
+		case !existingNamespaces[check.Namespace]:
+			check.Status = podIdentityMissingNS
+		case !ok:
+			check.Status = podIdentityMissingSA
+		case sa.Annotations[irsaRoleAnnotation] != "":
+			// Pods of the ServiceAccount get the credentials of either role
+			// depending on the credential provider chain of their AWS SDK
+			check.Status = podIdentityIRSAConflict
+			check.IRSARoleArn = sa.Annotations[irsaRoleAnnotation]
+		}
+		list.Items = append(list.Items, check)
+	}
+
+	for key, sa := range accounts {
+		role := sa.Annotations[irsaRoleAnnotation]
+		if role == "" || associated[key] {
+			continue
+		}
+		list.Unassociated = append(list.Unassociated, IRSAServiceAccount{
+			Namespace:      sa.Namespace,
+			ServiceAccount: sa.Name,
+			RoleArn:        role,
+			Pods:           podCounts[key],
+		})
+	}
+	sort.Slice(list.Unassociated, func(i, j int) bool {
+		a, b := list.Unassociated[i], list.Unassociated[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.ServiceAccount < b.ServiceAccount
+	})
+	return list
+}
+
+func NewPodIdentityCheckPrinter(options printers.PrintOptions) printers.ResourcePrinter {
+	return printers.ResourcePrinterFunc(func(obj runtime.Object, w io.Writer) error {
+		list, ok := obj.(*PodIdentityCheckList)
+		if !ok {
+			return fmt.Errorf("expected *PodIdentityCheckList, got %T", obj)
+		}
+
+		table := &metav1.Table{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "PodIdentityCheck",
+			},
+			ColumnDefinitions: []metav1.TableColumnDefinition{
+				{Name: "NAMESPACE", Type: "string"},
+				{Name: "SERVICE ACCOUNT NAME", Type: "string"},
+				{Name: "STATUS", Type: "string"},
+				{Name: "PODS", Type: "integer"},
+				{Name: "IAM ROLE ARN", Type: "string"},
+				{Name: "IRSA ROLE ARN", Type: "string"},
+				{Name: "ASSOCIATION ID", Type: "string", Priority: 1},
+			},
+		}
+		for _, item := range list.Items {
+			table.Rows = append(table.Rows, metav1.TableRow{
+				Cells: []interface{}{
+					item.Namespace,
+					item.ServiceAccount,
+					item.Status,
+					item.Pods,
+					item.RoleArn,
+					stringOrNone(&item.IRSARoleArn),
+					item.AssociationId,
+				},
+			})
+		}
+		if err := printTable(w, table, "pod identity associations check", options); err != nil {
+			return err
+		}
+
+		unassociated := &metav1.Table{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "IRSAServiceAccount",
+			},
+			ColumnDefinitions: []metav1.TableColumnDefinition{
+				{Name: "NAMESPACE", Type: "string"},
+				{Name: "SERVICE ACCOUNT NAME", Type: "string"},
+				{Name: "PODS", Type: "integer"},
+				{Name: "IRSA ROLE ARN", Type: "string"},
+			},
+		}
+		for _, item := range list.Unassociated {
+			unassociated.Rows = append(unassociated.Rows, metav1.TableRow{
+				Cells: []interface{}{item.Namespace, item.ServiceAccount, item.Pods, item.RoleArn},
+			})
+		}
+		if list.Unassociated == nil {
+			return nil
+		}
+		fmt.Fprintln(w)
+		return printTable(w, unassociated, "IRSA service accounts without pod identity association", options)
+	})
+}
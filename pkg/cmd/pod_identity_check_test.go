@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newPodIdentityCheckMock has one association for each status.
+func newPodIdentityCheckMock() *mockEKSClient {
+	associations := map[string][2]string{
+		"a-ok":       {"apps", "api"},
+		"a-irsa":     {"apps", "worker"},
+		"a-no-sa":    {"apps", "deleted"},
+		"a-no-ns":    {"retired", "api"},
+		"a-filtered": {"batch", "job"},
+	}
+	mockClient := newMockEKSClient()
+	mockClient.listPodIdentityAssociationsFunc = func(ctx context.Context, params *eks.ListPodIdentityAssociationsInput) (*eks.ListPodIdentityAssociationsOutput, error) {
+		var summaries []types.PodIdentityAssociationSummary
+		for id, target := range associations {
+			summaries = append(summaries, types.PodIdentityAssociationSummary{AssociationId: stringPtr(id), Namespace: stringPtr(target[0]), ServiceAccount: stringPtr(target[1])})
+		}
+		return &eks.ListPodIdentityAssociationsOutput{Associations: summaries}, nil
+	}
+	mockClient.describePodIdentityAssociationFunc = func(ctx context.Context, params *eks.DescribePodIdentityAssociationInput) (*eks.DescribePodIdentityAssociationOutput, error) {
+		target := associations[*params.AssociationId]
+		return &eks.DescribePodIdentityAssociationOutput{
+			Association: &types.PodIdentityAssociation{
+				AssociationId:  params.AssociationId,
+				AssociationArn: stringPtr("arn:aws:eks:us-west-2:123456789012:podidentityassociation/test-cluster/" + *params.AssociationId),
+				Namespace:      stringPtr(target[0]),
+				ServiceAccount: stringPtr(target[1]),
+				RoleArn:        stringPtr("arn:aws:iam::123456789012:role/" + target[1]),
+			},
+		}, nil
+	}
+	return mockClient
+}
+
+func newServiceAccount(namespace, name, irsaRole string) *corev1.ServiceAccount {
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	if irsaRole != "" {
+		sa.Annotations = map[string]string{irsaRoleAnnotation: irsaRole}
+	}
+	return sa
+}
+
+func newPod(namespace, name, serviceAccount string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       corev1.PodSpec{ServiceAccountName: serviceAccount},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
+
+func newPodIdentityCheckClientset() *fake.Clientset {
+	return fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "apps"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "batch"}},
+		newServiceAccount("apps", "api", ""),
+		newServiceAccount("apps", "worker", "arn:aws:iam::123456789012:role/legacy-worker"),
+		newServiceAccount("apps", "legacy", "arn:aws:iam::123456789012:role/legacy"),
+		newServiceAccount("batch", "job", ""),
+		newPod("apps", "api-1", "api", corev1.PodRunning),
+		newPod("apps", "api-2", "api", corev1.PodPending),
+		newPod("apps", "api-3", "api", corev1.PodSucceeded),
+		newPod("apps", "legacy-1", "legacy", corev1.PodRunning),
+	)
+}
+
+func TestRunPodIdentityCheck(t *testing.T) {
+	o, out, _ := newTestOptions(newPodIdentityCheckMock(), "json")
+	o.resourceType, o.checkPodIdentity = "pod-identity-associations", true
+	o.kubeClient = newPodIdentityCheckClientset()
+
+	if err := o.RunPodIdentityCheck(context.Background()); err != nil {
+		t.Fatalf("RunPodIdentityCheck returned error: %v", err)
+	}
+
+	list := &PodIdentityCheckList{}
+	if err := json.Unmarshal(out.Bytes(), list); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+
+	expected := map[string]struct {
+		status string
+		pods   int
+	}{
+		"a-ok":       {podIdentityOK, 2},
+		"a-irsa":     {podIdentityIRSAConflict, 0},
+		"a-no-sa":    {podIdentityMissingSA, 0},
+		"a-no-ns":    {podIdentityMissingNS, 0},
+		"a-filtered": {podIdentityOK, 0},
+	}
+	if len(list.Items) != len(expected) {
+		t.Fatalf("expected %d checks, got %+v", len(expected), list.Items)
+	}
+	for _, item := range list.Items {
+		if want := expected[item.AssociationId]; item.Status != want.status || item.Pods != want.pods {
+			t.Errorf("%s: expected %s with %d pods, got %+v", item.AssociationId, want.status, want.pods, item)
+		}
+		if item.AssociationId == "a-irsa" && item.IRSARoleArn != "arn:aws:iam::123456789012:role/legacy-worker" {
+			t.Errorf("expected the IRSA role of the conflict, got %+v", item)
+		}
+	}
+
+	if len(list.Unassociated) != 1 || list.Unassociated[0].ServiceAccount != "legacy" || list.Unassociated[0].Pods != 1 {
+		t.Errorf("expected the legacy ServiceAccount without association, got %+v", list.Unassociated)
+	}
+}
+
+func TestRunPodIdentityCheckSelected(t *testing.T) {
+	o, out, _ := newTestOptions(newPodIdentityCheckMock(), "")
+	o.resourceType, o.checkPodIdentity, o.names = "pod-identity-associations", true, []string{"apps/*"}
+	o.eksClient.nameFilter = newNameMatcher(o.names)
+	o.kubeClient = newPodIdentityCheckClientset()
+
+	if err := o.RunPodIdentityCheck(context.Background()); err != nil {
+		t.Fatalf("RunPodIdentityCheck returned error: %v", err)
+	}
+
+	// batch/job has an association that was not selected
+	if strings.Contains(out.String(), "batch") || strings.Contains(out.String(), "without pod identity association") {
+		t.Errorf("expected only the selected associations, got:\n%s", out.String())
+	}
+}
+
+func TestRunPodIdentityCheckPartialError(t *testing.T) {
+	mockClient := newPodIdentityCheckMock()
+	describe := mockClient.describePodIdentityAssociationFunc
+	mockClient.describePodIdentityAssociationFunc = func(ctx context.Context, params *eks.DescribePodIdentityAssociationInput) (*eks.DescribePodIdentityAssociationOutput, error) {
+		if *params.AssociationId == "a-irsa" {
+			return nil, errors.New("throttled")
+		}
+		return describe(ctx, params)
+	}
+	o, out, _ := newTestOptions(mockClient, "json")
+	o.resourceType, o.checkPodIdentity = "pod-identity-associations", true
+	o.kubeClient = newPodIdentityCheckClientset()
+
+	if err := o.RunPodIdentityCheck(context.Background()); err != nil {
+		t.Fatalf("RunPodIdentityCheck returned error: %v", err)
+	}
+
+	list := &PodIdentityCheckList{}
+	if err := json.Unmarshal(out.Bytes(), list); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	// apps/worker has an association that could not be described
+	if len(list.Errors) != 1 || list.Unassociated != nil {
+		t.Errorf("expected the error and no unassociated ServiceAccounts, got errors %+v and %+v", list.Errors, list.Unassociated)
+	}
+}
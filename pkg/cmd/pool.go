@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"sync"
+)
+
+const defaultConcurrency = 10
+
+// workerPool bounds the number of EKS API calls in flight at once. A single
+// pool is shared by every resource type so that fetching several types in
+// parallel does not multiply the request rate. A nil pool does not limit
+// concurrency.
+type workerPool struct {
+	sem chan struct{}
+}
+
+func newWorkerPool(size int) *workerPool {
+	if size < 1 {
+		size = 1
+	}
+	return &workerPool{sem: make(chan struct{}, size)}
+}
+
+func (p *workerPool) acquire(ctx context.Context) error {
+	if p == nil {
+		return ctx.Err()
+	}
+	select {
+	case p.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *workerPool) release() {
+	if p == nil {
+		return
+	}
+	<-p.sem
+}
+
+// mapConcurrent calls fn for every item through the pool and returns the
+// results in input order. The first error cancels the remaining calls and is
+// returned.
+func mapConcurrent[In, Out any](ctx context.Context, p *workerPool, items []In, fn func(context.Context, In) (Out, error)) ([]Out, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	results := make([]Out, len(items))
+	for i, item := range items {
+		if err := p.acquire(ctx); err != nil {
+			fail(err)
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer p.release()
+
+			out, err := fn(ctx, item)
+			if err != nil {
+				fail(err)
+				return
+			}
+			results[i] = out
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
+
+// stringName names items that are already identified by a string.
+func stringName(s string) string {
+	return s
+}
+
+// describeAll is like mapConcurrent but does not stop at the first failing
+// item. Failed items are left out of the result and reported together in a
+// *PartialError, named by name.
+func describeAll[In, Out any](ctx context.Context, p *workerPool, items []In, name func(In) string, fn func(context.Context, In) (Out, error)) ([]Out, error) {
+	type result struct {
+		out Out
+		err error
+	}
+
+	results, err := mapConcurrent(ctx, p, items, func(ctx context.Context, item In) (result, error) {
+		out, err := fn(ctx, item)
+		return result{out: out, err: err}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var outs []Out
+	partial := &PartialError{}
+	for i, r := range results {
+		if r.err != nil {
+			partial.Errors = append(partial.Errors, ItemError{Name: name(items[i]), Err: r.err})
+			continue
+		}
+		outs = append(outs, r.out)
+	}
+
+	if len(partial.Errors) > 0 {
+		return outs, partial
+	}
+	return outs, nil
+}
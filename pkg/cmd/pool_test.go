@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMapConcurrent(t *testing.T) {
+	tests := []struct {
+		name        string
+		poolSize    int
+		items       []int
+		failOn      int
+		expectedErr bool
+	}{
+		{
+			name:     "preserves input order",
+			poolSize: 3,
+			items:    []int{5, 4, 3, 2, 1, 0},
+			failOn:   -1,
+		},
+		{
+			name:     "single worker",
+			poolSize: 1,
+			items:    []int{1, 2, 3},
+			failOn:   -1,
+		},
+		{
+			name:        "returns first error",
+			poolSize:    2,
+			items:       []int{1, 2, 3, 4},
+			failOn:      3,
+			expectedErr: true,
+		},
+		{
+			name:     "no items",
+			poolSize: 2,
+			items:    nil,
+			failOn:   -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inFlight, maxInFlight int32
+			results, err := mapConcurrent(context.Background(), newWorkerPool(tt.poolSize), tt.items, func(ctx context.Context, i int) (string, error) {
+				n := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+				for {
+					m := atomic.LoadInt32(&maxInFlight)
+					if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+						break
+					}
+				}
+
+				// Later items finish first to exercise ordering
+				time.Sleep(time.Duration(i) * time.Millisecond)
+				if i == tt.failOn {
+					return "", errors.New("describe failed")
+				}
+				return fmt.Sprintf("item-%d", i), nil
+			})
+
+			if tt.expectedErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("mapConcurrent returned error: %v", err)
+			}
+
+			if int(maxInFlight) > tt.poolSize {
+				t.Errorf("expected at most %d concurrent calls, got %d", tt.poolSize, maxInFlight)
+			}
+			if len(results) != len(tt.items) {
+				t.Fatalf("expected %d results, got %d", len(tt.items), len(results))
+			}
+			for i, item := range tt.items {
+				if expected := fmt.Sprintf("item-%d", item); results[i] != expected {
+					t.Errorf("result %d: expected %s, got %s", i, expected, results[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDescribeAll(t *testing.T) {
+	items := []string{"a", "b", "c", "d"}
+	results, err := describeAll(context.Background(), newWorkerPool(2), items, stringName, func(ctx context.Context, name string) (string, error) {
+		if name == "b" || name == "d" {
+			return "", errors.New("access denied")
+		}
+		return "described-" + name, nil
+	})
+
+	var partial *PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("expected *PartialError, got %v", err)
+	}
+	if len(partial.Errors) != 2 || partial.Errors[0].Name != "b" || partial.Errors[1].Name != "d" {
+		t.Errorf("expected errors for b and d, got %+v", partial.Errors)
+	}
+
+	expected := []string{"described-a", "described-c"}
+	if len(results) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(results))
+	}
+	for i := range expected {
+		if results[i] != expected[i] {
+			t.Errorf("result %d: expected %s, got %s", i, expected[i], results[i])
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// progressReporter shows which resource types are still being fetched. It
+// must never write to stdout so that printer output can be piped.
+type progressReporter interface {
+	// Start begins reporting progress for resourceTypes.
+	Start(resourceTypes []string)
+	// Done marks resourceType as fetched.
+	Done(resourceType string)
+	// Show draws the progress line, Clear removes it so that other output
+	// can be written to the same terminal.
+	Show()
+	Clear()
+}
+
+// newProgressReporter returns a reporter writing to w, or one that does
+// nothing when quiet is set or w is not a terminal.
+func newProgressReporter(w io.Writer, quiet bool) progressReporter {
+	if quiet || !isTerminal(w) {
+		return noopProgress{}
+	}
+	return &terminalProgress{w: w}
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+type noopProgress struct{}
+
+func (noopProgress) Start([]string) {}
+func (noopProgress) Done(string)    {}
+func (noopProgress) Show()          {}
+func (noopProgress) Clear()         {}
+
+// terminalProgress redraws a single line listing the pending resource types.
+type terminalProgress struct {
+	mu      sync.Mutex
+	w       io.Writer
+	pending []string
+	total   int
+	visible bool
+}
+
+func (p *terminalProgress) Start(resourceTypes []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pending = append([]string(nil), resourceTypes...)
+	p.total = len(resourceTypes)
+	p.visible = true
+	p.draw()
+}
+
+func (p *terminalProgress) Done(resourceType string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, pending := range p.pending {
+		if pending == resourceType {
+			p.pending = append(p.pending[:i], p.pending[i+1:]...)
+			break
+		}
+	}
+	if p.visible {
+		p.draw()
+	}
+}
+
+func (p *terminalProgress) Show() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.visible = true
+	p.draw()
+}
+
+func (p *terminalProgress) Clear() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.visible = false
+	fmt.Fprint(p.w, "\r\033[K")
+}
+
+func (p *terminalProgress) draw() {
+	if len(p.pending) == 0 {
+		fmt.Fprint(p.w, "\r\033[K")
+		return
+	}
+
+	names := make([]string, 0, len(p.pending))
+	for _, resourceType := range p.pending {
+		names = append(names, strings.ReplaceAll(resourceType, "-", " "))
+	}
+	fmt.Fprintf(p.w, "\r\033[K \033[36mFetching %s... (%d/%d)\033[m",
+		strings.Join(names, ", "), p.total-len(p.pending), p.total)
+}
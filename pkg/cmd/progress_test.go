@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTerminalProgress(t *testing.T) {
+	buf := &bytes.Buffer{}
+	p := &terminalProgress{w: buf}
+
+	p.Start([]string{"addons", "fargate-profiles", "insights"})
+	if !strings.Contains(buf.String(), "Fetching addons, fargate profiles, insights... (0/3)") {
+		t.Errorf("expected all resource types pending, got %q", buf.String())
+	}
+
+	buf.Reset()
+	p.Done("fargate-profiles")
+	if !strings.Contains(buf.String(), "Fetching addons, insights... (1/3)") {
+		t.Errorf("expected fargate profiles to be done, got %q", buf.String())
+	}
+
+	// Nothing is drawn while the line is cleared for other output
+	p.Clear()
+	buf.Reset()
+	p.Done("addons")
+	if buf.Len() != 0 {
+		t.Errorf("expected no output while cleared, got %q", buf.String())
+	}
+
+	p.Show()
+	if !strings.Contains(buf.String(), "Fetching insights... (2/3)") {
+		t.Errorf("expected insights pending, got %q", buf.String())
+	}
+}
+
+func TestNewProgressReporterNotTerminal(t *testing.T) {
+	if _, ok := newProgressReporter(&bytes.Buffer{}, false).(noopProgress); !ok {
+		t.Error("expected no progress when output is not a terminal")
+	}
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// Reports check a single live cluster against its Kubernetes API, e.g.
+// addons --upgrades or who-can. Snapshots do not record what they read.
+
+// validateLiveCluster checks that name, a report flag or command, is used
+// with a single live cluster.
+func (o *Options) validateLiveCluster(name string) error {
+	switch {
+	case o.fromFile != "":
+		return fmt.Errorf("%s cannot be used with --from-file", name)
+	case o.isMultiContext():
+		return fmt.Errorf("%s cannot be used with several contexts", name)
+	}
+	return nil
+}
+
+// validateReportFlags checks that the flags adding a report to a resource
+// type are used with that resource type.
+func (o *Options) validateReportFlags() error {
+	reports := []struct {
+		flag         string
+		enabled      bool
+		resourceType string
+		// columns reports add columns to the table of the resource type,
+		// which is also printed with every resource type and by --watch
+		columns bool
+	}{
+		{flag: "--upgrades", enabled: o.upgrades, resourceType: "addons"},
+		{flag: "--nodes", enabled: o.nodes, resourceType: "nodegroups", columns: true},
+		{flag: "--check", enabled: o.checkPodIdentity, resourceType: "pod-identity-associations"},
+		{flag: "--match-pods", enabled: o.matchPods, resourceType: "fargate-profiles"},
+	}
+	for _, report := range reports {
+		if !report.enabled {
+			continue
+		}
+		switch {
+		case o.resourceType != report.resourceType && !(report.columns && o.resourceType == ""):
+			return fmt.Errorf("%s is only supported for %s", report.flag, report.resourceType)
+		case o.watch && !report.columns:
+			return fmt.Errorf("%s cannot be used with --watch", report.flag)
+		}
+		if err := o.validateLiveCluster(report.flag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchReport fetches a report of resourceType, showing the progress on
+// stderr.
+func fetchReport[T runtime.Object](ctx context.Context, o *Options, resourceType string, fetch func(context.Context) (T, error)) (T, error) {
+	progress := newProgressReporter(o.ErrOut, o.quiet)
+	progress.Start([]string{resourceType})
+	report, err := fetch(ctx)
+	progress.Done(resourceType)
+	progress.Clear()
+	return report, err
+}
+
+// printReport prints report with the output format, or as the tables of
+// newPrinter, followed by the resources that could not be fetched.
+func (o *Options) printReport(report runtime.Object, resourceErrors []ResourceError, newPrinter func(printers.PrintOptions) printers.ResourcePrinter) error {
+	if !o.isTableFormat() {
+		printer, err := o.printFlags.ToPrinter()
+		if err != nil {
+			return err
+		}
+		if err := printer.PrintObj(report, o.Out); err != nil {
+			return err
+		}
+		printResourceErrors(o.ErrOut, resourceErrors)
+		return o.checkErrors(resourceErrors)
+	}
+
+	options := printers.PrintOptions{Wide: *o.printFlags.OutputFormat == "wide"}
+	if err := newPrinter(options).PrintObj(report, o.Out); err != nil {
+		return err
+	}
+	printResourceErrors(o.Out, resourceErrors)
+	return o.checkErrors(resourceErrors)
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidateReportFlags(t *testing.T) {
+	tests := []struct {
+		name         string
+		setFlag      func(o *Options)
+		resourceType string
+		watch        bool
+		fromFile     string
+		expectedErr  string
+	}{
+		{name: "upgrades", setFlag: func(o *Options) { o.upgrades = true }, resourceType: "addons"},
+		{name: "upgrades of other resource type", setFlag: func(o *Options) { o.upgrades = true }, resourceType: "nodegroups", expectedErr: "--upgrades is only supported for addons"},
+		{name: "upgrades with watch", setFlag: func(o *Options) { o.upgrades = true }, resourceType: "addons", watch: true, expectedErr: "--upgrades cannot be used with --watch"},
+		{name: "upgrades of snapshot", setFlag: func(o *Options) { o.upgrades = true }, resourceType: "addons", fromFile: "state.yaml", expectedErr: "--upgrades cannot be used with --from-file"},
+		{name: "nodes of all resources", setFlag: func(o *Options) { o.nodes = true }},
+		{name: "nodes with watch", setFlag: func(o *Options) { o.nodes = true }, resourceType: "nodegroups", watch: true},
+		{name: "nodes of other resource type", setFlag: func(o *Options) { o.nodes = true }, resourceType: "addons", expectedErr: "--nodes is only supported for nodegroups"},
+		{name: "nodes of snapshot", setFlag: func(o *Options) { o.nodes = true }, resourceType: "nodegroups", fromFile: "state.yaml", expectedErr: "--nodes cannot be used with --from-file"},
+		{name: "check", setFlag: func(o *Options) { o.checkPodIdentity = true }, resourceType: "pod-identity-associations"},
+		{name: "check of all resources", setFlag: func(o *Options) { o.checkPodIdentity = true }, expectedErr: "--check is only supported for pod-identity-associations"},
+		{name: "check with watch", setFlag: func(o *Options) { o.checkPodIdentity = true }, resourceType: "pod-identity-associations", watch: true, expectedErr: "--check cannot be used with --watch"},
+		{name: "match pods", setFlag: func(o *Options) { o.matchPods = true }, resourceType: "fargate-profiles"},
+		{name: "match pods of snapshot", setFlag: func(o *Options) { o.matchPods = true }, resourceType: "fargate-profiles", fromFile: "state.yaml", expectedErr: "--match-pods cannot be used with --from-file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, _, _ := newTestOptions(newMockEKSClient(), "")
+			tt.setFlag(o)
+			o.resourceType, o.watch, o.fromFile = tt.resourceType, tt.watch, tt.fromFile
+
+			err := o.validateReportFlags()
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Errorf("expected error containing %q, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestRunReportsTable(t *testing.T) {
+	tests := []struct {
+		name       string
+		mockClient *mockEKSClient
+		kubeClient *fake.Clientset
+		run        func(o *Options) error
+		expected   []string
+	}{
+		{
+			name:       "pod identity check",
+			mockClient: newPodIdentityCheckMock(),
+			kubeClient: newPodIdentityCheckClientset(),
+			run:        func(o *Options) error { return o.RunPodIdentityCheck(context.Background()) },
+			expected: []string{
+				"=== pod identity associations check ===",
+				"IRSA-CONFLICT",
+				"MISSING-NS",
+				"=== IRSA service accounts without pod identity association ===",
+				"arn:aws:iam::123456789012:role/legacy",
+			},
+		},
+		{
+			name:       "fargate pods",
+			mockClient: newFargatePodsMock(),
+			kubeClient: newFargatePodsClientset(),
+			run:        func(o *Options) error { return o.RunFargatePods(context.Background()) },
+			expected: []string{
+				"=== fargate profile pods ===",
+				"fargate-ip-10-0-1-10.ec2.internal",
+				"=== pending pods matching no fargate profile ===",
+				"job-3",
+			},
+		},
+		{
+			name:       "auth migration lockout",
+			mockClient: newAuthMigrationMock(types.AuthenticationModeApiAndConfigMap),
+			kubeClient: newAWSAuthClientset(),
+			run:        func(o *Options) error { return o.RunAuthMigration(context.Background()) },
+			expected: []string{
+				"=== auth migration: test-cluster (API_AND_CONFIG_MAP) ===",
+				"Switching to the API authentication mode would lock out 1 principal(s) only mapped by aws-auth",
+				"1 principal(s) have other Kubernetes groups in their access entry",
+			},
+		},
+		{
+			name:       "auth migration in config map mode",
+			mockClient: newAuthMigrationMock(types.AuthenticationModeConfigMap),
+			kubeClient: newAWSAuthClientset(),
+			run:        func(o *Options) error { return o.RunAuthMigration(context.Background()) },
+			expected: []string{
+				"would lock out 5 principal(s)",
+				"Access entries can only be created after switching to API_AND_CONFIG_MAP",
+			},
+		},
+		{
+			name:       "who can",
+			mockClient: newWhoCanMock(),
+			kubeClient: newWhoCanClientset(),
+			run:        func(o *Options) error { return o.RunWhoCan(context.Background(), "dev-1", "get", "secrets") },
+			expected: []string{
+				"=== who can get secrets in namespace dev-1 ===",
+				"PRINCIPAL ARN",
+				"developers-edit",
+				"The permissions of these access policies are not known: AmazonEKSAuditPolicy",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, out, _ := newTestOptions(tt.mockClient, "")
+			o.kubeClient = tt.kubeClient
+
+			if err := tt.run(o); err != nil {
+				t.Fatalf("run returned error: %v", err)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(out.String(), expected) {
+					t.Errorf("Output does not contain expected string: %s\nGot: %s", expected, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestPrintReportErrors(t *testing.T) {
+	list := &AddonUpgradeList{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "EksAddonUpgradeList"},
+		Errors:   []ResourceError{{ResourceType: "addons", Name: "vpc-cni", Message: "throttled"}},
+		Items:    []AddonUpgrade{},
+	}
+
+	for _, format := range []string{"", "json"} {
+		o, out, errOut := newTestOptions(newMockEKSClient(), format)
+		o.failOnError = true
+
+		if err := o.printReport(list, list.Errors, NewAddonUpgradePrinter); err == nil {
+			t.Errorf("format %q: expected an error with --fail-on-error", format)
+		}
+		// Errors are kept out of the structured output
+		errorsOut := out
+		if format != "" {
+			errorsOut = errOut
+		}
+		if !strings.Contains(errorsOut.String(), "throttled") {
+			t.Errorf("format %q: expected the error to be reported, got stdout:\n%s\nstderr:\n%s", format, out.String(), errOut.String())
+		}
+	}
+}
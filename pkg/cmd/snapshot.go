@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/printers"
+	"sigs.k8s.io/yaml"
+)
+
+const snapshotKind = "EksSnapshot"
+
+// accessPoliciesResourceType records the access entries whose associated
+// access policies could not be listed in Snapshot.Errors.
+const accessPoliciesResourceType = "access-policies"
+
+// Snapshot is the EKS state of a cluster captured by the snapshot command and
+// rendered again with --from-file.
+type Snapshot struct {
+	metav1.TypeMeta
+	Metadata SnapshotMetadata `json:"metadata"`
+	Errors   []ResourceError  `json:"errors,omitempty"`
+	Items    ResourceItems    `json:"items"`
+	// AccessPolicies are the access policies associated with each access
+	// entry, by principal ARN.
+	AccessPolicies map[string][]types.AssociatedAccessPolicy `json:"accessPolicies,omitempty"`
+}
+
+type SnapshotMetadata struct {
+	ClusterName string    `json:"clusterName"`
+	ClusterARN  string    `json:"clusterArn,omitempty"`
+	AccountID   string    `json:"accountId,omitempty"`
+	Region      string    `json:"region,omitempty"`
+	Context     string    `json:"context,omitempty"`
+	CapturedAt  time.Time `json:"capturedAt"`
+}
+
+func NewSnapshotCmd(o *Options) *cobra.Command {
+	var filename string
+
+	cmd := &cobra.Command{
+		Use:   "snapshot -f FILENAME",
+		Short: "Save the EKS state of a cluster to a file",
+		Long: `Save the EKS state of a cluster to a file.
+All resource types are captured together with the account, region, cluster
+ARN and capture time. The file is written as JSON if its name ends in .json
+and as YAML otherwise; use -f - to write YAML to stdout.
+
+Render a snapshot later, without AWS credentials, with --from-file.`,
+		Example: `  # Capture the current cluster
+  kubectl eks-viewer snapshot -f state.yaml
+
+  # Review it offline
+  kubectl eks-viewer --from-file state.yaml
+  kubectl eks-viewer --from-file state.yaml -o wide nodegroups
+  kubectl eks-viewer --from-file state.yaml describe addons vpc-cni`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if err := o.Complete(); err != nil {
+				return err
+			}
+			return o.RunSnapshot(context.Background(), filename)
+		},
+	}
+
+	cmd.Flags().StringVarP(&filename, "filename", "f", "", "File to write the snapshot to, or - for stdout")
+	_ = cmd.MarkFlagRequired("filename")
+
+	return cmd
+}
+
+// RunSnapshot captures every resource type and writes the snapshot to
+// filename. Resources that cannot be fetched are recorded as errors, like in
+// -o json output.
+func (o *Options) RunSnapshot(ctx context.Context, filename string) error {
+	snapshot, err := o.captureSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+	printResourceErrors(o.ErrOut, snapshot.Errors)
+
+	if filename == "-" {
+		return writeSnapshot(o.Out, snapshot, false)
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %v", err)
+	}
+	if err := writeSnapshot(f, snapshot, filepath.Ext(filename) == ".json"); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(o.ErrOut, "Saved snapshot of cluster %q to %s\n", snapshot.Metadata.ClusterName, filename)
+	return nil
+}
+
+func (o *Options) captureSnapshot(ctx context.Context) (*Snapshot, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	resourceList := &ResourceList{}
+	resources := o.resourceFetchers(resourceList, printers.PrintOptions{})
+	progress := newProgressReporter(o.ErrOut, o.quiet)
+	done := o.fetchAll(ctx, resources, progress)
+
+	snapshot := &Snapshot{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: snapshotKind},
+		Metadata: SnapshotMetadata{
+			ClusterName: *o.eksClient.clusterName,
+			Context:     o.kubeContextName,
+			CapturedAt:  time.Now().UTC(),
+		},
+	}
+	for i, res := range resources {
+		if err := <-done[i]; err != nil {
+			snapshot.Errors = append(snapshot.Errors, toResourceErrors(res.resourceType, err)...)
+		}
+	}
+	progress.Clear()
+	snapshot.Items = resourceList.Items
+
+	if len(snapshot.Items.Cluster) > 0 && snapshot.Items.Cluster[0].Arn != nil {
+		snapshot.Metadata.ClusterARN = *snapshot.Items.Cluster[0].Arn
+		if clusterARN, err := arn.Parse(snapshot.Metadata.ClusterARN); err == nil {
+			snapshot.Metadata.AccountID = clusterARN.AccountID
+			snapshot.Metadata.Region = clusterARN.Region
+		}
+	}
+
+	// The access entries table shows their associated policies
+	policies, err := o.eksClient.listAccessEntryPolicies(ctx, snapshot.Items.AccessEntries)
+	if err != nil && !isPartialError(err) {
+		return nil, err
+	}
+	if err != nil {
+		snapshot.Errors = append(snapshot.Errors, toResourceErrors(accessPoliciesResourceType, err)...)
+	}
+	if len(policies) > 0 {
+		snapshot.AccessPolicies = policies
+	}
+
+	return snapshot, nil
+}
+
+func writeSnapshot(w io.Writer, snapshot *Snapshot, asJSON bool) error {
+	var data []byte
+	var err error
+	if asJSON {
+		data, err = json.MarshalIndent(snapshot, "", "    ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(snapshot)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %v", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// readSnapshot reads a snapshot written as JSON or YAML.
+func readSnapshot(filename string) (*Snapshot, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %v", err)
+	}
+
+	snapshot := &Snapshot{}
+	if err := yaml.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %v", filename, err)
+	}
+	if snapshot.Kind != snapshotKind {
+		return nil, fmt.Errorf("%s is not an EKS snapshot, expected kind %s, got %q", filename, snapshotKind, snapshot.Kind)
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+)
+
+// snapshotClient serves the EKS API from a snapshot, so that a snapshot is
+// rendered by the same code as a live cluster. Resources that could not be
+// fetched when the snapshot was taken fail again with the recorded error.
+type snapshotClient struct {
+	snapshot *Snapshot
+}
+
+func newSnapshotClient(snapshot *Snapshot) *snapshotClient {
+	return &snapshotClient{snapshot: snapshot}
+}
+
+// listError returns the recorded error for the whole of resourceType.
+func (c *snapshotClient) listError(resourceType string) error {
+	for _, e := range c.snapshot.Errors {
+		if e.ResourceType == resourceType && e.Name == "" {
+			return errors.New(e.Message)
+		}
+	}
+	return nil
+}
+
+// itemErrors returns the recorded errors of the items of resourceType, by name.
+func (c *snapshotClient) itemErrors(resourceType string) map[string]error {
+	errs := map[string]error{}
+	for _, e := range c.snapshot.Errors {
+		if e.ResourceType == resourceType && e.Name != "" {
+			errs[e.Name] = errors.New(e.Message)
+		}
+	}
+	return errs
+}
+
+// names lists the items of resourceType, including those that failed to be
+// described so that they are reported again.
+func (c *snapshotClient) names(resourceType string, names []string) []string {
+	for name := range c.itemErrors(resourceType) {
+		names = append(names, name)
+	}
+	return names
+}
+
+// find returns the item of resourceType named name.
+func find[T any](c *snapshotClient, resourceType string, items []T, nameOf func(T) *string, name *string) (*T, error) {
+	if err := c.itemErrors(resourceType)[aws.ToString(name)]; err != nil {
+		return nil, err
+	}
+	for i := range items {
+		if aws.ToString(nameOf(items[i])) == aws.ToString(name) {
+			return &items[i], nil
+		}
+	}
+	return nil, &types.ResourceNotFoundException{
+		Message: aws.String(fmt.Sprintf("%s %q not found in snapshot", resourceType, aws.ToString(name))),
+	}
+}
+
+func (c *snapshotClient) ListAssociatedAccessPolicies(ctx context.Context, params *eks.ListAssociatedAccessPoliciesInput, optFns ...func(*eks.Options)) (*eks.ListAssociatedAccessPoliciesOutput, error) {
+	if err := c.itemErrors(accessPoliciesResourceType)[aws.ToString(params.PrincipalArn)]; err != nil {
+		return nil, err
+	}
+	return &eks.ListAssociatedAccessPoliciesOutput{
+		AssociatedAccessPolicies: c.snapshot.AccessPolicies[aws.ToString(params.PrincipalArn)],
+		ClusterName:              params.ClusterName,
+		PrincipalArn:             params.PrincipalArn,
+	}, nil
+}
+
+// ListAccessPolicies fails as snapshots only record the associated policies.
+func (c *snapshotClient) ListAccessPolicies(ctx context.Context, params *eks.ListAccessPoliciesInput, optFns ...func(*eks.Options)) (*eks.ListAccessPoliciesOutput, error) {
+	return nil, errors.New("access policies are not recorded in snapshots")
+}
+
+func (c *snapshotClient) ListAccessEntries(ctx context.Context, params *eks.ListAccessEntriesInput, optFns ...func(*eks.Options)) (*eks.ListAccessEntriesOutput, error) {
+	if err := c.listError("access-entries"); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, item := range c.snapshot.Items.AccessEntries {
+		names = append(names, aws.ToString(item.PrincipalArn))
+	}
+	return &eks.ListAccessEntriesOutput{AccessEntries: c.names("access-entries", names)}, nil
+}
+
+func (c *snapshotClient) DescribeAccessEntry(ctx context.Context, params *eks.DescribeAccessEntryInput, optFns ...func(*eks.Options)) (*eks.DescribeAccessEntryOutput, error) {
+	item, err := find(c, "access-entries", c.snapshot.Items.AccessEntries, func(item AccessEntry) *string { return item.PrincipalArn }, params.PrincipalArn)
+	if err != nil {
+		return nil, err
+	}
+	return &eks.DescribeAccessEntryOutput{AccessEntry: item}, nil
+}
+
+func (c *snapshotClient) ListAddons(ctx context.Context, params *eks.ListAddonsInput, optFns ...func(*eks.Options)) (*eks.ListAddonsOutput, error) {
+	if err := c.listError("addons"); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, item := range c.snapshot.Items.Addons {
+		names = append(names, aws.ToString(item.AddonName))
+	}
+	return &eks.ListAddonsOutput{Addons: c.names("addons", names)}, nil
+}
+
+func (c *snapshotClient) DescribeAddon(ctx context.Context, params *eks.DescribeAddonInput, optFns ...func(*eks.Options)) (*eks.DescribeAddonOutput, error) {
+	item, err := find(c, "addons", c.snapshot.Items.Addons, func(item Addon) *string { return item.AddonName }, params.AddonName)
+	if err != nil {
+		return nil, err
+	}
+	return &eks.DescribeAddonOutput{Addon: item}, nil
+}
+
+// DescribeAddonVersions fails as snapshots only record the installed addons.
+func (c *snapshotClient) DescribeAddonVersions(ctx context.Context, params *eks.DescribeAddonVersionsInput, optFns ...func(*eks.Options)) (*eks.DescribeAddonVersionsOutput, error) {
+	return nil, errors.New("addon versions are not recorded in snapshots")
+}
+
+func (c *snapshotClient) DescribeCluster(ctx context.Context, params *eks.DescribeClusterInput, optFns ...func(*eks.Options)) (*eks.DescribeClusterOutput, error) {
+	if err := c.listError("cluster"); err != nil {
+		return nil, err
+	}
+	item, err := find(c, "cluster", c.snapshot.Items.Cluster, func(item Cluster) *string { return item.Name }, params.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &eks.DescribeClusterOutput{Cluster: item}, nil
+}
+
+func (c *snapshotClient) ListClusters(ctx context.Context, params *eks.ListClustersInput, optFns ...func(*eks.Options)) (*eks.ListClustersOutput, error) {
+	var names []string
+	for _, item := range c.snapshot.Items.Cluster {
+		names = append(names, aws.ToString(item.Name))
+	}
+	return &eks.ListClustersOutput{Clusters: names}, nil
+}
+
+func (c *snapshotClient) ListFargateProfiles(ctx context.Context, params *eks.ListFargateProfilesInput, optFns ...func(*eks.Options)) (*eks.ListFargateProfilesOutput, error) {
+	if err := c.listError("fargate-profiles"); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, item := range c.snapshot.Items.FargateProfiles {
+		names = append(names, aws.ToString(item.FargateProfileName))
+	}
+	return &eks.ListFargateProfilesOutput{FargateProfileNames: c.names("fargate-profiles", names)}, nil
+}
+
+func (c *snapshotClient) DescribeFargateProfile(ctx context.Context, params *eks.DescribeFargateProfileInput, optFns ...func(*eks.Options)) (*eks.DescribeFargateProfileOutput, error) {
+	item, err := find(c, "fargate-profiles", c.snapshot.Items.FargateProfiles, func(item FargateProfile) *string { return item.FargateProfileName }, params.FargateProfileName)
+	if err != nil {
+		return nil, err
+	}
+	return &eks.DescribeFargateProfileOutput{FargateProfile: item}, nil
+}
+
+func (c *snapshotClient) ListInsights(ctx context.Context, params *eks.ListInsightsInput, optFns ...func(*eks.Options)) (*eks.ListInsightsOutput, error) {
+	if err := c.listError("insights"); err != nil {
+		return nil, err
+	}
+	var summaries []types.InsightSummary
+	for _, item := range c.snapshot.Items.Insights {
+		if !matchesInsightsFilter(item, params.Filter) {
+			continue
+		}
+		summaries = append(summaries, types.InsightSummary{
+			Id:                 item.Id,
+			Name:               item.Name,
+			Category:           item.Category,
+			KubernetesVersion:  item.KubernetesVersion,
+			Description:        item.Description,
+			InsightStatus:      item.InsightStatus,
+			LastRefreshTime:    item.LastRefreshTime,
+			LastTransitionTime: item.LastTransitionTime,
+		})
+	}
+	for id := range c.itemErrors("insights") {
+		summaries = append(summaries, types.InsightSummary{Id: aws.String(id)})
+	}
+	return &eks.ListInsightsOutput{Insights: summaries}, nil
+}
+
+// matchesInsightsFilter reports whether item matches the ListInsights filter,
+// which may be nil.
+func matchesInsightsFilter(item Insight, filter *types.InsightsFilter) bool {
+	if filter == nil {
+		return true
+	}
+	if len(filter.Categories) > 0 && !slices.Contains(filter.Categories, item.Category) {
+		return false
+	}
+	if len(filter.KubernetesVersions) > 0 && !slices.Contains(filter.KubernetesVersions, aws.ToString(item.KubernetesVersion)) {
+		return false
+	}
+	if len(filter.Statuses) > 0 && (item.InsightStatus == nil || !slices.Contains(filter.Statuses, item.InsightStatus.Status)) {
+		return false
+	}
+	return true
+}
+
+func (c *snapshotClient) DescribeInsight(ctx context.Context, params *eks.DescribeInsightInput, optFns ...func(*eks.Options)) (*eks.DescribeInsightOutput, error) {
+	item, err := find(c, "insights", c.snapshot.Items.Insights, func(item Insight) *string { return item.Id }, params.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &eks.DescribeInsightOutput{Insight: item}, nil
+}
+
+func (c *snapshotClient) ListNodegroups(ctx context.Context, params *eks.ListNodegroupsInput, optFns ...func(*eks.Options)) (*eks.ListNodegroupsOutput, error) {
+	if err := c.listError("nodegroups"); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, item := range c.snapshot.Items.Nodegroups {
+		names = append(names, aws.ToString(item.NodegroupName))
+	}
+	return &eks.ListNodegroupsOutput{Nodegroups: c.names("nodegroups", names)}, nil
+}
+
+func (c *snapshotClient) DescribeNodegroup(ctx context.Context, params *eks.DescribeNodegroupInput, optFns ...func(*eks.Options)) (*eks.DescribeNodegroupOutput, error) {
+	item, err := find(c, "nodegroups", c.snapshot.Items.Nodegroups, func(item Nodegroup) *string { return item.NodegroupName }, params.NodegroupName)
+	if err != nil {
+		return nil, err
+	}
+	return &eks.DescribeNodegroupOutput{Nodegroup: item}, nil
+}
+
+func (c *snapshotClient) ListPodIdentityAssociations(ctx context.Context, params *eks.ListPodIdentityAssociationsInput, optFns ...func(*eks.Options)) (*eks.ListPodIdentityAssociationsOutput, error) {
+	if err := c.listError("pod-identity-associations"); err != nil {
+		return nil, err
+	}
+	var summaries []types.PodIdentityAssociationSummary
+	for _, item := range c.snapshot.Items.PodIdentityAssociations {
+		summaries = append(summaries, types.PodIdentityAssociationSummary{
+			AssociationArn: item.AssociationArn,
+			AssociationId:  item.AssociationId,
+			ClusterName:    item.ClusterName,
+			Namespace:      item.Namespace,
+			OwnerArn:       item.OwnerArn,
+			ServiceAccount: item.ServiceAccount,
+		})
+	}
+	for id := range c.itemErrors("pod-identity-associations") {
+		summaries = append(summaries, types.PodIdentityAssociationSummary{AssociationId: aws.String(id)})
+	}
+	return &eks.ListPodIdentityAssociationsOutput{Associations: summaries}, nil
+}
+
+func (c *snapshotClient) DescribePodIdentityAssociation(ctx context.Context, params *eks.DescribePodIdentityAssociationInput, optFns ...func(*eks.Options)) (*eks.DescribePodIdentityAssociationOutput, error) {
+	item, err := find(c, "pod-identity-associations", c.snapshot.Items.PodIdentityAssociations, func(item PodIdentityAssociation) *string { return item.AssociationId }, params.AssociationId)
+	if err != nil {
+		return nil, err
+	}
+	return &eks.DescribePodIdentityAssociationOutput{Association: item}, nil
+}
+
+func (c *snapshotClient) ListUpdates(ctx context.Context, params *eks.ListUpdatesInput, optFns ...func(*eks.Options)) (*eks.ListUpdatesOutput, error) {
+	if err := c.listError("updates"); err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, item := range c.snapshot.Items.Updates {
+		if aws.ToString(item.NodegroupName) == aws.ToString(params.NodegroupName) && aws.ToString(item.AddonName) == aws.ToString(params.AddonName) {
+			ids = append(ids, aws.ToString(item.Id))
+		}
+	}
+	// The resource of updates that failed to be described is not recorded,
+	// so they are reported with the updates of the cluster
+	if params.NodegroupName == nil && params.AddonName == nil {
+		ids = c.names("updates", ids)
+	}
+	return &eks.ListUpdatesOutput{UpdateIds: ids}, nil
+}
+
+func (c *snapshotClient) DescribeUpdate(ctx context.Context, params *eks.DescribeUpdateInput, optFns ...func(*eks.Options)) (*eks.DescribeUpdateOutput, error) {
+	item, err := find(c, "updates", c.snapshot.Items.Updates, func(item Update) *string { return item.Id }, params.UpdateId)
+	if err != nil {
+		return nil, err
+	}
+	return &eks.DescribeUpdateOutput{Update: &item.Update}, nil
+}
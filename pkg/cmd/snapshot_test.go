@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+)
+
+// newSnapshotMock extends newPartiallyFailingMock with an access entry whose
+// policies are listed and one whose policies cannot be.
+func newSnapshotMock() *mockEKSClient {
+	mockClient := newPartiallyFailingMock()
+	mockClient.describeClusterFunc = func(ctx context.Context, params *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error) {
+		return &eks.DescribeClusterOutput{
+			Cluster: &types.Cluster{
+				Name:            params.Name,
+				Arn:             stringPtr("arn:aws:eks:eu-west-1:123456789012:cluster/test-cluster"),
+				Version:         stringPtr("1.31"),
+				PlatformVersion: stringPtr("eks.1"),
+				Status:          types.ClusterStatusActive,
+				CreatedAt:       &time.Time{},
+			},
+		}, nil
+	}
+	mockClient.listAccessEntriesFunc = func(ctx context.Context, params *eks.ListAccessEntriesInput) (*eks.ListAccessEntriesOutput, error) {
+		return &eks.ListAccessEntriesOutput{AccessEntries: []string{"arn:aws:iam::123456789012:role/admin", "arn:aws:iam::123456789012:role/denied"}}, nil
+	}
+	mockClient.describeAccessEntryFunc = func(ctx context.Context, params *eks.DescribeAccessEntryInput) (*eks.DescribeAccessEntryOutput, error) {
+		return &eks.DescribeAccessEntryOutput{
+			AccessEntry: &types.AccessEntry{PrincipalArn: params.PrincipalArn, KubernetesGroups: []string{"admins"}},
+		}, nil
+	}
+	mockClient.listAssociatedAccessPoliciesFunc = func(ctx context.Context, params *eks.ListAssociatedAccessPoliciesInput) (*eks.ListAssociatedAccessPoliciesOutput, error) {
+		if strings.HasSuffix(*params.PrincipalArn, "denied") {
+			return nil, errors.New("AccessDeniedException: not authorized to perform eks:ListAssociatedAccessPolicies")
+		}
+		return &eks.ListAssociatedAccessPoliciesOutput{
+			AssociatedAccessPolicies: []types.AssociatedAccessPolicy{
+				{PolicyArn: stringPtr("arn:aws:eks::aws:cluster-access-policy/AmazonEKSClusterAdminPolicy")},
+			},
+		}, nil
+	}
+	return mockClient
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	for _, filename := range []string{"state.yaml", "state.json"} {
+		t.Run(filename, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), filename)
+
+			live, liveOut, _ := newTestOptions(newSnapshotMock(), "")
+			if err := live.RunSnapshot(context.Background(), path); err != nil {
+				t.Fatalf("RunSnapshot returned error: %v", err)
+			}
+
+			snapshot, err := readSnapshot(path)
+			if err != nil {
+				t.Fatalf("readSnapshot returned error: %v", err)
+			}
+			if snapshot.Metadata.AccountID != "123456789012" || snapshot.Metadata.Region != "eu-west-1" || snapshot.Metadata.ClusterName != "test-cluster" {
+				t.Errorf("unexpected metadata: %+v", snapshot.Metadata)
+			}
+
+			// A snapshot renders like the live cluster it was taken from,
+			// including the resources that could not be fetched
+			if err := live.Run(); err != nil {
+				t.Fatalf("Run returned error: %v", err)
+			}
+			offline, offlineOut, _ := newTestOptions(newMockEKSClient(), "")
+			offline.fromFile = path
+			if err := offline.Complete(); err != nil {
+				t.Fatalf("Complete returned error: %v", err)
+			}
+			if err := offline.Run(); err != nil {
+				t.Fatalf("Run returned error: %v", err)
+			}
+
+			if offlineOut.String() != liveOut.String() {
+				t.Errorf("expected the snapshot to render like the live cluster\nLive:\n%s\nSnapshot:\n%s", liveOut.String(), offlineOut.String())
+			}
+			for _, expected := range []string{
+				"AmazonEKSClusterAdminPolicy",
+				`error: failed to describe "arn:aws:iam::123456789012:role/denied": AccessDeniedException`,
+				`error: failed to describe "ng-broken": AccessDeniedException`,
+				"error: failed to list insights: AccessDeniedException",
+			} {
+				if !strings.Contains(offlineOut.String(), expected) {
+					t.Errorf("Output does not contain expected string: %s\nGot: %s", expected, offlineOut.String())
+				}
+			}
+		})
+	}
+}
+
+func TestReadSnapshotRejectsResourceList(t *testing.T) {
+	o, out, _ := newTestOptions(newMockEKSClient(), "yaml")
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "list.yaml")
+	if err := os.WriteFile(path, out.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := readSnapshot(path)
+	if err == nil || !strings.Contains(err.Error(), "not an EKS snapshot") {
+		t.Errorf("expected -o yaml output to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// sortColumns maps the table columns accepted by --sort-by to JSONPath
+// expressions over the items of each resource type. Column names are matched
+// case insensitively, ignoring spaces, dashes and underscores.
+var sortColumns = map[string]map[string]string{
+	"access-entries": {
+		"accessentryprincipalarn": "{.PrincipalArn}",
+		"principalarn":            "{.PrincipalArn}",
+		"type":                    "{.Type}",
+		"username":                "{.Username}",
+		"createdat":               "{.CreatedAt}",
+	},
+	"addons": {
+		"name":                  "{.AddonName}",
+		"version":               "{.AddonVersion}",
+		"status":                "{.Status}",
+		"serviceaccountrolearn": "{.ServiceAccountRoleArn}",
+		"createdat":             "{.CreatedAt}",
+		"modifiedat":            "{.ModifiedAt}",
+	},
+	"cluster": {
+		"name":            "{.Name}",
+		"version":         "{.Version}",
+		"status":          "{.Status}",
+		"platformversion": "{.PlatformVersion}",
+		"authmode":        "{.AccessConfig.AuthenticationMode}",
+		"oidcissuer":      "{.Identity.Oidc.Issuer}",
+		"ipfamily":        "{.KubernetesNetworkConfig.IpFamily}",
+	},
+	"fargate-profiles": {
+		"name":                "{.FargateProfileName}",
+		"selectors":           "{.Selectors[0].Namespace}",
+		"selectornamespace":   "{.Selectors[0].Namespace}",
+		"podexecutionrolearn": "{.PodExecutionRoleArn}",
+		"status":              "{.Status}",
+		"createdat":           "{.CreatedAt}",
+	},
+	"insights": {
+		"name":              "{.Name}",
+		"category":          "{.Category}",
+		"status":            "{.InsightStatus.Status}",
+		"id":                "{.Id}",
+		"kubernetesversion": "{.KubernetesVersion}",
+		"lastrefresh":       "{.LastRefreshTime}",
+	},
+	"nodegroups": {
+		"name":         "{.NodegroupName}",
+		"status":       "{.Status}",
+		"instancetype": "{.InstanceTypes[0]}",
+		"desiredsize":  "{.ScalingConfig.DesiredSize}",
+		"minsize":      "{.ScalingConfig.MinSize}",
+		"maxsize":      "{.ScalingConfig.MaxSize}",
+		"version":      "{.Version}",
+		"amitype":      "{.AmiType}",
+		"capacitytype": "{.CapacityType}",
+		"disksize":     "{.DiskSize}",
+		"noderole":     "{.NodeRole}",
+		"createdat":    "{.CreatedAt}",
+	},
+	"pod-identity-associations": {
+		"arn":                "{.AssociationArn}",
+		"namespace":          "{.Namespace}",
+		"serviceaccountname": "{.ServiceAccount}",
+		"iamrolearn":         "{.RoleArn}",
+		"ownerarn":           "{.OwnerArn}",
+		"associationid":      "{.AssociationId}",
+		"createdat":          "{.CreatedAt}",
+	},
+	"updates": {
+		"createdat": "{.CreatedAt}",
+		"type":      "{.Type}",
+		"status":    "{.Status}",
+		"id":        "{.Id}",
+	},
+}
+
+// defaultSortPaths give every resource type a stable order by name, whatever
+// order the EKS API returned the items in.
+var defaultSortPaths = map[string][]string{
+	"access-entries":            {"{.PrincipalArn}"},
+	"addons":                    {"{.AddonName}"},
+	"cluster":                   {"{.Name}"},
+	"fargate-profiles":          {"{.FargateProfileName}"},
+	"insights":                  {"{.Name}", "{.Id}"},
+	"nodegroups":                {"{.NodegroupName}"},
+	"pod-identity-associations": {"{.Namespace}", "{.ServiceAccount}", "{.AssociationId}"},
+	"updates":                   {"{.CreatedAt}", "{.Id}"},
+}
+
+// isSortJSONPath reports whether --sort-by is a JSONPath rather than a column.
+func isSortJSONPath(sortBy string) bool {
+	return strings.HasPrefix(sortBy, "{") || strings.HasPrefix(sortBy, ".")
+}
+
+// relaxedJSONPath accepts ".Status" as well as "{.Status}", like kubectl.
+func relaxedJSONPath(path string) string {
+	if strings.HasPrefix(path, "{") {
+		return path
+	}
+	return "{" + path + "}"
+}
+
+func normalizeColumn(column string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(column))
+}
+
+// validateSortBy checks that sortBy is a valid JSONPath, or a column of
+// resourceType. Without a resource type the column must exist for at least
+// one of them; the others keep their default order.
+func validateSortBy(resourceType, sortBy string) error {
+	if isSortJSONPath(sortBy) {
+		if err := jsonpath.New("sort-by").Parse(relaxedJSONPath(sortBy)); err != nil {
+			return fmt.Errorf("invalid --sort-by JSONPath %q: %v", sortBy, err)
+		}
+		return nil
+	}
+
+	column := normalizeColumn(sortBy)
+	resourceTypes := validResourceTypes
+	if resourceType != "" {
+		resourceTypes = []string{resourceType}
+	}
+	for _, rt := range resourceTypes {
+		if _, ok := sortColumns[rt][column]; ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("--sort-by %q is neither a JSONPath nor a column of %s", sortBy, strings.Join(resourceTypes, ", "))
+}
+
+// sortPaths returns the JSONPath expressions to sort resourceType by, most
+// significant first.
+func sortPaths(resourceType, sortBy string) []string {
+	var paths []string
+	if isSortJSONPath(sortBy) {
+		paths = append(paths, relaxedJSONPath(sortBy))
+	} else if path, ok := sortColumns[resourceType][normalizeColumn(sortBy)]; ok {
+		paths = append(paths, path)
+	}
+	return append(paths, defaultSortPaths[resourceType]...)
+}
+
+// sortItems sorts items by the values of paths in their JSON representation,
+// so that the JSONPath seen in -o json output is the one to sort by. Items a
+// path does not apply to sort first.
+func sortItems[T any](items []T, paths []string) {
+	if len(items) < 2 || len(paths) == 0 {
+		return
+	}
+
+	parsers := make([]*jsonpath.JSONPath, 0, len(paths))
+	for _, path := range paths {
+		parser := jsonpath.New("sort-by").AllowMissingKeys(true)
+		if err := parser.Parse(path); err != nil {
+			continue
+		}
+		parsers = append(parsers, parser)
+	}
+
+	keys := make([][]interface{}, len(items))
+	for i, item := range items {
+		keys[i] = sortKeys(item, parsers)
+	}
+
+	indexes := make([]int, len(items))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		ka, kb := keys[indexes[a]], keys[indexes[b]]
+		for k := range ka {
+			if c := compareSortValues(ka[k], kb[k]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	sorted := make([]T, len(items))
+	for i, index := range indexes {
+		sorted[i] = items[index]
+	}
+	copy(items, sorted)
+}
+
+// sortKeys evaluates every parser against item. A value that is missing or
+// cannot be evaluated is nil.
+func sortKeys(item interface{}, parsers []*jsonpath.JSONPath) []interface{} {
+	keys := make([]interface{}, len(parsers))
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return keys
+	}
+	var obj interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return keys
+	}
+
+	for i, parser := range parsers {
+		results, err := parser.FindResults(obj)
+		if err != nil || len(results) == 0 || len(results[0]) == 0 {
+			continue
+		}
+		if value := results[0][0]; value.CanInterface() {
+			keys[i] = value.Interface()
+		}
+	}
+	return keys
+}
+
+// compareSortValues orders nil first, numbers numerically, RFC 3339
+// timestamps by time and anything else by its string form. Timestamps are
+// parsed as their fractional seconds drop trailing zeros, so their strings do
+// not sort by time.
+func compareSortValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	if fa, ok := a.(float64); ok {
+		if fb, ok := b.(float64); ok {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+
+	sa, sb := fmt.Sprint(a), fmt.Sprint(b)
+	if ta, err := time.Parse(time.RFC3339Nano, sa); err == nil {
+		if tb, err := time.Parse(time.RFC3339Nano, sb); err == nil {
+			return ta.Compare(tb)
+		}
+	}
+	return strings.Compare(sa, sb)
+}
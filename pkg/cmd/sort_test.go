@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+)
+
+func TestSortItems(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	nodegroup := func(name string, desired int32, createdAt *time.Time) types.Nodegroup {
+		return types.Nodegroup{
+			NodegroupName: stringPtr(name),
+			ScalingConfig: &types.NodegroupScalingConfig{DesiredSize: int32Ptr(desired)},
+			CreatedAt:     createdAt,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		sortBy   string
+		expected []string
+	}{
+		{
+			name:     "default by name",
+			expected: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:     "column sorts numbers numerically",
+			sortBy:   "DESIRED SIZE",
+			expected: []string{"b", "d", "c", "a"},
+		},
+		{
+			name:     "column with dashes",
+			sortBy:   "desired-size",
+			expected: []string{"b", "d", "c", "a"},
+		},
+		{
+			name:     "JSONPath with missing values first and ties by name",
+			sortBy:   ".CreatedAt",
+			expected: []string{"a", "d", "c", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := []types.Nodegroup{
+				nodegroup("c", 10, &older),
+				nodegroup("a", 20, nil),
+				nodegroup("b", 1, &newer),
+				nodegroup("d", 2, nil),
+			}
+			sortItems(items, sortPaths("nodegroups", tt.sortBy))
+
+			var names []string
+			for _, item := range items {
+				names = append(names, *item.NodegroupName)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("expected order %v, got %v", tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestValidateSortBy(t *testing.T) {
+	tests := []struct {
+		name          string
+		resourceType  string
+		sortBy        string
+		expectedError string
+	}{
+		{name: "column", resourceType: "nodegroups", sortBy: "capacity type"},
+		{name: "JSONPath", resourceType: "addons", sortBy: "{.Health.Issues[0].Code}"},
+		{name: "column of some resource type", sortBy: "status"},
+		{
+			name:          "unknown column",
+			resourceType:  "addons",
+			sortBy:        "capacity type",
+			expectedError: "neither a JSONPath nor a column of addons",
+		},
+		{
+			name:          "invalid JSONPath",
+			resourceType:  "addons",
+			sortBy:        "{.Status",
+			expectedError: "invalid --sort-by JSONPath",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSortBy(tt.resourceType, tt.sortBy)
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Errorf("expected error containing %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestCompareSortValues(t *testing.T) {
+	tests := []struct {
+		a, b     interface{}
+		expected int
+	}{
+		{a: "2024-11-05T10:00:00.5Z", b: "2024-11-05T10:00:00Z", expected: 1},
+		{a: "2024-11-05T10:00:00.55Z", b: "2024-11-05T10:00:00.5Z", expected: 1},
+		{a: "2024-11-05T10:00:00+01:00", b: "2024-11-05T09:30:00Z", expected: -1},
+		{a: float64(10), b: float64(9), expected: 1},
+		{a: "ng-b", b: "ng-a", expected: 1},
+		{a: nil, b: "ng-a", expected: -1},
+	}
+
+	for _, tt := range tests {
+		if got := compareSortValues(tt.a, tt.b); got != tt.expected {
+			t.Errorf("compareSortValues(%v, %v): expected %d, got %d", tt.a, tt.b, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// Update is an update of the cluster, or of one of its nodegroups or addons.
+// The EKS API only returns the updated resource in the ListUpdates input, so
+// it is kept next to the update.
+type Update struct {
+	types.Update
+	// NodegroupName is set for the updates of a nodegroup
+	NodegroupName *string `json:",omitempty"`
+	// AddonName is set for the updates of an addon
+	AddonName *string `json:",omitempty"`
+}
+
+type UpdateList struct {
+	metav1.TypeMeta
+	Items []Update
+	// clusters are the clusters of the items when viewing several contexts
+	clusters []clusterRef
+}
+
+// Implement runtime.Object interface
+func (u *UpdateList) GetObjectKind() schema.ObjectKind {
+	return &u.TypeMeta
+}
+
+func (u *UpdateList) DeepCopyObject() runtime.Object {
+	return &UpdateList{
+		TypeMeta: u.TypeMeta,
+		Items:    append([]Update(nil), u.Items...),
+		clusters: append([]clusterRef(nil), u.clusters...),
+	}
+}
+
+func NewUpdatePrinter(options printers.PrintOptions) printers.ResourcePrinter {
+	return printers.ResourcePrinterFunc(func(obj runtime.Object, w io.Writer) error {
+		list, ok := obj.(*UpdateList)
+		if !ok {
+			return fmt.Errorf("expected *UpdateList, got %T", obj)
+		}
+
+		table := &metav1.Table{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "Update",
+			},
+			ColumnDefinitions: []metav1.TableColumnDefinition{
+				{Name: "ID", Type: "string"},
+				{Name: "CREATED AT", Type: "string"},
+				{Name: "RESOURCE", Type: "string"},
+				{Name: "TYPE", Type: "string"},
+				{Name: "STATUS", Type: "string"},
+				{Name: "PARAMS", Type: "string"},
+				{Name: "ERRORS", Type: "string"},
+			},
+		}
+
+		for _, item := range list.Items {
+			table.Rows = append(table.Rows, metav1.TableRow{
+				Cells: []interface{}{
+					stringOrNone(item.Id),
+					tableTime(item.CreatedAt),
+					updateResource(item),
+					string(item.Type),
+					string(item.Status),
+					joinOrNone(formatUpdateParams(item.Params)),
+					formatUpdateErrors(item.Errors),
+				},
+			})
+		}
+
+		addClusterColumns(table, list.clusters)
+		return printTable(w, table, "updates", options)
+	})
+}
+
+// updateResource names the resource an update applies to.
+func updateResource(item Update) string {
+	switch {
+	case item.NodegroupName != nil:
+		return "nodegroup/" + *item.NodegroupName
+	case item.AddonName != nil:
+		return "addon/" + *item.AddonName
+	default:
+		return "cluster"
+	}
+}
+
+func formatUpdateParams(params []types.UpdateParam) []string {
+	var formatted []string
+	for _, param := range params {
+		formatted = append(formatted, fmt.Sprintf("%s=%s", param.Type, aws.ToString(param.Value)))
+	}
+	return formatted
+}
+
+func formatUpdateErrors(errs []types.ErrorDetail) string {
+	if len(errs) == 0 {
+		return "<none>"
+	}
+	var formatted []string
+	for _, e := range errs {
+		formatted = append(formatted, fmt.Sprintf("%s: %s", e.ErrorCode, aws.ToString(e.ErrorMessage)))
+	}
+	return strings.Join(formatted, "; ")
+}
+
+// ListUpdates lists the updates of the cluster, of every nodegroup and of
+// every addon. Names select updates by ID or by resource, e.g. nodegroup/ng-1.
+func (c *EKSClient) ListUpdates(ctx context.Context) ([]Update, error) {
+	refs, listErr := c.listUpdateRefs(ctx)
+	if listErr != nil && !isPartialError(listErr) {
+		return nil, listErr
+	}
+
+	refs = filterNames(c.nameFilter, refs, func(ref Update) []string {
+		return []string{aws.ToString(ref.Id), updateResource(ref)}
+	})
+
+	updateID := func(ref Update) string {
+		return aws.ToString(ref.Id)
+	}
+	updates, err := describeAll(ctx, c.pool, refs, updateID, c.DescribeUpdate)
+	if err != nil && !isPartialError(err) {
+		return nil, err
+	}
+	var listPartial *PartialError
+	if errors.As(listErr, &listPartial) {
+		for _, item := range listPartial.Errors {
+			err = withItemError(err, item)
+		}
+	}
+	return updates, err
+}
+
+// listUpdateRefs lists the updates of the cluster, of every nodegroup and of
+// every addon, with only their IDs and resources set. A nodegroup or addon
+// deleted since it was listed fails alone.
+func (c *EKSClient) listUpdateRefs(ctx context.Context) ([]Update, error) {
+	ngNames, err := c.listNodegroupNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	addonNames, err := c.listAddonNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The updates of the cluster are listed without a nodegroup or addon
+	targets := []Update{{}}
+	for i := range ngNames {
+		targets = append(targets, Update{NodegroupName: &ngNames[i]})
+	}
+	for i := range addonNames {
+		targets = append(targets, Update{AddonName: &addonNames[i]})
+	}
+
+	pages, err := describeAll(ctx, c.pool, targets, updateResource, c.listUpdateIDs)
+	if err != nil && !isPartialError(err) {
+		return nil, err
+	}
+	var refs []Update
+	for _, page := range pages {
+		refs = append(refs, page...)
+	}
+	return refs, err
+}
+
+// DescribeUpdateByID describes the update id, looking up the nodegroup or
+// addon it applies to.
+func (c *EKSClient) DescribeUpdateByID(ctx context.Context, id string) (Update, error) {
+	refs, err := c.listUpdateRefs(ctx)
+	if err != nil && !isPartialError(err) {
+		return Update{}, err
+	}
+	for _, ref := range refs {
+		if aws.ToString(ref.Id) == id {
+			return c.DescribeUpdate(ctx, ref)
+		}
+	}
+	// The update may belong to a resource whose updates could not be listed
+	if err != nil {
+		return Update{}, err
+	}
+	return Update{}, fmt.Errorf("update %q not found", id)
+}
+
+// listUpdateIDs lists the updates of target, which holds the nodegroup or
+// addon they apply to, if any. Only their IDs are set.
+func (c *EKSClient) listUpdateIDs(ctx context.Context, target Update) ([]Update, error) {
+	input := &eks.ListUpdatesInput{
+		Name:          c.clusterName,
+		NodegroupName: target.NodegroupName,
+		AddonName:     target.AddonName,
+	}
+
+	var refs []Update
+	paginator := eks.NewListUpdatesPaginator(c.client, input)
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range result.UpdateIds {
+			ref := target
+			ref.Id = aws.String(id)
+			refs = append(refs, ref)
+		}
+	}
+	return refs, nil
+}
+
+// DescribeUpdate describes the update ref.Id of the resource ref applies to.
+func (c *EKSClient) DescribeUpdate(ctx context.Context, ref Update) (Update, error) {
+	detail, err := c.client.DescribeUpdate(ctx, &eks.DescribeUpdateInput{
+		Name:          c.clusterName,
+		UpdateId:      ref.Id,
+		NodegroupName: ref.NodegroupName,
+		AddonName:     ref.AddonName,
+	})
+	if err != nil {
+		return Update{}, err
+	}
+	return Update{Update: *detail.Update, NodegroupName: ref.NodegroupName, AddonName: ref.AddonName}, nil
+}
+
+func describeUpdate(w *prefixWriter, item Update) {
+	w.Write(levelZero, "ID:\t%s\n", stringOrNone(item.Id))
+	w.Write(levelZero, "Resource:\t%s\n", updateResource(item))
+	w.Write(levelZero, "Type:\t%s\n", item.Type)
+	w.Write(levelZero, "Status:\t%s\n", item.Status)
+	w.Write(levelZero, "Created At:\t%s\n", timeOrNone(item.CreatedAt))
+	w.WriteList(levelZero, "Params", formatUpdateParams(item.Params))
+
+	if len(item.Errors) == 0 {
+		w.Write(levelZero, "Errors:\t<none>\n")
+		return
+	}
+	w.Write(levelZero, "Errors:\n")
+	w.Write(levelOne, "Code\tMessage\tResources\n")
+	w.Write(levelOne, "----\t-------\t---------\n")
+	for _, e := range item.Errors {
+		w.Write(levelOne, "%s\t%s\t%s\n", e.ErrorCode, stringOrNone(e.ErrorMessage), joinOrNone(e.ResourceIds))
+	}
+}
+
+// updateFields are the fields matched by --field-selector.
+func updateFields(item Update) []fields.Set {
+	return []fields.Set{{
+		"type":     string(item.Type),
+		"status":   string(item.Status),
+		"resource": updateResource(item),
+	}}
+}
+
+// updateTags are the tags matched by --selector. Updates cannot be tagged.
+func updateTags(item Update) map[string]string {
+	return nil
+}
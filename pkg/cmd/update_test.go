@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+func TestNewUpdatePrinter(t *testing.T) {
+	createdAt := time.Date(2024, 11, 5, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		updates        []Update
+		expectedOutput []string
+	}{
+		{
+			name: "cluster version update",
+			updates: []Update{
+				{
+					Update: types.Update{
+						Id:        stringPtr("update-1"),
+						Type:      types.UpdateTypeVersionUpdate,
+						Status:    types.UpdateStatusSuccessful,
+						CreatedAt: &createdAt,
+						Params: []types.UpdateParam{
+							{Type: types.UpdateParamTypeVersion, Value: stringPtr("1.31")},
+							{Type: types.UpdateParamTypePlatformVersion, Value: stringPtr("eks.1")},
+						},
+					},
+				},
+			},
+			expectedOutput: []string{
+				"CREATED AT",
+				"RESOURCE",
+				"PARAMS",
+				"2024-11-05T10:30:00Z",
+				"cluster",
+				"VersionUpdate",
+				"Successful",
+				"Version=1.31,PlatformVersion=eks.1",
+			},
+		},
+		{
+			name: "failed nodegroup update",
+			updates: []Update{
+				{
+					Update: types.Update{
+						Id:     stringPtr("update-2"),
+						Type:   types.UpdateTypeConfigUpdate,
+						Status: types.UpdateStatusFailed,
+						Errors: []types.ErrorDetail{
+							{ErrorCode: types.ErrorCodeNodeCreationFailure, ErrorMessage: stringPtr("Instances failed to join")},
+						},
+					},
+					NodegroupName: stringPtr("ng-1"),
+				},
+			},
+			expectedOutput: []string{
+				"nodegroup/ng-1",
+				"Failed",
+				"NodeCreationFailure: Instances failed to join",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			printer := NewUpdatePrinter(printers.PrintOptions{})
+			buf := &bytes.Buffer{}
+
+			if err := printer.PrintObj(&UpdateList{Items: tt.updates}, buf); err != nil {
+				t.Fatalf("PrintObj returned error: %v", err)
+			}
+
+			output := buf.String()
+			for _, expected := range tt.expectedOutput {
+				if !strings.Contains(output, expected) {
+					t.Errorf("Output does not contain expected string: %s\nGot: %s", expected, output)
+				}
+			}
+		})
+	}
+}
+
+// newUpdatesMock has updates on the cluster, on nodegroup ng-1 over two
+// pages and on addon vpc-cni, one of which cannot be described.
+func newUpdatesMock() *mockEKSClient {
+	base := time.Date(2024, 11, 5, 10, 0, 0, 0, time.UTC)
+	createdAt := map[string]time.Time{
+		"cluster-1":   base.Add(2 * time.Hour),
+		"ng-1":        base,
+		"ng-2":        base.Add(3 * time.Hour),
+		"vpc-cni-1":   base.Add(time.Hour),
+		"vpc-cni-bad": base,
+	}
+
+	mockClient := newMockEKSClient()
+	mockClient.listNodegroupsFunc = func(ctx context.Context, params *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error) {
+		return &eks.ListNodegroupsOutput{Nodegroups: []string{"ng-1"}}, nil
+	}
+	mockClient.listAddonsFunc = func(ctx context.Context, params *eks.ListAddonsInput) (*eks.ListAddonsOutput, error) {
+		return &eks.ListAddonsOutput{Addons: []string{"vpc-cni"}}, nil
+	}
+	mockClient.listUpdatesFunc = func(ctx context.Context, params *eks.ListUpdatesInput) (*eks.ListUpdatesOutput, error) {
+		switch {
+		case params.NodegroupName != nil && params.NextToken == nil:
+			return &eks.ListUpdatesOutput{UpdateIds: []string{"ng-1"}, NextToken: stringPtr("page-2")}, nil
+		case params.NodegroupName != nil:
+			return &eks.ListUpdatesOutput{UpdateIds: []string{"ng-2"}}, nil
+		case params.AddonName != nil:
+			return &eks.ListUpdatesOutput{UpdateIds: []string{"vpc-cni-1", "vpc-cni-bad"}}, nil
+		default:
+			return &eks.ListUpdatesOutput{UpdateIds: []string{"cluster-1"}}, nil
+		}
+	}
+	mockClient.describeUpdateFunc = func(ctx context.Context, params *eks.DescribeUpdateInput) (*eks.DescribeUpdateOutput, error) {
+		id := aws.ToString(params.UpdateId)
+		if id == "vpc-cni-bad" {
+			return nil, errors.New("AccessDeniedException: not authorized to perform eks:DescribeUpdate")
+		}
+		// The nodegroup and addon must be passed along with their updates
+		if strings.HasPrefix(id, "ng-") && aws.ToString(params.NodegroupName) != "ng-1" ||
+			strings.HasPrefix(id, "vpc-cni-") && aws.ToString(params.AddonName) != "vpc-cni" {
+			return nil, &types.ResourceNotFoundException{Message: stringPtr("update not found")}
+		}
+		created := createdAt[id]
+		return &eks.DescribeUpdateOutput{
+			Update: &types.Update{
+				Id:        params.UpdateId,
+				Type:      types.UpdateTypeVersionUpdate,
+				Status:    types.UpdateStatusSuccessful,
+				CreatedAt: &created,
+			},
+		}, nil
+	}
+	return mockClient
+}
+
+func TestListUpdates(t *testing.T) {
+	client := &EKSClient{
+		client:      newUpdatesMock(),
+		clusterName: stringPtr("test-cluster"),
+		pool:        newWorkerPool(2),
+	}
+
+	updates, err := client.ListUpdates(context.Background())
+	if !isPartialError(err) || !strings.Contains(err.Error(), "vpc-cni-bad") {
+		t.Fatalf("expected a partial error for vpc-cni-bad, got %v", err)
+	}
+
+	resources := map[string]string{}
+	for _, update := range updates {
+		resources[*update.Id] = updateResource(update)
+	}
+	expected := map[string]string{
+		"cluster-1": "cluster",
+		"ng-1":      "nodegroup/ng-1",
+		"ng-2":      "nodegroup/ng-1",
+		"vpc-cni-1": "addon/vpc-cni",
+	}
+	if len(resources) != len(expected) {
+		t.Fatalf("expected updates %v, got %v", expected, resources)
+	}
+	for id, resource := range expected {
+		if resources[id] != resource {
+			t.Errorf("update %s: expected resource %s, got %s", id, resource, resources[id])
+		}
+	}
+}
+
+func TestListUpdatesDeletedNodegroup(t *testing.T) {
+	mockClient := newUpdatesMock()
+	listUpdates := mockClient.listUpdatesFunc
+	mockClient.listUpdatesFunc = func(ctx context.Context, params *eks.ListUpdatesInput) (*eks.ListUpdatesOutput, error) {
+		if params.NodegroupName != nil {
+			return nil, errors.New("ResourceNotFoundException: No node group found for name: ng-1")
+		}
+		return listUpdates(ctx, params)
+	}
+	client := &EKSClient{
+		client:      mockClient,
+		clusterName: stringPtr("test-cluster"),
+		pool:        newWorkerPool(2),
+	}
+
+	updates, err := client.ListUpdates(context.Background())
+	var partial *PartialError
+	if !errors.As(err, &partial) || len(partial.Errors) != 2 {
+		t.Fatalf("expected partial errors for vpc-cni-bad and ng-1, got %v", err)
+	}
+	names := []string{partial.Errors[0].Name, partial.Errors[1].Name}
+	if !slices.Contains(names, "nodegroup/ng-1") || !slices.Contains(names, "vpc-cni-bad") {
+		t.Errorf("expected errors for nodegroup/ng-1 and vpc-cni-bad, got %v", names)
+	}
+	if len(updates) != 2 {
+		t.Errorf("expected the updates of the cluster and the addon, got %+v", updates)
+	}
+}
+
+func TestRunUpdatesTimeline(t *testing.T) {
+	o, out, _ := newTestOptions(newUpdatesMock(), "")
+	o.resourceType = "updates"
+
+	if err := o.Run(); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	var ids []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if fields := strings.Fields(line); len(fields) > 2 && strings.HasPrefix(fields[1], "2024-") {
+			ids = append(ids, fields[2])
+		}
+	}
+	expected := []string{"nodegroup/ng-1", "addon/vpc-cni", "cluster", "nodegroup/ng-1"}
+	if strings.Join(ids, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected the updates sorted by time %v, got %v\n%s", expected, ids, out.String())
+	}
+	if !strings.Contains(out.String(), `error: failed to describe "vpc-cni-bad": AccessDeniedException`) {
+		t.Errorf("expected the describe error to be reported, got %s", out.String())
+	}
+}
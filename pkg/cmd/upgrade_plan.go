@@ -0,0 +1,426 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// exitUpgradeBlocked is the exit code of the upgrade-plan command when the
+// plan has blocking items.
+const exitUpgradeBlocked = 2
+
+// Severities of the upgrade checks, in the order they are printed
+const (
+	severityBlocking = "Blocking"
+	severityWarning  = "Warning"
+	severityOK       = "OK"
+)
+
+var severityOrder = map[string]int{severityBlocking: 0, severityWarning: 1, severityOK: 2}
+
+// UpgradeCheck is an item of the upgrade checklist.
+type UpgradeCheck struct {
+	Severity string
+	// Check is the resource type checked, e.g. nodegroups
+	Check    string
+	Resource string
+	Message  string
+}
+
+// UpgradePlan is the output of the upgrade-plan command.
+type UpgradePlan struct {
+	metav1.TypeMeta
+	Cluster        string `json:"cluster"`
+	CurrentVersion string `json:"currentVersion"`
+	TargetVersion  string `json:"targetVersion"`
+	// Ready is set when no check blocks the upgrade
+	Ready  bool            `json:"ready"`
+	Errors []ResourceError `json:"errors,omitempty"`
+	Items  []UpgradeCheck  `json:"items"`
+}
+
+// Implement runtime.Object interface
+func (u *UpgradePlan) GetObjectKind() schema.ObjectKind {
+	return &u.TypeMeta
+}
+
+func (u *UpgradePlan) DeepCopyObject() runtime.Object {
+	copied := *u
+	copied.Errors = append([]ResourceError(nil), u.Errors...)
+	copied.Items = append([]UpgradeCheck(nil), u.Items...)
+	return &copied
+}
+
+func NewUpgradePlanCmd(o *Options) *cobra.Command {
+	var target string
+	printFlags := genericclioptions.NewPrintFlags("")
+
+	cmd := &cobra.Command{
+		Use:   "upgrade-plan --target VERSION",
+		Short: "Check whether the cluster is ready for a Kubernetes upgrade",
+		Long: `Check whether the cluster is ready for a Kubernetes minor version upgrade.
+
+The checklist combines:
+  - the cluster status and upgrade policy
+  - the upgrade readiness insights of the target version
+  - the compatibility of every addon with the target version
+  - the version skew of every nodegroup against the target version
+  - the Fargate pods, which must be restarted after the upgrade
+
+Blocking items are listed first, then warnings.
+
+Exit codes:
+  0  nothing blocks the upgrade
+  1  an error occurred
+  2  at least one item blocks the upgrade`,
+		Example: `  # Check the upgrade of the current cluster to 1.31
+  kubectl eks-viewer upgrade-plan --target 1.31
+
+  # Check it in CI
+  kubectl eks-viewer upgrade-plan --target 1.31 -o json | jq '.items[] | select(.Severity == "Blocking")'`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if _, err := version.ParseMajorMinor(target); err != nil {
+				return fmt.Errorf("invalid --target %q, expected a Kubernetes minor version such as 1.31", target)
+			}
+			o.printFlags = printFlags
+
+			if err := o.Complete(); err != nil {
+				return err
+			}
+			return o.RunUpgradePlan(context.Background(), target)
+		},
+	}
+
+	cmd.Flags().StringVar(&target, "target", "", "Kubernetes version to upgrade to, e.g. 1.31")
+	_ = cmd.MarkFlagRequired("target")
+	printFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+// RunUpgradePlan checks the cluster for an upgrade to target and prints the
+// checklist.
+func (o *Options) RunUpgradePlan(ctx context.Context, target string) error {
+	plan, err := o.buildUpgradePlan(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	if err := o.printUpgradePlan(o.Out, plan); err != nil {
+		return err
+	}
+	if err := o.checkErrors(plan.Errors); err != nil {
+		return err
+	}
+	if !plan.Ready {
+		blocking := 0
+		for _, item := range plan.Items {
+			if item.Severity == severityBlocking {
+				blocking++
+			}
+		}
+		return &ExitError{Code: exitUpgradeBlocked, Err: fmt.Errorf("upgrade to %s is blocked by %d item(s)", target, blocking)}
+	}
+	return nil
+}
+
+func (o *Options) buildUpgradePlan(ctx context.Context, target string) (*UpgradePlan, error) {
+	clusters, err := o.eksClient.DescribeCluster(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe cluster: %v", err)
+	}
+	cluster := clusters[0]
+	current := aws.ToString(cluster.Version)
+
+	currentVersion, err := version.ParseMajorMinor(current)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster version %q: %v", current, err)
+	}
+	targetVersion, err := version.ParseMajorMinor(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target version %q: %v", target, err)
+	}
+	if !targetVersion.GreaterThan(currentVersion) {
+		return nil, fmt.Errorf("target version %s must be newer than the cluster version %s", target, current)
+	}
+
+	resourceList := &ResourceList{}
+	var insights []types.InsightSummary
+	resources := []resourceFetcher{{
+		resourceType: "insights",
+		fetch: func(ctx context.Context) error {
+			var err error
+			insights, err = o.eksClient.listInsightSummaries(ctx, &types.InsightsFilter{
+				Categories:         []types.Category{types.CategoryUpgradeReadiness},
+				KubernetesVersions: []string{target},
+			})
+			return err
+		},
+	}}
+	for _, res := range o.resourceFetchers(resourceList, printers.PrintOptions{}) {
+		switch res.resourceType {
+		case "addons", "nodegroups", "fargate-profiles":
+			resources = append(resources, res)
+		}
+	}
+
+	progress := newProgressReporter(o.ErrOut, o.quiet)
+	done := o.fetchAll(ctx, resources, progress)
+	fetchErrs := map[string]error{}
+	plan := &UpgradePlan{
+		TypeMeta:       metav1.TypeMeta{APIVersion: "v1", Kind: "EksUpgradePlan"},
+		Cluster:        aws.ToString(cluster.Name),
+		CurrentVersion: current,
+		TargetVersion:  target,
+	}
+	for i, res := range resources {
+		if err := <-done[i]; err != nil {
+			fetchErrs[res.resourceType] = err
+			plan.Errors = append(plan.Errors, toResourceErrors(res.resourceType, err)...)
+		}
+	}
+	progress.Clear()
+
+	plan.Items = append(plan.Items, clusterChecks(cluster, currentVersion, targetVersion)...)
+	plan.Items = append(plan.Items, insightChecks(insights)...)
+	if fetchErrs["addons"] == nil || isPartialError(fetchErrs["addons"]) {
+		upgrades, err := o.eksClient.addonUpgrades(ctx, resourceList.Items.Addons, current, target)
+		if err != nil {
+			plan.Errors = append(plan.Errors, toResourceErrors("addons", err)...)
+		}
+		plan.Items = append(plan.Items, addonChecks(upgrades, target)...)
+	}
+	plan.Items = append(plan.Items, nodegroupChecks(resourceList.Items.Nodegroups, currentVersion, targetVersion)...)
+	plan.Items = append(plan.Items, fargateChecks(resourceList.Items.FargateProfiles, target)...)
+
+	sort.SliceStable(plan.Items, func(i, j int) bool {
+		return severityOrder[plan.Items[i].Severity] < severityOrder[plan.Items[j].Severity]
+	})
+	plan.Ready = true
+	for _, item := range plan.Items {
+		if item.Severity == severityBlocking {
+			plan.Ready = false
+		}
+	}
+	return plan, nil
+}
+
+// clusterChecks checks the cluster status, that the upgrade is of a single
+// minor version, and reports the upgrade policy.
+func clusterChecks(cluster types.Cluster, current, target *version.Version) []UpgradeCheck {
+	name := aws.ToString(cluster.Name)
+	var checks []UpgradeCheck
+
+	if cluster.Status != types.ClusterStatusActive {
+		checks = append(checks, UpgradeCheck{Severity: severityBlocking, Check: "cluster", Resource: name,
+			Message: fmt.Sprintf("cluster is %s, it must be ACTIVE to be upgraded", cluster.Status)})
+	}
+	if next := current.WithMinor(current.Minor() + 1); target.GreaterThan(next) {
+		checks = append(checks, UpgradeCheck{Severity: severityBlocking, Check: "cluster", Resource: name,
+			Message: fmt.Sprintf("EKS upgrades one minor version at a time, upgrade to %d.%d first", next.Major(), next.Minor())})
+	} else {
+		checks = append(checks, UpgradeCheck{Severity: severityOK, Check: "cluster", Resource: name,
+			Message: fmt.Sprintf("control plane can be upgraded from %d.%d", current.Major(), current.Minor())})
+	}
+
+	if cluster.UpgradePolicy != nil {
+		message := "standard support: the cluster is upgraded automatically when standard support of its version ends"
+		if cluster.UpgradePolicy.SupportType == types.SupportTypeExtended {
+			message = "extended support: the cluster stays on its version after standard support ends, at an additional cost"
+		}
+		checks = append(checks, UpgradeCheck{Severity: severityOK, Check: "cluster", Resource: name, Message: message})
+	}
+	return checks
+}
+
+// insightChecks reports the upgrade readiness insights by status.
+func insightChecks(insights []types.InsightSummary) []UpgradeCheck {
+	var checks []UpgradeCheck
+	for _, insight := range insights {
+		severity, status, reason := severityWarning, "UNKNOWN", ""
+		if insight.InsightStatus != nil {
+			status, reason = string(insight.InsightStatus.Status), aws.ToString(insight.InsightStatus.Reason)
+		}
+		switch types.InsightStatusValue(status) {
+		case types.InsightStatusValueError:
+			severity = severityBlocking
+		case types.InsightStatusValuePassing:
+			severity = severityOK
+		}
+
+		message := status
+		if reason != "" {
+			message = fmt.Sprintf("%s: %s", status, reason)
+		}
+		checks = append(checks, UpgradeCheck{Severity: severity, Check: "insights", Resource: aws.ToString(insight.Name), Message: message})
+	}
+	return checks
+}
+
+// addonChecks blocks on addons whose version does not support target.
+func addonChecks(upgrades []AddonUpgrade, target string) []UpgradeCheck {
+	var checks []UpgradeCheck
+	for _, upgrade := range upgrades {
+		check := UpgradeCheck{Check: "addons", Resource: upgrade.AddonName}
+		switch {
+		case upgrade.CompatibleWithNext == nil:
+			check.Severity = severityWarning
+			check.Message = fmt.Sprintf("EKS publishes no addon versions for %s yet", target)
+		case !*upgrade.CompatibleWithNext:
+			check.Severity = severityBlocking
+			check.Message = fmt.Sprintf("%s does not support %s, upgrade the addon first", upgrade.AddonVersion, target)
+		case upgrade.Behind:
+			check.Severity = severityOK
+			check.Message = fmt.Sprintf("%s supports %s, %s is available", upgrade.AddonVersion, target, upgrade.LatestVersion)
+		default:
+			check.Severity = severityOK
+			check.Message = fmt.Sprintf("%s supports %s", upgrade.AddonVersion, target)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// maxKubeletSkew is the number of minor versions the kubelet may be older
+// than the API server: three since Kubernetes 1.28, two before.
+func maxKubeletSkew(apiServer *version.Version) uint {
+	if apiServer.AtLeast(version.MajorMinor(1, 28)) {
+		return 3
+	}
+	return 2
+}
+
+// nodegroupChecks blocks on nodegroups that would be further behind target
+// than the version skew policy allows, and warns about those behind the
+// current control plane.
+func nodegroupChecks(nodegroups []Nodegroup, current, target *version.Version) []UpgradeCheck {
+	var checks []UpgradeCheck
+	for _, ng := range nodegroups {
+		check := UpgradeCheck{Check: "nodegroups", Resource: aws.ToString(ng.NodegroupName)}
+		ngVersion, err := version.ParseGeneric(nodegroupKubernetesVersion(ng))
+		switch {
+		case err != nil:
+			check.Severity = severityWarning
+			check.Message = "unknown Kubernetes version"
+		case target.Minor() > ngVersion.Minor()+maxKubeletSkew(target):
+			check.Severity = severityBlocking
+			check.Message = fmt.Sprintf("%d.%d would be more than %d minor versions behind %d.%d, upgrade the nodegroup first",
+				ngVersion.Major(), ngVersion.Minor(), maxKubeletSkew(target), target.Major(), target.Minor())
+		case ngVersion.Minor() < current.Minor():
+			check.Severity = severityWarning
+			check.Message = fmt.Sprintf("%d.%d is behind the control plane %d.%d", ngVersion.Major(), ngVersion.Minor(), current.Major(), current.Minor())
+		default:
+			check.Severity = severityOK
+			check.Message = fmt.Sprintf("%d.%d, upgrade it after the control plane", ngVersion.Major(), ngVersion.Minor())
+		}
+		if err == nil && ng.ReleaseVersion != nil {
+			check.Message += fmt.Sprintf(" (release %s)", *ng.ReleaseVersion)
+		}
+		if ng.Status != types.NodegroupStatusActive && check.Severity == severityOK {
+			check.Severity = severityWarning
+			check.Message = fmt.Sprintf("nodegroup is %s, %s", ng.Status, check.Message)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// nodegroupKubernetesVersion returns the Kubernetes version of a nodegroup,
+// taken from its release version when it has none, e.g. 1.30.4-20241109.
+func nodegroupKubernetesVersion(ng Nodegroup) string {
+	if v := aws.ToString(ng.Version); v != "" {
+		return v
+	}
+	return strings.SplitN(aws.ToString(ng.ReleaseVersion), "-", 2)[0]
+}
+
+// fargateChecks warns that Fargate pods keep the kubelet version they were
+// started with until they are restarted.
+func fargateChecks(profiles []FargateProfile, target string) []UpgradeCheck {
+	var checks []UpgradeCheck
+	for _, profile := range profiles {
+		checks = append(checks, UpgradeCheck{Severity: severityWarning, Check: "fargate-profiles", Resource: aws.ToString(profile.FargateProfileName),
+			Message: fmt.Sprintf("restart the pods of the profile after the upgrade to run them on %s", target)})
+	}
+	return checks
+}
+
+func (o *Options) printUpgradePlan(out io.Writer, plan *UpgradePlan) error {
+	if !o.isTableFormat() {
+		printer, err := o.printFlags.ToPrinter()
+		if err != nil {
+			return err
+		}
+		if err := printer.PrintObj(plan, out); err != nil {
+			return err
+		}
+		printResourceErrors(o.ErrOut, plan.Errors)
+		return nil
+	}
+
+	if err := NewUpgradePlanPrinter(printers.PrintOptions{}).PrintObj(plan, out); err != nil {
+		return err
+	}
+	printResourceErrors(out, plan.Errors)
+	return nil
+}
+
+func NewUpgradePlanPrinter(options printers.PrintOptions) printers.ResourcePrinter {
+	return printers.ResourcePrinterFunc(func(obj runtime.Object, w io.Writer) error {
+		plan, ok := obj.(*UpgradePlan)
+		if !ok {
+			return fmt.Errorf("expected *UpgradePlan, got %T", obj)
+		}
+
+		table := &metav1.Table{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "UpgradeCheck",
+			},
+			ColumnDefinitions: []metav1.TableColumnDefinition{
+				{Name: "SEVERITY", Type: "string"},
+				{Name: "CHECK", Type: "string"},
+				{Name: "RESOURCE", Type: "string"},
+				{Name: "MESSAGE", Type: "string"},
+			},
+		}
+
+		counts := map[string]int{}
+		for _, item := range plan.Items {
+			counts[item.Severity]++
+			table.Rows = append(table.Rows, metav1.TableRow{
+				Cells: []interface{}{item.Severity, item.Check, item.Resource, item.Message},
+			})
+		}
+
+		header := fmt.Sprintf("upgrade plan: %s %s -> %s", plan.Cluster, plan.CurrentVersion, plan.TargetVersion)
+		if err := printTable(w, table, header, options); err != nil {
+			return err
+		}
+		if plan.Ready {
+			fmt.Fprintf(w, "\nReady to upgrade to %s: %d warning(s)\n", plan.TargetVersion, counts[severityWarning])
+		} else {
+			fmt.Fprintf(w, "\nNot ready to upgrade to %s: %d blocking item(s), %d warning(s)\n",
+				plan.TargetVersion, counts[severityBlocking], counts[severityWarning])
+		}
+		return nil
+	})
+}
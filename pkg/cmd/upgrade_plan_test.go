@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+func TestNodegroupChecks(t *testing.T) {
+	tests := []struct {
+		name             string
+		nodegroup        types.Nodegroup
+		current          string
+		target           string
+		expectedSeverity string
+		expectedMessage  string
+	}{
+		{
+			name:             "too far behind the target",
+			nodegroup:        types.Nodegroup{Version: stringPtr("1.27"), Status: types.NodegroupStatusActive},
+			current:          "1.30",
+			target:           "1.31",
+			expectedSeverity: severityBlocking,
+			expectedMessage:  "1.27 would be more than 3 minor versions behind 1.31",
+		},
+		{
+			name:             "two minor versions allowed before 1.28",
+			nodegroup:        types.Nodegroup{Version: stringPtr("1.24"), Status: types.NodegroupStatusActive},
+			current:          "1.26",
+			target:           "1.27",
+			expectedSeverity: severityBlocking,
+			expectedMessage:  "more than 2 minor versions behind",
+		},
+		{
+			name:             "behind the control plane",
+			nodegroup:        types.Nodegroup{Version: stringPtr("1.29"), ReleaseVersion: stringPtr("1.29.10-20241109"), Status: types.NodegroupStatusActive},
+			current:          "1.30",
+			target:           "1.31",
+			expectedSeverity: severityWarning,
+			expectedMessage:  "1.29 is behind the control plane 1.30 (release 1.29.10-20241109)",
+		},
+		{
+			name:             "version from the release version",
+			nodegroup:        types.Nodegroup{ReleaseVersion: stringPtr("1.30.4-20241109"), Status: types.NodegroupStatusActive},
+			current:          "1.30",
+			target:           "1.31",
+			expectedSeverity: severityOK,
+			expectedMessage:  "1.30, upgrade it after the control plane",
+		},
+		{
+			name:             "degraded",
+			nodegroup:        types.Nodegroup{Version: stringPtr("1.30"), Status: types.NodegroupStatusDegraded},
+			current:          "1.30",
+			target:           "1.31",
+			expectedSeverity: severityWarning,
+			expectedMessage:  "nodegroup is DEGRADED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := nodegroupChecks([]types.Nodegroup{tt.nodegroup}, version.MustParseMajorMinor(tt.current), version.MustParseMajorMinor(tt.target))
+			if len(checks) != 1 {
+				t.Fatalf("expected one check, got %+v", checks)
+			}
+			if checks[0].Severity != tt.expectedSeverity || !strings.Contains(checks[0].Message, tt.expectedMessage) {
+				t.Errorf("expected %s %q, got %+v", tt.expectedSeverity, tt.expectedMessage, checks[0])
+			}
+		})
+	}
+}
+
+// newUpgradePlanMock extends the addon upgrades mock of a 1.31 cluster with
+// upgrade insights, a nodegroup behind the control plane and a Fargate
+// profile.
+func newUpgradePlanMock() *mockEKSClient {
+	mockClient := newAddonUpgradesMock(map[string]int{}, &sync.Mutex{})
+	mockClient.describeClusterFunc = func(ctx context.Context, params *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error) {
+		return &eks.DescribeClusterOutput{
+			Cluster: &types.Cluster{
+				Name:          params.Name,
+				Version:       stringPtr("1.31"),
+				Status:        types.ClusterStatusActive,
+				UpgradePolicy: &types.UpgradePolicyResponse{SupportType: types.SupportTypeExtended},
+			},
+		}, nil
+	}
+	mockClient.listInsightsFunc = func(ctx context.Context, params *eks.ListInsightsInput) (*eks.ListInsightsOutput, error) {
+		if params.Filter == nil || params.Filter.Categories[0] != types.CategoryUpgradeReadiness || params.Filter.KubernetesVersions[0] != "1.32" {
+			return &eks.ListInsightsOutput{}, nil
+		}
+		return &eks.ListInsightsOutput{Insights: []types.InsightSummary{
+			{
+				Name:          stringPtr("Deprecated APIs removed in Kubernetes v1.32"),
+				InsightStatus: &types.InsightStatus{Status: types.InsightStatusValueError, Reason: stringPtr("Deprecated API usage detected")},
+			},
+			{
+				Name:          stringPtr("Kubelet version skew"),
+				InsightStatus: &types.InsightStatus{Status: types.InsightStatusValuePassing, Reason: stringPtr("No issues")},
+			},
+		}}, nil
+	}
+	mockClient.listNodegroupsFunc = func(ctx context.Context, params *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error) {
+		return &eks.ListNodegroupsOutput{Nodegroups: []string{"ng-1"}}, nil
+	}
+	mockClient.describeNodegroupFunc = func(ctx context.Context, params *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error) {
+		return &eks.DescribeNodegroupOutput{
+			Nodegroup: &types.Nodegroup{NodegroupName: params.NodegroupName, Version: stringPtr("1.30"), Status: types.NodegroupStatusActive},
+		}, nil
+	}
+	mockClient.listFargateProfilesFunc = func(ctx context.Context, params *eks.ListFargateProfilesInput) (*eks.ListFargateProfilesOutput, error) {
+		return &eks.ListFargateProfilesOutput{FargateProfileNames: []string{"fp-default"}}, nil
+	}
+	mockClient.describeFargateProfileFunc = func(ctx context.Context, params *eks.DescribeFargateProfileInput) (*eks.DescribeFargateProfileOutput, error) {
+		return &eks.DescribeFargateProfileOutput{FargateProfile: &types.FargateProfile{FargateProfileName: params.FargateProfileName}}, nil
+	}
+	return mockClient
+}
+
+func TestRunUpgradePlanJSON(t *testing.T) {
+	o, out, _ := newTestOptions(newUpgradePlanMock(), "json")
+
+	err := o.RunUpgradePlan(context.Background(), "1.32")
+	if ExitCode(err) != exitUpgradeBlocked || !strings.Contains(err.Error(), "upgrade to 1.32 is blocked by 2 item(s)") {
+		t.Fatalf("expected the upgrade to be blocked, got %v", err)
+	}
+
+	plan := &UpgradePlan{}
+	if err := json.Unmarshal(out.Bytes(), plan); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if plan.Kind != "EksUpgradePlan" || plan.Ready || plan.CurrentVersion != "1.31" || plan.TargetVersion != "1.32" {
+		t.Errorf("unexpected plan: %+v", plan)
+	}
+
+	var got []string
+	for _, item := range plan.Items {
+		got = append(got, item.Severity+" "+item.Check+" "+item.Resource)
+	}
+	expected := []string{
+		"Blocking insights Deprecated APIs removed in Kubernetes v1.32",
+		"Blocking addons kube-proxy",
+		"Warning nodegroups ng-1",
+		"Warning fargate-profiles fp-default",
+		"OK cluster test-cluster",
+		"OK cluster test-cluster",
+		"OK insights Kubelet version skew",
+		"OK addons coredns",
+		"OK addons vpc-cni",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected checklist:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestRunUpgradePlanTable(t *testing.T) {
+	o, out, _ := newTestOptions(newUpgradePlanMock(), "")
+
+	err := o.RunUpgradePlan(context.Background(), "1.33")
+	if ExitCode(err) != exitUpgradeBlocked {
+		t.Fatalf("expected the upgrade to be blocked, got %v", err)
+	}
+
+	for _, expected := range []string{
+		"=== upgrade plan: test-cluster 1.31 -> 1.33 ===",
+		"EKS upgrades one minor version at a time, upgrade to 1.32 first",
+		"extended support",
+		"Not ready to upgrade to 1.33:",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Output does not contain expected string: %s\nGot: %s", expected, out.String())
+		}
+	}
+}
+
+func TestRunUpgradePlanOlderTarget(t *testing.T) {
+	o, _, _ := newTestOptions(newUpgradePlanMock(), "")
+
+	err := o.RunUpgradePlan(context.Background(), "1.31")
+	if err == nil || !strings.Contains(err.Error(), "target version 1.31 must be newer than the cluster version 1.31") {
+		t.Errorf("expected an error for a target that is not newer, got %v", err)
+	}
+}
@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of the wait command
+const (
+	exitWaitFailed  = 1
+	exitWaitTimeout = 2
+)
+
+// waitBackoff is the delay between two Describe calls while waiting. It
+// doubles after every call up to max.
+type waitBackoff struct {
+	initial time.Duration
+	max     time.Duration
+}
+
+var defaultWaitBackoff = waitBackoff{initial: 2 * time.Second, max: 30 * time.Second}
+
+func NewWaitCmd(o *Options) *cobra.Command {
+	var (
+		forCondition string
+		timeout      time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "wait [resource-type]/[name] --for=[status=STATUS|healthy|delete]",
+		Short: "Wait for an EKS resource to reach a condition",
+		Long: `Wait for an EKS resource to reach a condition.
+The resource is described with an exponential backoff until the condition is
+met, the timeout expires or the resource fails.
+
+Resource types: addons, cluster, fargate-profiles, nodegroups. The cluster
+name is optional and defaults to the current cluster.
+
+Conditions:
+  - status=STATUS: the resource has the given status, e.g. ACTIVE
+  - healthy: the resource is ACTIVE and reports no health issues
+  - delete: the resource no longer exists
+
+Exit codes:
+  0  the condition was met
+  1  the condition can no longer be met, e.g. the resource is *_FAILED, or an error occurred
+  2  the timeout expired`,
+		Example: `  # Wait for a nodegroup upgrade to finish
+  kubectl eks-viewer wait nodegroups/my-nodegroup --for=status=ACTIVE --timeout=20m
+
+  # Wait for an addon to be active without health issues
+  kubectl eks-viewer wait addons/vpc-cni --for=healthy
+
+  # Wait for a Fargate profile to be deleted
+  kubectl eks-viewer wait fargate-profiles/my-profile --for=delete
+
+  # Wait for the current cluster
+  kubectl eks-viewer wait cluster --for=status=ACTIVE`,
+		Args:         cobra.RangeArgs(1, 2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resourceType, name := parseWaitTarget(args)
+			o.resourceType = resourceType
+			if err := o.Validate(); err != nil {
+				return err
+			}
+
+			condition, err := parseWaitCondition(forCondition)
+			if err != nil {
+				return err
+			}
+			if _, ok := o.waiters()[resourceType]; !ok {
+				return fmt.Errorf("waiting is not supported for %s. Supported types are: addons, cluster, fargate-profiles, nodegroups", resourceType)
+			}
+			if name == "" && resourceType != "cluster" {
+				return fmt.Errorf("a name is required to wait for %s", resourceType)
+			}
+			if timeout <= 0 {
+				return fmt.Errorf("--timeout must be positive, got %s", timeout)
+			}
+
+			if err := o.Complete(); err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return o.RunWait(ctx, name, condition, defaultWaitBackoff)
+		},
+	}
+
+	cmd.Flags().StringVar(&forCondition, "for", "", "The condition to wait for: status=STATUS, healthy or delete")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Minute, "The length of time to wait before giving up")
+	_ = cmd.MarkFlagRequired("for")
+
+	return cmd
+}
+
+// parseWaitTarget accepts both "nodegroups/my-nodegroup" and
+// "nodegroups my-nodegroup".
+func parseWaitTarget(args []string) (string, string) {
+	if len(args) > 1 {
+		return args[0], args[1]
+	}
+	resourceType, name, _ := strings.Cut(args[0], "/")
+	return resourceType, name
+}
+
+// waitCondition is the parsed --for flag.
+type waitCondition struct {
+	status  string
+	healthy bool
+	deleted bool
+}
+
+func (c waitCondition) String() string {
+	switch {
+	case c.deleted:
+		return "delete"
+	case c.healthy:
+		return "healthy"
+	}
+	return "status=" + c.status
+}
+
+func parseWaitCondition(s string) (waitCondition, error) {
+	switch {
+	case s == "delete":
+		return waitCondition{deleted: true}, nil
+	case s == "healthy":
+		return waitCondition{status: "ACTIVE", healthy: true}, nil
+	case strings.HasPrefix(s, "status="):
+		if status := strings.TrimPrefix(s, "status="); status != "" {
+			return waitCondition{status: strings.ToUpper(status)}, nil
+		}
+	}
+	return waitCondition{}, fmt.Errorf("invalid --for %q, must be status=STATUS, healthy or delete", s)
+}
+
+// waitState is what is observed of a resource on each attempt.
+type waitState struct {
+	exists       bool
+	status       string
+	healthIssues int
+}
+
+func (s waitState) String() string {
+	if !s.exists {
+		return "not found"
+	}
+	return fmt.Sprintf("status %s, %d health issue(s)", s.status, s.healthIssues)
+}
+
+// met reports whether state satisfies the condition.
+func (c waitCondition) met(state waitState) bool {
+	if c.deleted {
+		return !state.exists
+	}
+	return state.exists && state.status == c.status && (!c.healthy || state.healthIssues == 0)
+}
+
+// failed reports whether state can no longer reach the condition.
+func (c waitCondition) failed(state waitState) bool {
+	if c.deleted || !state.exists {
+		return false
+	}
+	return strings.HasSuffix(state.status, "_FAILED") && state.status != c.status
+}
+
+// resourceWaiter describes a single resource for the wait command.
+type resourceWaiter func(ctx context.Context, name string) (waitState, error)
+
+func (o *Options) waiters() map[string]resourceWaiter {
+	return map[string]resourceWaiter{
+		"addons": func(ctx context.Context, name string) (waitState, error) {
+			addon, err := o.eksClient.DescribeAddon(ctx, name)
+			if err != nil {
+				return waitState{}, err
+			}
+			state := waitState{exists: true, status: string(addon.Status)}
+			if addon.Health != nil {
+				state.healthIssues = len(addon.Health.Issues)
+			}
+			return state, nil
+		},
+		"cluster": func(ctx context.Context, name string) (waitState, error) {
+			if name != "" {
+				o.eksClient.clusterName = &name
+			}
+			clusters, err := o.eksClient.DescribeCluster(ctx)
+			if err != nil {
+				return waitState{}, err
+			}
+			state := waitState{exists: true, status: string(clusters[0].Status)}
+			if clusters[0].Health != nil {
+				state.healthIssues = len(clusters[0].Health.Issues)
+			}
+			return state, nil
+		},
+		"fargate-profiles": func(ctx context.Context, name string) (waitState, error) {
+			profile, err := o.eksClient.DescribeFargateProfile(ctx, name)
+			if err != nil {
+				return waitState{}, err
+			}
+			state := waitState{exists: true, status: string(profile.Status)}
+			if profile.Health != nil {
+				state.healthIssues = len(profile.Health.Issues)
+			}
+			return state, nil
+		},
+		"nodegroups": func(ctx context.Context, name string) (waitState, error) {
+			nodegroup, err := o.eksClient.DescribeNodeGroup(ctx, name)
+			if err != nil {
+				return waitState{}, err
+			}
+			state := waitState{exists: true, status: string(nodegroup.Status)}
+			if nodegroup.Health != nil {
+				state.healthIssues = len(nodegroup.Health.Issues)
+			}
+			return state, nil
+		},
+	}
+}
+
+// RunWait describes the resource until condition is met or ctx is done. Every
+// change of the observed state is reported on stderr.
+func (o *Options) RunWait(ctx context.Context, name string, condition waitCondition, backoff waitBackoff) error {
+	describe := o.waiters()[o.resourceType]
+	target := o.resourceType
+	if name != "" {
+		target += "/" + name
+	}
+
+	start := time.Now()
+	delay := backoff.initial
+	lastState := ""
+	for {
+		state, err := describe(ctx, name)
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			state, err = waitState{}, nil
+		}
+
+		switch {
+		case ctx.Err() != nil:
+			// Reported below
+		case err != nil:
+			return &ExitError{Code: exitWaitFailed, Err: fmt.Errorf("failed to describe %s: %v", target, err)}
+		case condition.met(state):
+			fmt.Fprintf(o.Out, "%s condition met\n", target)
+			return nil
+		case condition.failed(state):
+			return &ExitError{Code: exitWaitFailed, Err: fmt.Errorf("%s is %s, condition %s can no longer be met", target, state.status, condition)}
+		}
+
+		if s := state.String(); s != lastState && ctx.Err() == nil {
+			if !o.quiet {
+				fmt.Fprintf(o.ErrOut, "Waiting for %s %s: %s (%s elapsed)\n", target, condition, s, time.Since(start).Round(time.Second))
+			}
+			lastState = s
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return &ExitError{Code: exitWaitTimeout, Err: fmt.Errorf("timed out waiting for %s %s, last seen: %s", target, condition, lastState)}
+			}
+			return &ExitError{Code: exitWaitFailed, Err: fmt.Errorf("interrupted waiting for %s %s", target, condition)}
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, backoff.max)
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+)
+
+func TestRunWait(t *testing.T) {
+	notFound := &types.ResourceNotFoundException{Message: stringPtr("No node group found")}
+
+	tests := []struct {
+		name             string
+		forCondition     string
+		statuses         []types.NodegroupStatus
+		healthIssues     int
+		notFoundAfter    int
+		expectedExitCode int
+		expectedOutput   string
+	}{
+		{
+			name:           "status reached",
+			forCondition:   "status=active",
+			statuses:       []types.NodegroupStatus{types.NodegroupStatusUpdating, types.NodegroupStatusUpdating, types.NodegroupStatusActive},
+			expectedOutput: "nodegroups/my-ng condition met",
+		},
+		{
+			name:             "healthy requires no health issues",
+			forCondition:     "healthy",
+			statuses:         []types.NodegroupStatus{types.NodegroupStatusActive},
+			healthIssues:     1,
+			expectedExitCode: exitWaitTimeout,
+		},
+		{
+			name:             "failed status ends the wait",
+			forCondition:     "status=ACTIVE",
+			statuses:         []types.NodegroupStatus{types.NodegroupStatusCreating, types.NodegroupStatusCreateFailed},
+			expectedExitCode: exitWaitFailed,
+		},
+		{
+			name:           "deleted",
+			forCondition:   "delete",
+			statuses:       []types.NodegroupStatus{types.NodegroupStatusDeleting},
+			notFoundAfter:  2,
+			expectedOutput: "nodegroups/my-ng condition met",
+		},
+		{
+			name:             "timeout",
+			forCondition:     "status=ACTIVE",
+			statuses:         []types.NodegroupStatus{types.NodegroupStatusUpdating},
+			expectedExitCode: exitWaitTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			mockClient := newMockEKSClient()
+			mockClient.describeNodegroupFunc = func(ctx context.Context, params *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error) {
+				calls++
+				if tt.notFoundAfter > 0 && calls > tt.notFoundAfter {
+					return nil, notFound
+				}
+				status := tt.statuses[min(calls, len(tt.statuses))-1]
+				issues := make([]types.Issue, tt.healthIssues)
+				return &eks.DescribeNodegroupOutput{
+					Nodegroup: &types.Nodegroup{
+						NodegroupName: params.NodegroupName,
+						Status:        status,
+						Health:        &types.NodegroupHealth{Issues: issues},
+					},
+				}, nil
+			}
+
+			o, out, errOut := newTestOptions(mockClient, "")
+			o.resourceType = "nodegroups"
+			condition, err := parseWaitCondition(tt.forCondition)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			err = o.RunWait(ctx, "my-ng", condition, waitBackoff{initial: time.Millisecond, max: 4 * time.Millisecond})
+
+			if code := ExitCode(err); code != tt.expectedExitCode {
+				t.Fatalf("expected exit code %d, got %d (%v)", tt.expectedExitCode, code, err)
+			}
+			if !strings.Contains(out.String(), tt.expectedOutput) {
+				t.Errorf("Output does not contain expected string: %s\nGot: %s", tt.expectedOutput, out.String())
+			}
+			if !strings.Contains(errOut.String(), "Waiting for nodegroups/my-ng") {
+				t.Errorf("expected progress on stderr, got: %s", errOut.String())
+			}
+		})
+	}
+}
+
+func TestParseWaitCondition(t *testing.T) {
+	for _, invalid := range []string{"", "status=", "condition=Ready"} {
+		if _, err := parseWaitCondition(invalid); err == nil {
+			t.Errorf("expected error for --for=%q", invalid)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const defaultWatchInterval = 5 * time.Second
+
+// RunWatch polls the selected resources every --interval until interrupted.
+func (o *Options) RunWatch() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return o.watchResources(ctx, isTerminal(o.Out))
+}
+
+// watchResources polls until ctx is cancelled. On a terminal the tables are
+// redrawn in place with the rows that changed highlighted. Otherwise the first
+// poll is printed in full and later polls only print the rows that appeared or
+// changed, like kubectl get --watch. JSON and YAML are printed again whenever
+// they change.
+func (o *Options) watchResources(ctx context.Context, redraw bool) error {
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+
+	isTableFormat := o.isTableFormat()
+	var previous string
+	for first := true; ; first = false {
+		buf := &bytes.Buffer{}
+		err := o.runOnce(ctx, buf)
+		if ctx.Err() != nil {
+			// Interrupted while fetching, the output is incomplete
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		current := buf.String()
+		switch {
+		case !isTableFormat:
+			if current != previous {
+				fmt.Fprint(o.Out, current)
+			}
+		case redraw:
+			// Move to the top left corner and clear the screen
+			fmt.Fprintf(o.Out, "\033[H\033[2JEvery %s: %s\n\n", o.interval, time.Now().Format(time.RFC1123))
+			if first {
+				fmt.Fprint(o.Out, current)
+			} else {
+				printHighlighted(o.Out, current, previous)
+			}
+		case first:
+			fmt.Fprint(o.Out, current)
+		default:
+			printChangedRows(o.Out, current, previous)
+		}
+		previous = current
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// tableRow is a line of table output together with the section and column
+// header it belongs to.
+type tableRow struct {
+	section string
+	columns string
+	line    string
+	// index is the line number of the row in the output
+	index int
+}
+
+// key identifies a row across polls by its section and first column. The
+// first cell ends where the second column of the header starts, since it may
+// contain spaces, e.g. insight names. Other lines, such as errors, are
+// identified by their whole content.
+func (r tableRow) key() string {
+	if r.columns == "" || strings.TrimSpace(r.line) == "" {
+		return r.section + "\x00" + normalizeRow(r.line)
+	}
+	cell := []rune(r.line)
+	if loc := columnGap.FindStringIndex(r.columns); loc != nil && loc[1]-1 < len(cell) {
+		cell = cell[:loc[1]-1]
+	}
+	return r.section + "\x00" + strings.TrimSpace(string(cell))
+}
+
+// columnGap matches the padding before the second column of a header. Column
+// names contain single spaces only.
+var columnGap = regexp.MustCompile(`  +\S`)
+
+// normalizeRow ignores the padding, which changes with the width of the
+// other rows.
+func normalizeRow(line string) string {
+	return strings.Join(strings.Fields(line), " ")
+}
+
+// parseTableRows splits table output into its rows.
+func parseTableRows(output string) []tableRow {
+	var rows []tableRow
+	var section, columns string
+	expectColumns := false
+	for i, line := range strings.Split(output, "\n") {
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "=== "):
+			section, columns = line, ""
+			expectColumns = true
+			continue
+		case expectColumns:
+			expectColumns = false
+			if line != "<none>" && !strings.HasPrefix(line, "error: ") {
+				columns = line
+				continue
+			}
+		}
+		rows = append(rows, tableRow{section: section, columns: columns, line: line, index: i})
+	}
+	return rows
+}
+
+// changedRows returns the rows of current that are new or differ from
+// previous.
+func changedRows(current, previous string) map[string]bool {
+	before := map[string]string{}
+	for _, row := range parseTableRows(previous) {
+		before[row.key()] = normalizeRow(row.line)
+	}
+
+	changed := map[string]bool{}
+	for _, row := range parseTableRows(current) {
+		if line, ok := before[row.key()]; !ok || line != normalizeRow(row.line) {
+			changed[row.key()] = true
+		}
+	}
+	return changed
+}
+
+// printChangedRows prints the rows that changed since the previous poll,
+// each group preceded by its section and column header.
+func printChangedRows(w io.Writer, current, previous string) {
+	changed := changedRows(current, previous)
+	lastHeader := ""
+	for _, row := range parseTableRows(current) {
+		if !changed[row.key()] {
+			continue
+		}
+		if header := row.section + row.columns; header != lastHeader {
+			fmt.Fprintln(w, row.section)
+			if row.columns != "" {
+				fmt.Fprintln(w, row.columns)
+			}
+			lastHeader = header
+		}
+		fmt.Fprintln(w, row.line)
+	}
+}
+
+// printHighlighted prints current with the rows that changed since the
+// previous poll in bold.
+func printHighlighted(w io.Writer, current, previous string) {
+	changed := changedRows(current, previous)
+	highlight := map[int]bool{}
+	for _, row := range parseTableRows(current) {
+		if changed[row.key()] {
+			highlight[row.index] = true
+		}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(current, "\n"), "\n")
+	for i, line := range lines {
+		if highlight[i] {
+			fmt.Fprintf(w, "\033[1m%s\033[m\n", line)
+		} else {
+			fmt.Fprintln(w, line)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+)
+
+// newUpgradingMock returns nodegroups that are UPDATING on the first poll and
+// ACTIVE on the second, and cancels the watch on the third.
+func newUpgradingMock(cancel context.CancelFunc) *mockEKSClient {
+	polls := 0
+	mockClient := newMockEKSClient()
+	mockClient.listNodegroupsFunc = func(ctx context.Context, params *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error) {
+		polls++
+		if polls == 3 {
+			cancel()
+			return nil, ctx.Err()
+		}
+		return &eks.ListNodegroupsOutput{Nodegroups: []string{"ng-stable", "ng-upgrading"}}, nil
+	}
+	mockClient.describeNodegroupFunc = func(ctx context.Context, params *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error) {
+		status := types.NodegroupStatusActive
+		if *params.NodegroupName == "ng-upgrading" && polls == 1 {
+			status = types.NodegroupStatusUpdating
+		}
+		return &eks.DescribeNodegroupOutput{
+			Nodegroup: &types.Nodegroup{
+				NodegroupName: params.NodegroupName,
+				Status:        status,
+				ScalingConfig: &types.NodegroupScalingConfig{
+					DesiredSize: int32Ptr(2),
+					MinSize:     int32Ptr(1),
+					MaxSize:     int32Ptr(3),
+				},
+				Version: stringPtr("1.31"),
+			},
+		}, nil
+	}
+	return mockClient
+}
+
+func TestWatchPrintsChangedRows(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	o, out, _ := newTestOptions(newUpgradingMock(cancel), "")
+	o.resourceType = "nodegroups"
+	o.interval = time.Millisecond
+
+	if err := o.watchResources(ctx, false); err != nil {
+		t.Fatalf("watchResources returned error: %v", err)
+	}
+
+	output := out.String()
+	if strings.Count(output, "ng-stable") != 1 {
+		t.Errorf("expected the unchanged nodegroup to be printed once\nGot: %s", output)
+	}
+	if strings.Count(output, "ng-upgrading") != 2 || !strings.Contains(output, "UPDATING") {
+		t.Errorf("expected the upgrading nodegroup to be printed again when it changed\nGot: %s", output)
+	}
+	if strings.Count(output, "=== nodegroups ===") != 2 {
+		t.Errorf("expected changed rows to be preceded by their section\nGot: %s", output)
+	}
+}
+
+func TestWatchRedrawHighlightsChanges(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	o, out, _ := newTestOptions(newUpgradingMock(cancel), "")
+	o.resourceType = "nodegroups"
+	o.interval = time.Millisecond
+
+	if err := o.watchResources(ctx, true); err != nil {
+		t.Fatalf("watchResources returned error: %v", err)
+	}
+
+	redraws := strings.Split(out.String(), "\033[H\033[2J")
+	if len(redraws) != 3 {
+		t.Fatalf("expected 2 redraws, got %d\nGot: %q", len(redraws)-1, out.String())
+	}
+	last := redraws[2]
+	if !strings.Contains(last, "\033[1mng-upgrading") {
+		t.Errorf("expected the changed row to be highlighted\nGot: %q", last)
+	}
+	if strings.Contains(last, "\033[1mng-stable") {
+		t.Errorf("expected the unchanged row not to be highlighted\nGot: %q", last)
+	}
+}
+
+func TestChangedRowsNamesWithSpaces(t *testing.T) {
+	output := "=== insights ===\n" +
+		"NAME                                          CATEGORY             STATUS\n" +
+		"Deprecated APIs removed in Kubernetes v1.32   UPGRADE_READINESS    PASSING\n" +
+		"Deprecated APIs removed in Kubernetes v1.33   UPGRADE_READINESS    WARNING\n"
+
+	if changed := changedRows(output, output); len(changed) != 0 {
+		t.Errorf("expected no changed rows, got %v", changed)
+	}
+}
+
+func TestChangedRowsIgnoresPadding(t *testing.T) {
+	previous := "=== addons ===\nNAME    VERSION\ncoredns v1.11\n"
+	current := "=== addons ===\nNAME           VERSION\ncoredns        v1.11\nkube-proxy-v2  v1.31\n"
+
+	changed := changedRows(current, previous)
+	if len(changed) != 1 {
+		t.Errorf("expected only the new row to have changed, got %v", changed)
+	}
+}
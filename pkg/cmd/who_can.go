@@ -0,0 +1,440 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/spf13/cobra"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+// accessPolicyClusterRoles maps the access policies of the EKS catalog to the
+// Kubernetes ClusterRole they grant the permissions of.
+var accessPolicyClusterRoles = map[string]string{
+	"AmazonEKSClusterAdminPolicy": "cluster-admin",
+	"AmazonEKSAdminPolicy":        "admin",
+	"AmazonEKSEditPolicy":         "edit",
+	"AmazonEKSViewPolicy":         "view",
+}
+
+// accessPolicyRules are the permissions of the access policies of the EKS
+// catalog that have no equivalent ClusterRole.
+var accessPolicyRules = map[string][]rbacv1.PolicyRule{
+	"AmazonEKSAdminViewPolicy": {{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"get", "list", "watch"}}},
+}
+
+// Ways a principal is granted access
+const (
+	grantAccessPolicy       = "AccessPolicy"
+	grantClusterRoleBinding = "ClusterRoleBinding"
+	grantRoleBinding        = "RoleBinding"
+)
+
+// Grant is an access policy or RBAC binding that gives a principal access.
+type Grant struct {
+	PrincipalArn string
+	// Via is AccessPolicy, ClusterRoleBinding or RoleBinding
+	Via  string
+	Name string
+	// Subject is the access scope of a policy, or the group or user of the
+	// principal a binding refers to
+	Subject string
+	// Role is the role whose rules grant the access
+	Role string
+}
+
+// WhoCan is the output of the who-can command.
+type WhoCan struct {
+	metav1.TypeMeta
+	Namespace string          `json:"namespace"`
+	Verb      string          `json:"verb"`
+	Resource  string          `json:"resource"`
+	Errors    []ResourceError `json:"errors,omitempty"`
+	// UnknownPolicies are the access policies associated with principals
+	// whose permissions are not known
+	UnknownPolicies []string `json:"unknownPolicies,omitempty"`
+	Items           []Grant  `json:"items"`
+}
+
+// Implement runtime.Object interface
+func (w *WhoCan) GetObjectKind() schema.ObjectKind {
+	return &w.TypeMeta
+}
+
+func (w *WhoCan) DeepCopyObject() runtime.Object {
+	copied := *w
+	copied.Errors = append([]ResourceError(nil), w.Errors...)
+	copied.UnknownPolicies = append([]string(nil), w.UnknownPolicies...)
+	copied.Items = append([]Grant(nil), w.Items...)
+	return &copied
+}
+
+// accessRequest is the verb and resource of a who-can query.
+type accessRequest struct {
+	verb string
+	// group is empty when the resource is not qualified by its API group
+	group    string
+	resource string
+}
+
+// parseAccessRequest parses a resource such as pods, deployments.apps or
+// pods/log.
+func parseAccessRequest(verb, resource string) accessRequest {
+	name, subresource, hasSubresource := strings.Cut(resource, "/")
+	name, group, _ := strings.Cut(name, ".")
+	if hasSubresource {
+		name += "/" + subresource
+	}
+	return accessRequest{verb: verb, group: group, resource: name}
+}
+
+// allows reports whether one of rules grants the request on every object of
+// the resource. Rules restricted to resource names are not.
+func (r accessRequest) allows(rules []rbacv1.PolicyRule) bool {
+	for _, rule := range rules {
+		if len(rule.ResourceNames) > 0 {
+			continue
+		}
+		if !slices.Contains(rule.Verbs, r.verb) && !slices.Contains(rule.Verbs, rbacv1.VerbAll) {
+			continue
+		}
+		if r.group != "" && !slices.Contains(rule.APIGroups, r.group) && !slices.Contains(rule.APIGroups, rbacv1.APIGroupAll) {
+			continue
+		}
+		if slices.Contains(rule.Resources, r.resource) {
+			return true
+		}
+		// The resources of every group only include an unqualified resource
+		// for the core group
+		if slices.Contains(rule.Resources, rbacv1.ResourceAll) &&
+			(r.group != "" || slices.Contains(rule.APIGroups, "") || slices.Contains(rule.APIGroups, rbacv1.APIGroupAll)) {
+			return true
+		}
+	}
+	return false
+}
+
+func NewWhoCanCmd(o *Options) *cobra.Command {
+	printFlags := genericclioptions.NewPrintFlags("")
+
+	cmd := &cobra.Command{
+		Use:   "who-can VERB RESOURCE",
+		Short: "List the IAM principals with access to a Kubernetes resource",
+		Long: `List the IAM principals of the access entries that can perform VERB on
+RESOURCE in the namespace of --namespace, through their access policies or
+through Kubernetes RBAC bindings to their groups or username.
+
+RESOURCE may be qualified with its API group, e.g. deployments.apps.
+Unqualified resources match the rules naming them in any API group, and the
+rules for every resource of the core group. The access policies of the EKS
+catalog are evaluated as the ClusterRole they correspond to, e.g.
+AmazonEKSEditPolicy as edit.`,
+		Example: `  # List the principals that can read secrets in the payments namespace
+  kubectl eks-viewer who-can get secrets -n payments
+
+  # List the principals that can delete deployments in the default namespace
+  kubectl eks-viewer who-can delete deployments.apps -o json`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.Validate(); err != nil {
+				return err
+			}
+			if err := o.validateLiveCluster("who-can"); err != nil {
+				return err
+			}
+			o.printFlags = printFlags
+
+			if err := o.Complete(); err != nil {
+				return err
+			}
+			namespace, _, err := o.configFlags.ToRawKubeConfigLoader().Namespace()
+			if err != nil {
+				return err
+			}
+			if err := o.ensureKubernetesClient(); err != nil {
+				return err
+			}
+			return o.RunWhoCan(context.Background(), namespace, args[0], args[1])
+		},
+	}
+
+	printFlags.AddFlags(cmd)
+
+	return cmd
+}
+
+// RunWhoCan prints the principals that can perform verb on resource in
+// namespace.
+func (o *Options) RunWhoCan(ctx context.Context, namespace, verb, resource string) error {
+	whoCan, err := o.buildWhoCan(ctx, namespace, verb, resource)
+	if err != nil {
+		return err
+	}
+	return o.printReport(whoCan, whoCan.Errors, NewWhoCanPrinter)
+}
+
+func (o *Options) buildWhoCan(ctx context.Context, namespace, verb, resource string) (*WhoCan, error) {
+	whoCan := &WhoCan{
+		TypeMeta:  metav1.TypeMeta{APIVersion: "v1", Kind: "EksWhoCan"},
+		Namespace: namespace,
+		Verb:      verb,
+		Resource:  resource,
+		Items:     []Grant{},
+	}
+
+	catalog, err := o.eksClient.ListAccessPolicies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access policies: %v", err)
+	}
+	policyNames := map[string]string{}
+	for _, policy := range catalog {
+		policyNames[aws.ToString(policy.Arn)] = aws.ToString(policy.Name)
+	}
+
+	entries, err := o.eksClient.ListAccessEntries(ctx)
+	if err != nil && !isPartialError(err) {
+		return nil, fmt.Errorf("failed to list access entries: %v", err)
+	}
+	if err != nil {
+		whoCan.Errors = append(whoCan.Errors, toResourceErrors("access-entries", err)...)
+	}
+	policies, err := o.eksClient.listAccessEntryPolicies(ctx, entries)
+	if err != nil && !isPartialError(err) {
+		return nil, err
+	}
+	if err != nil {
+		whoCan.Errors = append(whoCan.Errors, toResourceErrors("access-entries", err)...)
+	}
+
+	rbac, err := o.listRBAC(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	request := parseAccessRequest(verb, resource)
+	unknown := map[string]bool{}
+	for _, entry := range entries {
+		arn := aws.ToString(entry.PrincipalArn)
+
+		for _, policy := range policies[arn] {
+			if !accessScopeIncludes(policy.AccessScope, namespace) {
+				continue
+			}
+			name, ok := policyNames[aws.ToString(policy.PolicyArn)]
+			if !ok {
+				name = aws.ToString(policy.PolicyArn)
+			}
+			role, rules, known := rbac.accessPolicyRules(name)
+			if !known {
+				unknown[name] = true
+				continue
+			}
+			if request.allows(rules) {
+				whoCan.Items = append(whoCan.Items, Grant{PrincipalArn: arn, Via: grantAccessPolicy, Name: name, Subject: formatAccessScope(policy.AccessScope), Role: role})
+			}
+		}
+
+		for _, grant := range rbac.grants(request, entry) {
+			grant.PrincipalArn = arn
+			whoCan.Items = append(whoCan.Items, grant)
+		}
+	}
+
+	for name := range unknown {
+		whoCan.UnknownPolicies = append(whoCan.UnknownPolicies, name)
+	}
+	sort.Strings(whoCan.UnknownPolicies)
+	sort.SliceStable(whoCan.Items, func(i, j int) bool {
+		a, b := whoCan.Items[i], whoCan.Items[j]
+		if a.PrincipalArn != b.PrincipalArn {
+			return a.PrincipalArn < b.PrincipalArn
+		}
+		if a.Via != b.Via {
+			return a.Via < b.Via
+		}
+		return a.Name < b.Name
+	})
+	return whoCan, nil
+}
+
+// accessScopeIncludes reports whether an access policy associated with scope
+// applies to namespace. Namespaces of the scope may end with a * wildcard.
+func accessScopeIncludes(scope *types.AccessScope, namespace string) bool {
+	if scope == nil || scope.Type != types.AccessScopeTypeNamespace {
+		return true
+	}
+	return newNameMatcher(scope.Namespaces).Matches(namespace)
+}
+
+func formatAccessScope(scope *types.AccessScope) string {
+	if scope == nil || scope.Type != types.AccessScopeTypeNamespace {
+		return "cluster"
+	}
+	return "namespaces " + strings.Join(scope.Namespaces, ",")
+}
+
+// rbacObjects are the roles and bindings that apply to a namespace.
+type rbacObjects struct {
+	clusterRoles        map[string][]rbacv1.PolicyRule
+	roles               map[string][]rbacv1.PolicyRule
+	clusterRoleBindings []rbacv1.ClusterRoleBinding
+	roleBindings        []rbacv1.RoleBinding
+}
+
+// listRBAC lists the ClusterRoles and ClusterRoleBindings of the cluster, and
+// the Roles and RoleBindings of namespace.
+func (o *Options) listRBAC(ctx context.Context, namespace string) (*rbacObjects, error) {
+	rbac := o.kubeClient.RbacV1()
+	clusterRoles, err := listAllPages(ctx, metav1.ListOptions{}, func(ctx context.Context, options metav1.ListOptions) ([]rbacv1.ClusterRole, string, error) {
+		list, err := rbac.ClusterRoles().List(ctx, options)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster roles: %v", err)
+	}
+	roles, err := listAllPages(ctx, metav1.ListOptions{}, func(ctx context.Context, options metav1.ListOptions) ([]rbacv1.Role, string, error) {
+		list, err := rbac.Roles(namespace).List(ctx, options)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %v", err)
+	}
+
+	objects := &rbacObjects{clusterRoles: map[string][]rbacv1.PolicyRule{}, roles: map[string][]rbacv1.PolicyRule{}}
+	for _, role := range clusterRoles {
+		objects.clusterRoles[role.Name] = role.Rules
+	}
+	for _, role := range roles {
+		objects.roles[role.Name] = role.Rules
+	}
+
+	objects.clusterRoleBindings, err = listAllPages(ctx, metav1.ListOptions{}, func(ctx context.Context, options metav1.ListOptions) ([]rbacv1.ClusterRoleBinding, string, error) {
+		list, err := rbac.ClusterRoleBindings().List(ctx, options)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster role bindings: %v", err)
+	}
+	objects.roleBindings, err = listAllPages(ctx, metav1.ListOptions{}, func(ctx context.Context, options metav1.ListOptions) ([]rbacv1.RoleBinding, string, error) {
+		list, err := rbac.RoleBindings(namespace).List(ctx, options)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role bindings: %v", err)
+	}
+	return objects, nil
+}
+
+// accessPolicyRules returns the role and rules of the access policy name,
+// known is not set for the policies whose permissions are not known.
+func (r *rbacObjects) accessPolicyRules(name string) (role string, rules []rbacv1.PolicyRule, known bool) {
+	if clusterRole, ok := accessPolicyClusterRoles[name]; ok {
+		return "ClusterRole/" + clusterRole, r.clusterRoles[clusterRole], true
+	}
+	if rules, ok := accessPolicyRules[name]; ok {
+		return name, rules, true
+	}
+	return "", nil, false
+}
+
+// grants returns the bindings to the groups or username of entry whose role
+// allows request.
+func (r *rbacObjects) grants(request accessRequest, entry AccessEntry) []Grant {
+	// Every authenticated principal is in system:authenticated
+	groups := sortedUnion(entry.KubernetesGroups, nodeAccessEntryGroups[aws.ToString(entry.Type)])
+	groups = sortedUnion(groups, []string{"system:authenticated"})
+	username := aws.ToString(entry.Username)
+
+	subjectOf := func(subjects []rbacv1.Subject) (string, bool) {
+		for _, subject := range subjects {
+			switch {
+			case subject.Kind == rbacv1.GroupKind && slices.Contains(groups, subject.Name):
+				return "Group/" + subject.Name, true
+			// Usernames with a {{SessionName}} template cannot be matched
+			case subject.Kind == rbacv1.UserKind && subject.Name == username && !strings.Contains(username, "{{"):
+				return "User/" + subject.Name, true
+			}
+		}
+		return "", false
+	}
+	rulesOf := func(ref rbacv1.RoleRef) []rbacv1.PolicyRule {
+		if ref.Kind == "Role" {
+			return r.roles[ref.Name]
+		}
+		return r.clusterRoles[ref.Name]
+	}
+
+	var grants []Grant
+	for _, binding := range r.clusterRoleBindings {
+		if subject, ok := subjectOf(binding.Subjects); ok && request.allows(rulesOf(binding.RoleRef)) {
+			grants = append(grants, Grant{Via: grantClusterRoleBinding, Name: binding.Name, Subject: subject, Role: binding.RoleRef.Kind + "/" + binding.RoleRef.Name})
+		}
+	}
+	for _, binding := range r.roleBindings {
+		if subject, ok := subjectOf(binding.Subjects); ok && request.allows(rulesOf(binding.RoleRef)) {
+			grants = append(grants, Grant{Via: grantRoleBinding, Name: binding.Name, Subject: subject, Role: binding.RoleRef.Kind + "/" + binding.RoleRef.Name})
+		}
+	}
+	return grants
+}
+
+func NewWhoCanPrinter(options printers.PrintOptions) printers.ResourcePrinter {
+	return printers.ResourcePrinterFunc(func(obj runtime.Object, w io.Writer) error {
+		whoCan, ok := obj.(*WhoCan)
+		if !ok {
+			return fmt.Errorf("expected *WhoCan, got %T", obj)
+		}
+
+		table := &metav1.Table{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "Grant",
+			},
+			ColumnDefinitions: []metav1.TableColumnDefinition{
+				{Name: "PRINCIPAL ARN", Type: "string"},
+				{Name: "VIA", Type: "string"},
+				{Name: "NAME", Type: "string"},
+				{Name: "SUBJECT", Type: "string"},
+				{Name: "ROLE", Type: "string"},
+			},
+		}
+		for _, item := range whoCan.Items {
+			table.Rows = append(table.Rows, metav1.TableRow{
+				Cells: []interface{}{item.PrincipalArn, item.Via, item.Name, item.Subject, item.Role},
+			})
+		}
+
+		header := fmt.Sprintf("who can %s %s in namespace %s", whoCan.Verb, whoCan.Resource, whoCan.Namespace)
+		if err := printTable(w, table, header, options); err != nil {
+			return err
+		}
+		if len(whoCan.UnknownPolicies) > 0 {
+			fmt.Fprintf(w, "\nThe permissions of these access policies are not known: %s\n", strings.Join(whoCan.UnknownPolicies, ", "))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAccessRequestAllows(t *testing.T) {
+	rules := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"pods", "pods/log"}, Verbs: []string{"get", "list"}},
+		{APIGroups: []string{"apps"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}, ResourceNames: []string{"token"}},
+	}
+
+	tests := []struct {
+		verb     string
+		resource string
+		expected bool
+	}{
+		{verb: "get", resource: "pods", expected: true},
+		{verb: "delete", resource: "pods"},
+		{verb: "get", resource: "pods/log", expected: true},
+		{verb: "delete", resource: "deployments.apps", expected: true},
+		{verb: "delete", resource: "deployments"},
+		{verb: "delete", resource: "deployments.extensions"},
+		{verb: "get", resource: "secrets"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.verb+" "+tt.resource, func(t *testing.T) {
+			if got := parseAccessRequest(tt.verb, tt.resource).allows(rules); got != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, got)
+			}
+		})
+	}
+}
+
+const (
+	adminsArn     = "arn:aws:iam::123456789012:role/admins"
+	developersArn = "arn:aws:iam::123456789012:role/developers"
+	auditorsArn   = "arn:aws:iam::123456789012:role/auditors"
+	deployerArn   = "arn:aws:iam::123456789012:user/deployer"
+)
+
+// newWhoCanMock has access entries for the admins (cluster admin), the
+// developers (edit in dev-*, developers group), the auditors (an unknown
+// policy) and a deployer user.
+func newWhoCanMock() *mockEKSClient {
+	entries := map[string]types.AccessEntry{
+		adminsArn:     {Type: stringPtr("STANDARD")},
+		developersArn: {Type: stringPtr("STANDARD"), KubernetesGroups: []string{"developers"}},
+		auditorsArn:   {Type: stringPtr("STANDARD")},
+		deployerArn:   {Type: stringPtr("STANDARD"), Username: stringPtr("deployer")},
+	}
+	policies := map[string][]types.AssociatedAccessPolicy{
+		adminsArn: {{
+			PolicyArn:   stringPtr("arn:aws:eks::aws:cluster-access-policy/AmazonEKSClusterAdminPolicy"),
+			AccessScope: &types.AccessScope{Type: types.AccessScopeTypeCluster},
+		}},
+		developersArn: {{
+			PolicyArn:   stringPtr("arn:aws:eks::aws:cluster-access-policy/AmazonEKSEditPolicy"),
+			AccessScope: &types.AccessScope{Type: types.AccessScopeTypeNamespace, Namespaces: []string{"dev-*"}},
+		}},
+		auditorsArn: {{
+			PolicyArn:   stringPtr("arn:aws:eks::aws:cluster-access-policy/AmazonEKSAuditPolicy"),
+			AccessScope: &types.AccessScope{Type: types.AccessScopeTypeCluster},
+		}},
+	}
+
+	mockClient := newMockEKSClient()
+	mockClient.listAccessPoliciesFunc = func(ctx context.Context, params *eks.ListAccessPoliciesInput) (*eks.ListAccessPoliciesOutput, error) {
+		var catalog []types.AccessPolicy
+		for _, name := range []string{"AmazonEKSClusterAdminPolicy", "AmazonEKSEditPolicy", "AmazonEKSAuditPolicy"} {
+			catalog = append(catalog, types.AccessPolicy{Name: stringPtr(name), Arn: stringPtr("arn:aws:eks::aws:cluster-access-policy/" + name)})
+		}
+		return &eks.ListAccessPoliciesOutput{AccessPolicies: catalog}, nil
+	}
+	mockClient.listAccessEntriesFunc = func(ctx context.Context, params *eks.ListAccessEntriesInput) (*eks.ListAccessEntriesOutput, error) {
+		var arns []string
+		for arn := range entries {
+			arns = append(arns, arn)
+		}
+		return &eks.ListAccessEntriesOutput{AccessEntries: arns}, nil
+	}
+	mockClient.describeAccessEntryFunc = func(ctx context.Context, params *eks.DescribeAccessEntryInput) (*eks.DescribeAccessEntryOutput, error) {
+		entry := entries[*params.PrincipalArn]
+		entry.PrincipalArn = params.PrincipalArn
+		return &eks.DescribeAccessEntryOutput{AccessEntry: &entry}, nil
+	}
+	mockClient.listAssociatedAccessPoliciesFunc = func(ctx context.Context, params *eks.ListAssociatedAccessPoliciesInput) (*eks.ListAssociatedAccessPoliciesOutput, error) {
+		return &eks.ListAssociatedAccessPoliciesOutput{AssociatedAccessPolicies: policies[*params.PrincipalArn]}, nil
+	}
+	return mockClient
+}
+
+func newWhoCanClientset() *fake.Clientset {
+	secretsRule := rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get", "list"}}
+	return fake.NewSimpleClientset(
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin"},
+			Rules:      []rbacv1.PolicyRule{{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}},
+		},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "edit"}, Rules: []rbacv1.PolicyRule{secretsRule}},
+		&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Namespace: "dev-1", Name: "secret-reader"}, Rules: []rbacv1.PolicyRule{secretsRule}},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "dev-1", Name: "deployer-secrets"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "deployer"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "secret-reader"},
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "developers-secrets"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "developers"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "edit"},
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "developers-edit"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "developers"}},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "edit"},
+		},
+	)
+}
+
+func TestRunWhoCan(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		verb      string
+		resource  string
+		expected  []string
+	}{
+		{
+			name:      "namespace scope",
+			namespace: "dev-1",
+			verb:      "get",
+			resource:  "secrets",
+			expected: []string{
+				"admins AccessPolicy AmazonEKSClusterAdminPolicy cluster ClusterRole/cluster-admin",
+				"developers AccessPolicy AmazonEKSEditPolicy namespaces dev-* ClusterRole/edit",
+				"developers ClusterRoleBinding developers-edit Group/developers ClusterRole/edit",
+				"deployer RoleBinding deployer-secrets User/deployer Role/secret-reader",
+			},
+		},
+		{
+			name:      "outside the namespace scope",
+			namespace: "prod",
+			verb:      "list",
+			resource:  "secrets",
+			expected: []string{
+				"admins AccessPolicy AmazonEKSClusterAdminPolicy cluster ClusterRole/cluster-admin",
+				"developers ClusterRoleBinding developers-edit Group/developers ClusterRole/edit",
+				"developers RoleBinding developers-secrets Group/developers ClusterRole/edit",
+			},
+		},
+		{
+			name:      "only cluster admin",
+			namespace: "dev-1",
+			verb:      "delete",
+			resource:  "nodes",
+			expected: []string{
+				"admins AccessPolicy AmazonEKSClusterAdminPolicy cluster ClusterRole/cluster-admin",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, out, _ := newTestOptions(newWhoCanMock(), "json")
+			o.kubeClient = newWhoCanClientset()
+
+			if err := o.RunWhoCan(context.Background(), tt.namespace, tt.verb, tt.resource); err != nil {
+				t.Fatalf("RunWhoCan returned error: %v", err)
+			}
+
+			whoCan := &WhoCan{}
+			if err := json.Unmarshal(out.Bytes(), whoCan); err != nil {
+				t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+			}
+			if whoCan.Kind != "EksWhoCan" || strings.Join(whoCan.UnknownPolicies, ",") != "AmazonEKSAuditPolicy" {
+				t.Errorf("unexpected who-can: %+v", whoCan)
+			}
+
+			var got []string
+			for _, item := range whoCan.Items {
+				principal := item.PrincipalArn[strings.LastIndex(item.PrincipalArn, "/")+1:]
+				got = append(got, strings.Join([]string{principal, item.Via, item.Name, item.Subject, item.Role}, " "))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("expected grants:\n%s\ngot:\n%s", strings.Join(tt.expected, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+// Package filter matches AWS resource tags with Kubernetes label selector
+// syntax.
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Operator is the comparison made by a Requirement.
+type Operator string
+
+const (
+	Equals       Operator = "="
+	NotEquals    Operator = "!="
+	In           Operator = "in"
+	NotIn        Operator = "notin"
+	Exists       Operator = "exists"
+	DoesNotExist Operator = "!"
+)
+
+// Requirement is a single comparison against one tag key.
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string
+}
+
+// Matches reports whether tags satisfy the requirement. As with Kubernetes
+// labels, != and notin match resources that do not have the tag at all.
+func (r Requirement) Matches(tags map[string]string) bool {
+	value, ok := tags[r.Key]
+	switch r.Operator {
+	case Equals, In:
+		return ok && contains(r.Values, value)
+	case NotEquals, NotIn:
+		return !ok || !contains(r.Values, value)
+	case Exists:
+		return ok
+	case DoesNotExist:
+		return !ok
+	}
+	return false
+}
+
+func (r Requirement) String() string {
+	switch r.Operator {
+	case Exists:
+		return r.Key
+	case DoesNotExist:
+		return "!" + r.Key
+	case In, NotIn:
+		return fmt.Sprintf("%s %s (%s)", r.Key, r.Operator, strings.Join(r.Values, ","))
+	}
+	return r.Key + string(r.Operator) + r.Values[0]
+}
+
+// Selector is a set of requirements that must all match. The zero Selector
+// matches everything.
+type Selector struct {
+	requirements []Requirement
+}
+
+var setPattern = regexp.MustCompile(`^(.+?)\s+(in|notin)\s*\((.*)\)$`)
+
+// Parse parses a selector such as "team=payments,env!=dev,tier in (web,api)".
+// Unlike Kubernetes label keys, AWS tag keys may contain spaces and colons,
+// e.g. aws:cloudformation:stack-name, so keys are not validated further.
+func Parse(selector string) (Selector, error) {
+	var s Selector
+	for _, term := range splitTerms(selector) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		req, err := parseRequirement(term)
+		if err != nil {
+			return Selector{}, fmt.Errorf("invalid selector %q: %v", selector, err)
+		}
+		s.requirements = append(s.requirements, req)
+	}
+	return s, nil
+}
+
+func parseRequirement(term string) (Requirement, error) {
+	var req Requirement
+	switch {
+	case strings.HasPrefix(term, "!"):
+		req = Requirement{Key: term[1:], Operator: DoesNotExist}
+	case setPattern.MatchString(term):
+		m := setPattern.FindStringSubmatch(term)
+		req = Requirement{Key: m[1], Operator: Operator(m[2])}
+		for _, value := range strings.Split(m[3], ",") {
+			req.Values = append(req.Values, strings.TrimSpace(value))
+		}
+	case strings.Contains(term, "!="):
+		key, value, _ := strings.Cut(term, "!=")
+		req = Requirement{Key: key, Operator: NotEquals, Values: []string{value}}
+	case strings.Contains(term, "=="):
+		key, value, _ := strings.Cut(term, "==")
+		req = Requirement{Key: key, Operator: Equals, Values: []string{value}}
+	case strings.Contains(term, "="):
+		key, value, _ := strings.Cut(term, "=")
+		req = Requirement{Key: key, Operator: Equals, Values: []string{value}}
+	default:
+		req = Requirement{Key: term, Operator: Exists}
+	}
+
+	req.Key = strings.TrimSpace(req.Key)
+	if req.Key == "" {
+		return Requirement{}, fmt.Errorf("missing tag key in %q", term)
+	}
+	for i := range req.Values {
+		req.Values[i] = strings.TrimSpace(req.Values[i])
+	}
+	return req, nil
+}
+
+// splitTerms splits a selector on the commas that are not inside a set.
+func splitTerms(selector string) []string {
+	var terms []string
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(terms, selector[start:])
+}
+
+// Empty reports whether the selector has no requirements.
+func (s Selector) Empty() bool {
+	return len(s.requirements) == 0
+}
+
+// Requirements returns the requirements of the selector.
+func (s Selector) Requirements() []Requirement {
+	return s.requirements
+}
+
+// Matches reports whether tags satisfy every requirement.
+func (s Selector) Matches(tags map[string]string) bool {
+	for _, req := range s.requirements {
+		if !req.Matches(tags) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s Selector) String() string {
+	terms := make([]string, 0, len(s.requirements))
+	for _, req := range s.requirements {
+		terms = append(terms, req.String())
+	}
+	return strings.Join(terms, ",")
+}
+
+// ByTags returns the items whose tags match selector.
+func ByTags[T any](items []T, selector Selector, tagsOf func(T) map[string]string) []T {
+	if selector.Empty() {
+		return items
+	}
+
+	var matched []T
+	for _, item := range items {
+		if selector.Matches(tagsOf(item)) {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
+
+// FormatTags formats tags as sorted key=value pairs, or <none>.
+func FormatTags(tags map[string]string) string {
+	if len(tags) == 0 {
+		return "<none>"
+	}
+	pairs := make([]string, 0, len(tags))
+	for k, v := range tags {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name          string
+		selector      string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "equality",
+			selector: "team=payments,env!=dev",
+			expected: "team=payments,env!=dev",
+		},
+		{
+			name:     "double equals",
+			selector: "team==payments",
+			expected: "team=payments",
+		},
+		{
+			name:     "sets keep their commas",
+			selector: "env in (prod, staging),tier notin (batch)",
+			expected: "env in (prod,staging),tier notin (batch)",
+		},
+		{
+			name:     "existence",
+			selector: "cost-center,!temporary",
+			expected: "cost-center,!temporary",
+		},
+		{
+			name:     "AWS tag keys with colons",
+			selector: "aws:cloudformation:stack-name=eks-prod",
+			expected: "aws:cloudformation:stack-name=eks-prod",
+		},
+		{
+			name:     "empty",
+			selector: "",
+			expected: "",
+		},
+		{
+			name:          "missing key",
+			selector:      "=payments",
+			expectedError: "missing tag key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.selector)
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Fatalf("expected error containing %q, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := s.String(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSelectorMatches(t *testing.T) {
+	tags := map[string]string{
+		"team":        "payments",
+		"env":         "prod",
+		"cost-center": "1234",
+	}
+
+	tests := []struct {
+		selector string
+		tags     map[string]string
+		expected bool
+	}{
+		{selector: "", tags: tags, expected: true},
+		{selector: "team=payments", tags: tags, expected: true},
+		{selector: "team=payments,env!=dev", tags: tags, expected: true},
+		{selector: "team=payments,env=dev", tags: tags, expected: false},
+		{selector: "env in (prod,staging)", tags: tags, expected: true},
+		{selector: "env notin (prod,staging)", tags: tags, expected: false},
+		{selector: "cost-center", tags: tags, expected: true},
+		{selector: "!cost-center", tags: tags, expected: false},
+		// As with Kubernetes labels, negative requirements match untagged resources
+		{selector: "env!=dev", tags: nil, expected: true},
+		{selector: "env notin (dev)", tags: nil, expected: true},
+		{selector: "team=payments", tags: nil, expected: false},
+		{selector: "team", tags: nil, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			s, err := Parse(tt.selector)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := s.Matches(tt.tags); got != tt.expected {
+				t.Errorf("expected %v for tags %v, got %v", tt.expected, tt.tags, got)
+			}
+		})
+	}
+}
+
+func TestByTags(t *testing.T) {
+	type resource struct {
+		name string
+		tags map[string]string
+	}
+	items := []resource{
+		{name: "a", tags: map[string]string{"team": "payments"}},
+		{name: "b", tags: map[string]string{"team": "search"}},
+		{name: "c"},
+	}
+	tagsOf := func(r resource) map[string]string { return r.tags }
+
+	s, err := Parse("team=payments")
+	if err != nil {
+		t.Fatal(err)
+	}
+	matched := ByTags(items, s, tagsOf)
+	if len(matched) != 1 || matched[0].name != "a" {
+		t.Errorf("expected only a, got %+v", matched)
+	}
+
+	if matched := ByTags(items, Selector{}, tagsOf); len(matched) != len(items) {
+		t.Errorf("expected an empty selector to match everything, got %+v", matched)
+	}
+}
+
+func TestFormatTags(t *testing.T) {
+	if got := FormatTags(map[string]string{"team": "payments", "env": "prod"}); got != "env=prod,team=payments" {
+		t.Errorf("expected sorted tags, got %q", got)
+	}
+	if got := FormatTags(nil); got != "<none>" {
+		t.Errorf("expected <none>, got %q", got)
+	}
+}